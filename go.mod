@@ -3,8 +3,15 @@ module simple.http.server
 go 1.22
 
 require (
+	github.com/andybalholm/brotli v1.1.0
 	github.com/fsnotify/fsnotify v1.7.0
 	github.com/google/uuid v1.6.0
+	github.com/rwcarlsen/goexif v0.0.0-20190401172101-9e8deecbddbd
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
+	github.com/yeka/zip v0.0.0-20231116150916-03d6312748a9
 )
 
-require golang.org/x/sys v0.4.0 // indirect
+require (
+	golang.org/x/crypto v0.21.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+)
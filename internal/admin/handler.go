@@ -2,13 +2,21 @@ package admin
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
-	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"simple.http.server/internal/config"
+	"simple.http.server/internal/netutil"
 	"simple.http.server/internal/proxy"
+	"simple.http.server/internal/requestlog"
 
 	"github.com/google/uuid"
 )
@@ -17,6 +25,21 @@ import (
 type Handler struct {
 	config       *config.Config
 	proxyManager *proxy.ProxyManager
+	watcher      Watcher // optional, see SetWatcher
+	buildInfo    BuildInfo
+	requestLog   *requestlog.Log // optional, see SetRequestLog
+}
+
+// BuildInfo describes the running binary
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+}
+
+// Watcher is restarted when the served directory changes
+type Watcher interface {
+	RestartWatcher()
 }
 
 // NewHandler creates a new admin handler
@@ -27,6 +50,21 @@ func NewHandler(cfg *config.Config, pm *proxy.ProxyManager) *Handler {
 	}
 }
 
+// SetWatcher sets the file watcher restarted when the served directory is changed
+func (h *Handler) SetWatcher(w Watcher) {
+	h.watcher = w
+}
+
+// SetBuildInfo sets the build details reported by the version endpoint
+func (h *Handler) SetBuildInfo(info BuildInfo) {
+	h.buildInfo = info
+}
+
+// SetRequestLog sets the log of recent requests served by the logs endpoint
+func (h *Handler) SetRequestLog(l *requestlog.Log) {
+	h.requestLog = l
+}
+
 // ServeHTTP routes admin API requests
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// CORS headers
@@ -44,8 +82,12 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	switch {
 	case path == "/proxies" && r.Method == http.MethodGet:
 		h.listProxies(w, r)
+	case path == "/proxies/health" && r.Method == http.MethodGet:
+		h.proxyHealth(w, r)
 	case path == "/proxies" && r.Method == http.MethodPost:
 		h.addProxy(w, r)
+	case path == "/proxies/test" && r.Method == http.MethodPost:
+		h.testProxy(w, r)
 	case strings.HasPrefix(path, "/proxies/") && r.Method == http.MethodPut:
 		id := strings.TrimPrefix(path, "/proxies/")
 		h.updateProxy(w, r, id)
@@ -56,13 +98,46 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.exportSettings(w, r)
 	case path == "/settings/import" && r.Method == http.MethodPost:
 		h.importSettings(w, r)
+	case path == "/settings/reload" && r.Method == http.MethodPost:
+		h.reloadSettings(w, r)
 	case path == "/settings" && r.Method == http.MethodGet:
 		h.getSettings(w, r)
+	case path == "/settings" && r.Method == http.MethodPut:
+		h.updateSettings(w, r)
+	case path == "/version" && r.Method == http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(h.buildInfo)
+	case path == "/logs" && r.Method == http.MethodGet:
+		h.listLogs(w, r)
 	default:
 		http.Error(w, "Not found", http.StatusNotFound)
 	}
 }
 
+// defaultLogLimit is the number of requests returned by the logs endpoint when no limit is given
+const defaultLogLimit = 100
+
+// listLogs returns the most recent requests, newest first
+func (h *Handler) listLogs(w http.ResponseWriter, r *http.Request) {
+	limit := defaultLogLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			http.Error(w, "Query parameter 'limit' must be a positive number", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	entries := []requestlog.Entry{}
+	if h.requestLog != nil {
+		entries = h.requestLog.Recent(limit)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
 // listProxies returns all proxy rules
 func (h *Handler) listProxies(w http.ResponseWriter, r *http.Request) {
 	rules := h.config.GetProxyRules()
@@ -70,6 +145,13 @@ func (h *Handler) listProxies(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(rules)
 }
 
+// proxyHealth checks whether each proxy target is reachable
+func (h *Handler) proxyHealth(w http.ResponseWriter, r *http.Request) {
+	results := proxy.CheckHealth(h.config.GetProxyRules())
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
 // addProxy adds a new proxy rule
 func (h *Handler) addProxy(w http.ResponseWriter, r *http.Request) {
 	var rule config.ProxyRule
@@ -83,20 +165,14 @@ func (h *Handler) addProxy(w http.ResponseWriter, r *http.Request) {
 		rule.ID = uuid.New().String()
 	}
 
-	// Validate - either PathPrefix or Port must be set
-	if rule.PathPrefix == "" && rule.Port == 0 {
-		http.Error(w, "Either PathPrefix or Port must be specified", http.StatusBadRequest)
-		return
-	}
-	
-	if rule.TargetURL == "" {
-		http.Error(w, "TargetURL is required", http.StatusBadRequest)
+	if err := validateProxyRule(&rule); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Ensure PathPrefix starts with / if provided
-	if rule.PathPrefix != "" && !strings.HasPrefix(rule.PathPrefix, "/") {
-		rule.PathPrefix = "/" + rule.PathPrefix
+	if err := h.checkConflicts(rule, ""); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
 	}
 
 	h.config.AddProxyRule(rule)
@@ -104,13 +180,54 @@ func (h *Handler) addProxy(w http.ResponseWriter, r *http.Request) {
 
 	if rule.Port > 0 {
 		log.Printf("Added port-based proxy rule: localhost:%d -> %s", rule.Port, rule.TargetURL)
+	} else if rule.Host != "" {
+		log.Printf("Added host-based proxy rule: %s%s -> %s", rule.Host, rule.PathPrefix, rule.TargetURL)
 	} else {
 		log.Printf("Added path-based proxy rule: %s -> %s", rule.PathPrefix, rule.TargetURL)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(rule)
+	json.NewEncoder(w).Encode(proxyResponse{ProxyRule: rule, Warnings: reachabilityWarnings(rule)})
+}
+
+// testProxy sends a sample request through a candidate rule without saving it
+func (h *Handler) testProxy(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Rule   config.ProxyRule `json:"rule"`
+		Path   string           `json:"path"`
+		Method string           `json:"method"` // default GET
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateProxyRule(&req.Rule); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Rule.ID == "" {
+		req.Rule.ID = "dry-run"
+	}
+	if req.Path == "" {
+		req.Path = req.Rule.PathPrefix
+		if req.Path == "" {
+			req.Path = "/"
+		}
+	}
+	if req.Method == "" {
+		req.Method = http.MethodGet
+	}
+
+	result, err := proxy.DryRun(req.Rule, strings.ToUpper(req.Method), req.Path)
+	if err != nil {
+		http.Error(w, "Invalid test request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
 }
 
 // updateProxy updates an existing proxy rule
@@ -121,20 +238,18 @@ func (h *Handler) updateProxy(w http.ResponseWriter, r *http.Request, id string)
 		return
 	}
 
-	// Validate - either PathPrefix or Port must be set
-	if rule.PathPrefix == "" && rule.Port == 0 {
-		http.Error(w, "Either PathPrefix or Port must be specified", http.StatusBadRequest)
+	if err := validateProxyRule(&rule); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	
-	if rule.TargetURL == "" {
-		http.Error(w, "TargetURL is required", http.StatusBadRequest)
+
+	if _, exists := h.config.GetProxyRule(id); !exists {
+		http.Error(w, "Proxy rule not found", http.StatusNotFound)
 		return
 	}
-
-	// Ensure PathPrefix starts with / if provided
-	if rule.PathPrefix != "" && !strings.HasPrefix(rule.PathPrefix, "/") {
-		rule.PathPrefix = "/" + rule.PathPrefix
+	if err := h.checkConflicts(rule, id); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
 	}
 
 	if !h.config.UpdateProxyRule(id, rule) {
@@ -144,10 +259,117 @@ func (h *Handler) updateProxy(w http.ResponseWriter, r *http.Request, id string)
 
 	h.proxyManager.RefreshProxies()
 
-	log.Printf("Updated proxy rule: %s -> %s", rule.PathPrefix, rule.TargetURL)
+	if rule.Enabled {
+		log.Printf("Updated proxy rule: %s -> %s", rule.PathPrefix, rule.TargetURL)
+	} else {
+		log.Printf("Updated proxy rule: %s -> %s (disabled)", rule.PathPrefix, rule.TargetURL)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(rule)
+	json.NewEncoder(w).Encode(proxyResponse{ProxyRule: rule, Warnings: reachabilityWarnings(rule)})
+}
+
+// validateProxyRule checks a proxy rule from a request body and normalizes its path prefix
+func validateProxyRule(rule *config.ProxyRule) error {
+	// At least one of PathPrefix, Host or Port must be set
+	if rule.PathPrefix == "" && rule.Host == "" && rule.Port == 0 {
+		return errors.New("One of PathPrefix, Host or Port must be specified")
+	}
+	rule.Host = strings.ToLower(strings.TrimSpace(rule.Host))
+
+	// Accept rules that only list TargetURLs by promoting the first one to TargetURL
+	if rule.TargetURL == "" && len(rule.TargetURLs) > 0 {
+		rule.TargetURL = rule.TargetURLs[0]
+		rule.TargetURLs = rule.TargetURLs[1:]
+	}
+	if rule.TargetURL == "" {
+		return errors.New("TargetURL is required")
+	}
+	for _, target := range rule.Targets() {
+		if err := validateTargetURL(target); err != nil {
+			return err
+		}
+	}
+
+	if rule.DialTimeout < 0 || rule.ResponseTimeout < 0 || rule.MaxRetries < 0 {
+		return errors.New("Timeouts and MaxRetries must not be negative")
+	}
+
+	rule.CAFile = strings.TrimSpace(rule.CAFile)
+	if rule.CAFile != "" {
+		if _, err := proxy.LoadCAFile(rule.CAFile); err != nil {
+			return fmt.Errorf("Invalid CAFile: %v", err)
+		}
+	}
+
+	if rule.RewriteFrom != "" {
+		if _, err := regexp.Compile(rule.RewriteFrom); err != nil {
+			return fmt.Errorf("Invalid RewriteFrom pattern: %v", err)
+		}
+	}
+
+	// Ensure PathPrefix starts with / if provided
+	if rule.PathPrefix != "" && !strings.HasPrefix(rule.PathPrefix, "/") {
+		rule.PathPrefix = "/" + rule.PathPrefix
+	}
+	return nil
+}
+
+// checkConflicts reports an existing rule, other than the one with excludeID, that claims
+// the same host and path prefix or the same port as rule
+func (h *Handler) checkConflicts(rule config.ProxyRule, excludeID string) error {
+	for _, existing := range h.config.GetProxyRules() {
+		if existing.ID == excludeID {
+			continue
+		}
+		if rule.Port > 0 && existing.Port == rule.Port {
+			return fmt.Errorf("Port %d is already used by proxy rule %s", rule.Port, existing.ID)
+		}
+		if (rule.PathPrefix != "" || rule.Host != "") &&
+			strings.EqualFold(existing.Host, rule.Host) && existing.PathPrefix == rule.PathPrefix {
+			if rule.Host != "" {
+				return fmt.Errorf("Host %s with path prefix %q is already used by proxy rule %s", rule.Host, rule.PathPrefix, existing.ID)
+			}
+			return fmt.Errorf("Path prefix %s is already used by proxy rule %s", rule.PathPrefix, existing.ID)
+		}
+	}
+	return nil
+}
+
+// validateTargetURL checks that target is an absolute http or https URL with a host
+func validateTargetURL(target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("Invalid target URL %q: %v", target, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		if !strings.Contains(target, "://") {
+			return fmt.Errorf("Invalid target URL %q: missing scheme, did you mean http://%s?", target, strings.TrimPrefix(target, "//"))
+		}
+		return fmt.Errorf("Invalid target URL %q: scheme must be http or https", target)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("Invalid target URL %q: missing host", target)
+	}
+	return nil
+}
+
+// proxyResponse is a saved proxy rule with warnings about its targets
+type proxyResponse struct {
+	config.ProxyRule
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// reachabilityWarnings dials the rule's targets and describes those that refused the connection.
+// An unreachable target is still saved, as the backend may simply not be running yet.
+func reachabilityWarnings(rule config.ProxyRule) []string {
+	var warnings []string
+	for _, status := range proxy.CheckHealth([]config.ProxyRule{rule}) {
+		if !status.Healthy {
+			warnings = append(warnings, fmt.Sprintf("%s is not reachable: %s", status.TargetURL, status.Error))
+		}
+	}
+	return warnings
 }
 
 // deleteProxy removes a proxy rule
@@ -198,6 +420,24 @@ func (h *Handler) importSettings(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"message": "Settings imported successfully"})
 }
 
+// reloadSettings re-reads the config file after it was edited outside the server
+func (h *Handler) reloadSettings(w http.ResponseWriter, r *http.Request) {
+	oldDir := h.config.GetFileServerDir()
+	if err := h.config.Reload(); err != nil {
+		http.Error(w, "Failed to reload settings: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.proxyManager.RefreshProxies()
+	if dir := h.config.GetFileServerDir(); dir != oldDir && h.watcher != nil {
+		h.watcher.RestartWatcher()
+	}
+
+	log.Println("Settings reloaded from config file")
+
+	h.getSettings(w, r)
+}
+
 // getSettings returns current settings
 func (h *Handler) getSettings(w http.ResponseWriter, r *http.Request) {
 	settings := h.config.GetSettings()
@@ -208,6 +448,7 @@ func (h *Handler) getSettings(w http.ResponseWriter, r *http.Request) {
 	response := map[string]interface{}{
 		"file_server_port": settings.FileServerPort,
 		"file_server_dir":  settings.FileServerDir,
+		"auto_index":       settings.AutoIndex,
 		"proxy_rules":      settings.ProxyRules,
 		"local_ip":         localIP,
 	}
@@ -216,20 +457,48 @@ func (h *Handler) getSettings(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-// getLocalIP returns the local IP address of the machine
-func getLocalIP() string {
-	addrs, err := net.InterfaceAddrs()
+// updateSettings changes runtime settings; currently only the served directory
+func (h *Handler) updateSettings(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		FileServerDir string `json:"file_server_dir"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.FileServerDir == "" {
+		http.Error(w, "file_server_dir is required", http.StatusBadRequest)
+		return
+	}
+
+	dir, err := filepath.Abs(req.FileServerDir)
+	if err != nil {
+		http.Error(w, "Invalid directory", http.StatusBadRequest)
+		return
+	}
+	info, err := os.Stat(dir)
 	if err != nil {
-		return "Unable to detect"
+		http.Error(w, "Directory does not exist: "+dir, http.StatusBadRequest)
+		return
+	}
+	if !info.IsDir() {
+		http.Error(w, "Not a directory: "+dir, http.StatusBadRequest)
+		return
 	}
 
-	for _, addr := range addrs {
-		if ipnet, ok := addr.(*net.IPNet); ok && !ipnet.IP.IsLoopback() {
-			if ipnet.IP.To4() != nil {
-				return ipnet.IP.String()
-			}
-		}
+	h.config.SetFileServerDir(dir)
+	if h.watcher != nil {
+		h.watcher.RestartWatcher()
+	}
+	log.Printf("Serving directory changed to %s", dir)
+
+	h.getSettings(w, r)
+}
+
+// getLocalIP returns the local IP address of the machine
+func getLocalIP() string {
+	if ip := netutil.LocalIP(); ip != "" {
+		return ip
 	}
-	
 	return "Unable to detect"
 }
@@ -0,0 +1,366 @@
+package admin
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"simple.http.server/internal/config"
+	"simple.http.server/internal/proxy"
+	"simple.http.server/internal/requestlog"
+)
+
+// newTestHandler returns an admin handler whose config holds rules
+func newTestHandler(t *testing.T, rules ...config.ProxyRule) (*Handler, *config.Config) {
+	t.Helper()
+	settings, err := json.Marshal(map[string]interface{}{"file_server_dir": t.TempDir(), "proxy_rules": rules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(cfg, proxy.NewProxyManager(cfg)), cfg
+}
+
+// send makes a request to h with a JSON body and returns the recorded response
+func send(t *testing.T, h *Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(string(data))))
+	return rec
+}
+
+func TestAddProxyValidatesRewrite(t *testing.T) {
+	h, cfg := newTestHandler(t)
+
+	rec := send(t, h, http.MethodPost, "/admin/api/proxies", config.ProxyRule{
+		PathPrefix: "/api", TargetURL: "http://localhost:1", RewriteFrom: "(unclosed", RewriteTo: "/x",
+	})
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "Invalid RewriteFrom pattern") {
+		t.Errorf("invalid pattern: status = %d, body = %q, want 400", rec.Code, rec.Body)
+	}
+	if len(cfg.GetProxyRules()) != 0 {
+		t.Error("a rule with an invalid pattern was saved")
+	}
+
+	rec = send(t, h, http.MethodPost, "/admin/api/proxies", config.ProxyRule{
+		PathPrefix: "/api", TargetURL: "http://localhost:1", RewriteFrom: `^/users/(\d+)$`, RewriteTo: "/u/$1", Enabled: true,
+	})
+	if rec.Code != http.StatusCreated {
+		t.Errorf("valid pattern: status = %d: %s", rec.Code, rec.Body)
+	}
+	if rules := cfg.GetProxyRules(); len(rules) != 1 || rules[0].RewriteTo != "/u/$1" {
+		t.Errorf("saved rules = %+v", rules)
+	}
+}
+
+func TestProxyHealth(t *testing.T) {
+	live := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(live.Close)
+	// A closed server leaves a port nothing is listening on
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+	h, _ := newTestHandler(t,
+		config.ProxyRule{ID: "live", PathPrefix: "/live", TargetURL: live.URL, Enabled: true},
+		config.ProxyRule{ID: "dead", PathPrefix: "/dead", TargetURL: dead.URL, Enabled: true},
+	)
+
+	rec := send(t, h, http.MethodGet, "/admin/api/proxies/health", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var results []proxy.HealthStatus
+	if err := json.NewDecoder(rec.Body).Decode(&results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("results = %+v, want one per rule", results)
+	}
+	if got := results[0]; got.ID != "live" || got.TargetURL != live.URL || !got.Healthy || got.Error != "" {
+		t.Errorf("live target = %+v, want healthy", got)
+	}
+	if got := results[1]; got.ID != "dead" || got.TargetURL != dead.URL || got.Healthy || got.Error == "" {
+		t.Errorf("dead target = %+v, want unhealthy with an error", got)
+	}
+}
+
+func TestProxyConflicts(t *testing.T) {
+	backend := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(backend.Close)
+	h, cfg := newTestHandler(t,
+		config.ProxyRule{ID: "api", PathPrefix: "/api", TargetURL: backend.URL, Enabled: true},
+		config.ProxyRule{ID: "port", Port: 9100, TargetURL: backend.URL, Enabled: true},
+		config.ProxyRule{ID: "blog", Host: "blog.lan", PathPrefix: "/api", TargetURL: backend.URL, Enabled: true},
+	)
+
+	conflicts := []config.ProxyRule{
+		{PathPrefix: "/api", TargetURL: backend.URL},
+		{PathPrefix: "api", TargetURL: backend.URL}, // normalized to /api
+		{Port: 9100, TargetURL: backend.URL},
+		{Host: "BLOG.lan", PathPrefix: "/api", TargetURL: backend.URL},
+	}
+	for _, rule := range conflicts {
+		rec := send(t, h, http.MethodPost, "/admin/api/proxies", rule)
+		if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), "already used") {
+			t.Errorf("add %+v: status = %d, body = %q, want 409", rule, rec.Code, rec.Body)
+		}
+	}
+	if n := len(cfg.GetProxyRules()); n != 3 {
+		t.Errorf("%d rules saved, want the 3 existing ones", n)
+	}
+
+	// An update may keep its own prefix but not take another rule's port
+	if rec := send(t, h, http.MethodPut, "/admin/api/proxies/api", config.ProxyRule{PathPrefix: "/api", TargetURL: backend.URL}); rec.Code != http.StatusOK {
+		t.Errorf("update keeping its prefix: status = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := send(t, h, http.MethodPut, "/admin/api/proxies/api", config.ProxyRule{Port: 9100, TargetURL: backend.URL}); rec.Code != http.StatusConflict {
+		t.Errorf("update taking a used port: status = %d, want 409", rec.Code)
+	}
+
+	// Other prefixes, hosts and ports are free
+	for _, rule := range []config.ProxyRule{
+		{PathPrefix: "/app", TargetURL: backend.URL},
+		{Host: "shop.lan", PathPrefix: "/api", TargetURL: backend.URL},
+		{Port: 9101, TargetURL: backend.URL},
+	} {
+		if rec := send(t, h, http.MethodPost, "/admin/api/proxies", rule); rec.Code != http.StatusCreated {
+			t.Errorf("add %+v: status = %d: %s", rule, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestAddProxyValidatesTargetURL(t *testing.T) {
+	h, cfg := newTestHandler(t)
+
+	tests := []struct{ target, message string }{
+		{"localhost:3000", "did you mean http://localhost:3000"},
+		{"ftp://files.lan", "scheme must be http or https"},
+		{"http://", "missing host"},
+		{"", "TargetURL is required"},
+	}
+	for _, tt := range tests {
+		rec := send(t, h, http.MethodPost, "/admin/api/proxies", config.ProxyRule{PathPrefix: "/api", TargetURL: tt.target})
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), tt.message) {
+			t.Errorf("%q: status = %d, body = %q, want 400 with %q", tt.target, rec.Code, rec.Body, tt.message)
+		}
+	}
+	if len(cfg.GetProxyRules()) != 0 {
+		t.Fatal("a rule with an invalid target was saved")
+	}
+
+	backend := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(backend.Close)
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+	for _, tt := range []struct {
+		prefix, target string
+		warned         bool
+	}{
+		{"/live", backend.URL, false},
+		{"/dead", dead.URL, true}, // saved, since the backend may not be running yet
+	} {
+		rec := send(t, h, http.MethodPost, "/admin/api/proxies", config.ProxyRule{PathPrefix: tt.prefix, TargetURL: tt.target})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("%s: status = %d: %s", tt.target, rec.Code, rec.Body)
+		}
+		var resp struct {
+			Warnings []string `json:"warnings"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if (len(resp.Warnings) > 0) != tt.warned {
+			t.Errorf("%s: warnings = %v, want warned %v", tt.target, resp.Warnings, tt.warned)
+		}
+	}
+	if n := len(cfg.GetProxyRules()); n != 2 {
+		t.Errorf("%d rules saved, want 2", n)
+	}
+}
+
+func TestAddProxySetsPriority(t *testing.T) {
+	h, cfg := newTestHandler(t)
+	backend := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(backend.Close)
+
+	if rec := send(t, h, http.MethodPost, "/admin/api/proxies", map[string]interface{}{
+		"id": "api", "path_prefix": "/api", "target_url": backend.URL, "priority": 5,
+	}); rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if rule, ok := cfg.GetProxyRule("api"); !ok || rule.Priority != 5 {
+		t.Errorf("saved rule = %+v, want priority 5", rule)
+	}
+}
+
+func TestDryRunProxy(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Backend", "yes")
+		w.WriteHeader(http.StatusTeapot)
+		io.WriteString(w, "reached "+r.URL.Path+" "+strings.Repeat("x", 8<<10))
+	}))
+	t.Cleanup(backend.Close)
+	h, cfg := newTestHandler(t)
+
+	rec := send(t, h, http.MethodPost, "/admin/api/proxies/test", map[string]interface{}{
+		"rule": config.ProxyRule{PathPrefix: "/api", StripPrefix: true, TargetURL: backend.URL},
+		"path": "/api/users",
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var result proxy.DryRunResult
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != http.StatusTeapot || result.UpstreamPath != "/users" {
+		t.Errorf("status %d, upstream path %q; want 418 and /users", result.Status, result.UpstreamPath)
+	}
+	if got := http.Header(result.Headers).Get("X-Backend"); got != "yes" {
+		t.Errorf("X-Backend = %q, want the backend's header", got)
+	}
+	if !strings.HasPrefix(result.Body, "reached /users ") || !result.Truncated || len(result.Body) >= 8<<10 {
+		t.Errorf("body of %d bytes, truncated %v; want the start of the backend's body", len(result.Body), result.Truncated)
+	}
+	if len(cfg.GetProxyRules()) != 0 {
+		t.Error("the tested rule was saved")
+	}
+
+	rec = send(t, h, http.MethodPost, "/admin/api/proxies/test", map[string]interface{}{
+		"rule": config.ProxyRule{PathPrefix: "/api", TargetURL: "localhost:3000"},
+	})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid rule: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	h, _ := newTestHandler(t)
+	h.SetBuildInfo(BuildInfo{Version: "1.4.0", Commit: "abc1234", BuildDate: "2024-05-01T10:00:00Z"})
+
+	rec := send(t, h, http.MethodGet, "/admin/api/version", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var got map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"version": "1.4.0", "commit": "abc1234", "build_date": "2024-05-01T10:00:00Z"}
+	for field, value := range want {
+		if got[field] != value {
+			t.Errorf("%s = %q, want %q", field, got[field], value)
+		}
+	}
+}
+
+func TestListLogs(t *testing.T) {
+	h, _ := newTestHandler(t)
+	log := requestlog.New(10)
+	h.SetRequestLog(log)
+	for _, path := range []string{"/a", "/b", "/c"} {
+		log.Add(requestlog.Entry{Method: http.MethodGet, Path: path, Status: http.StatusOK})
+	}
+
+	rec := send(t, h, http.MethodGet, "/admin/api/logs?limit=2", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var entries []requestlog.Entry
+	if err := json.NewDecoder(rec.Body).Decode(&entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Path != "/c" || entries[1].Path != "/b" {
+		t.Errorf("entries = %+v, want /c then /b", entries)
+	}
+
+	for _, limit := range []string{"0", "x"} {
+		if rec := send(t, h, http.MethodGet, "/admin/api/logs?limit="+limit, nil); rec.Code != http.StatusBadRequest {
+			t.Errorf("limit %q: status = %d, want 400", limit, rec.Code)
+		}
+	}
+}
+
+// restartCounter is a Watcher that counts its restarts
+type restartCounter int
+
+func (c *restartCounter) RestartWatcher() { *c++ }
+
+func TestReloadSettings(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "reloaded")
+	}))
+	t.Cleanup(backend.Close)
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	served, other := t.TempDir(), t.TempDir()
+	writeSettings := func(settings map[string]interface{}) {
+		t.Helper()
+		data, err := json.Marshal(settings)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeSettings(map[string]interface{}{"file_server_dir": served})
+	cfg := &config.Config{}
+	if err := cfg.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	pm := proxy.NewProxyManager(cfg)
+	h := NewHandler(cfg, pm)
+	var restarts restartCounter
+	h.SetWatcher(&restarts)
+
+	// An external edit adds a proxy rule and moves the served directory
+	writeSettings(map[string]interface{}{
+		"file_server_dir": other,
+		"auto_index":      false,
+		"proxy_rules":     []config.ProxyRule{{ID: "api", PathPrefix: "/api", TargetURL: backend.URL, Enabled: true}},
+	})
+	rec := send(t, h, http.MethodPost, "/admin/api/settings/reload", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var body map[string]interface{}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["file_server_dir"] != other || body["auto_index"] != false {
+		t.Errorf("response = %v, want the reloaded settings", body)
+	}
+	if cfg.GetFileServerDir() != other || restarts != 1 {
+		t.Errorf("dir = %q with %d watcher restarts, want %q and 1", cfg.GetFileServerDir(), restarts, other)
+	}
+	proxied := httptest.NewRecorder()
+	pm.ServeHTTP(proxied, httptest.NewRequest(http.MethodGet, "/api/x", nil))
+	if proxied.Body.String() != "reloaded" {
+		t.Errorf("proxy after reload = %d %q, want the new rule to be active", proxied.Code, proxied.Body)
+	}
+
+	// A malformed file is refused and the running config kept
+	before := cfg.GetSettings()
+	if err := os.WriteFile(path, []byte(`{"auto_index": true,`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if rec := send(t, h, http.MethodPost, "/admin/api/settings/reload", nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("malformed file: status = %d, want 400", rec.Code)
+	}
+	if got := cfg.GetSettings(); !reflect.DeepEqual(got, before) || restarts != 1 {
+		t.Errorf("settings = %+v after a failed reload, want them unchanged", got)
+	}
+}
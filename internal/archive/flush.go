@@ -0,0 +1,34 @@
+package archive
+
+import (
+	"io"
+	"net/http"
+)
+
+// flushInterval is how many bytes are written between flushes of the response
+const flushInterval = 256 << 10 // 256 KB
+
+// flushWriter flushes the underlying response periodically so large archives
+// reach the client steadily instead of stalling behind buffers
+type flushWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+	pending int
+}
+
+// newFlushWriter wraps w, flushing it if it implements http.Flusher
+func newFlushWriter(w io.Writer) *flushWriter {
+	fw := &flushWriter{w: w}
+	fw.flusher, _ = w.(http.Flusher)
+	return fw
+}
+
+func (fw *flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	fw.pending += n
+	if fw.flusher != nil && fw.pending >= flushInterval {
+		fw.flusher.Flush()
+		fw.pending = 0
+	}
+	return n, err
+}
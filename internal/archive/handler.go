@@ -1,21 +1,28 @@
 package archive
 
 import (
-	"archive/zip"
+	"compress/flate"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
+	"mime"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"simple.http.server/internal/config"
+	"simple.http.server/internal/metrics"
+	"simple.http.server/internal/pathutil"
 )
 
 // Handler manages archive creation
 type Handler struct {
-	config *config.Config
+	config  *config.Config
+	metrics *metrics.Metrics // optional, see SetMetrics
 }
 
 // NewHandler creates a new archive handler
@@ -23,11 +30,19 @@ func NewHandler(cfg *config.Config) *Handler {
 	return &Handler{config: cfg}
 }
 
-// ServeHTTP handles archive requests
+// SetMetrics sets where created archives are counted
+func (h *Handler) SetMetrics(m *metrics.Metrics) {
+	h.metrics = m
+}
+
+// ServeHTTP handles archive requests. A single path is archived under its own name;
+// several paths (repeated "path" parameters or form fields, or a POSTed JSON array) are archived
+// together, keeping their paths relative to the served directory. A "password" parameter
+// encrypts the files in the archive with it.
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// CORS headers
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 	if r.Method == http.MethodOptions {
@@ -35,83 +50,196 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if r.Method != http.MethodGet {
+	// Get paths to archive
+	var archivePaths []string
+	password := r.URL.Query().Get("password")
+	switch r.Method {
+	case http.MethodGet:
+		archivePaths = r.URL.Query()["path"]
+	case http.MethodPost:
+		// The listing's "Download selected" form posts repeated "path" fields
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/x-www-form-urlencoded" {
+			if err := r.ParseForm(); err != nil {
+				http.Error(w, "Invalid form", http.StatusBadRequest)
+				return
+			}
+			archivePaths = r.PostForm["path"]
+			if value := r.PostForm.Get("password"); value != "" {
+				password = value
+			}
+			if len(archivePaths) == 0 {
+				http.Error(w, "No paths selected", http.StatusBadRequest)
+				return
+			}
+			break
+		}
+		if err := json.NewDecoder(r.Body).Decode(&archivePaths); err != nil {
+			http.Error(w, "Invalid request body: expected a JSON array of paths", http.StatusBadRequest)
+			return
+		}
+	default:
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-
-	// Get path to archive
-	archivePath := r.URL.Query().Get("path")
-	if archivePath == "" {
-		archivePath = "/"
+	if len(archivePaths) == 0 {
+		archivePaths = []string{"/"}
 	}
 
-	// Get base directory
-	baseDir := h.config.GetFileServerDir()
-	fullPath := filepath.Join(baseDir, filepath.Clean(archivePath))
-
-	// Security check
-	absBase, err := filepath.Abs(baseDir)
-	if err != nil {
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
+	// Glob patterns for files and directories to leave out, comma-separated and repeatable
+	var opts options
+	for _, value := range r.URL.Query()["exclude"] {
+		for _, pattern := range strings.Split(value, ",") {
+			if pattern = strings.TrimSpace(pattern); pattern != "" {
+				opts.exclude = append(opts.exclude, pattern)
+			}
+		}
 	}
 
-	absArchive, err := filepath.Abs(fullPath)
-	if err != nil {
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	level, ok := compressionLevels[r.URL.Query().Get("compression")]
+	if !ok {
+		http.Error(w, "Invalid compression: use store, fast or best", http.StatusBadRequest)
 		return
 	}
+	opts.store = level == flate.NoCompression
 
-	if !strings.HasPrefix(absArchive, absBase) {
-		http.Error(w, "Forbidden", http.StatusForbidden)
-		return
-	}
+	// Resolve every path first so nothing is sent if any of them is invalid
+	absBase := ""
+	entries := make([]archiveEntry, 0, len(archivePaths))
+	for _, archivePath := range archivePaths {
+		root, absPath, err := pathutil.Resolve(h.config.GetFileServerDir(), archivePath)
+		if err == pathutil.ErrOutsideRoot {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
 
-	// Check if path exists
-	info, err := os.Stat(absArchive)
-	if err != nil {
-		http.Error(w, "Path not found", http.StatusNotFound)
-		return
+		// Check if path exists
+		info, err := os.Stat(absPath)
+		if err != nil {
+			http.Error(w, "Path not found: "+archivePath, http.StatusNotFound)
+			return
+		}
+
+		absBase = root
+		entries = append(entries, archiveEntry{absPath: absPath, info: info})
 	}
 
-	// Determine archive name
-	archiveName := "archive.zip"
-	if info.IsDir() {
-		archiveName = filepath.Base(absArchive) + ".zip"
+	// Determine archive name and where each entry is placed inside the zip
+	archiveName := "selection.zip"
+	if len(entries) == 1 {
+		entry := &entries[0]
+		entry.zipPath = filepath.Base(entry.absPath)
+		if entry.info.IsDir() {
+			archiveName = filepath.Base(entry.absPath) + ".zip"
+		} else {
+			archiveName = strings.TrimSuffix(filepath.Base(entry.absPath), filepath.Ext(entry.absPath)) + ".zip"
+		}
 	} else {
-		archiveName = strings.TrimSuffix(filepath.Base(absArchive), filepath.Ext(absArchive)) + ".zip"
+		for i := range entries {
+			relPath, err := filepath.Rel(absBase, entries[i].absPath)
+			if err != nil {
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+			entries[i].zipPath = relPath
+		}
+	}
+
+	// Name protected archives so they are recognisable once downloaded
+	if password != "" {
+		archiveName = strings.TrimSuffix(archiveName, ".zip") + "-encrypted.zip"
 	}
 
 	// Set headers for download
 	w.Header().Set("Content-Type", "application/zip")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", archiveName))
 
-	// Create zip writer
-	zipWriter := zip.NewWriter(w)
-	defer zipWriter.Close()
-
-	if info.IsDir() {
-		// Archive directory
-		err = h.archiveDirectory(zipWriter, absArchive, filepath.Base(absArchive))
+	// Create zip writer; the archive is streamed without a Content-Length,
+	// so it is sent chunked and flushed as it grows
+	var zipWriter entryWriter
+	if password != "" {
+		zipWriter = newEncryptedWriter(newFlushWriter(w), password)
 	} else {
-		// Archive single file
-		err = h.archiveFile(zipWriter, absArchive, filepath.Base(absArchive))
+		zipWriter = newPlainWriter(newFlushWriter(w), level)
 	}
 
-	if err != nil {
-		log.Printf("Archive error: %v", err)
-		return
+	for _, entry := range entries {
+		var err error
+		if entry.info.IsDir() {
+			// Archive directory
+			err = h.archiveDirectory(zipWriter, entry.absPath, entry.zipPath, opts)
+		} else {
+			// Archive single file
+			err = h.archiveFile(zipWriter, entry.absPath, entry.zipPath, opts)
+		}
+
+		if err != nil {
+			abortArchive(archiveName, err)
+		}
 	}
 
-	log.Printf("Created archive: %s (%s)", archiveName, archivePath)
+	// Close writes the central directory; without it the zip is unreadable
+	if err := zipWriter.Close(); err != nil {
+		abortArchive(archiveName, err)
+	}
+
+	log.Printf("Created archive: %s (%s)", archiveName, strings.Join(archivePaths, ", "))
+	h.metrics.ArchiveCreated()
+}
+
+// abortArchive logs a failure part way through streaming an archive and aborts the
+// response. The status has already been sent, so dropping the connection is the only
+// way to keep the client from saving a truncated zip as if it were complete.
+func abortArchive(archiveName string, err error) {
+	log.Printf("Archive error for %s: %v", archiveName, err)
+	panic(http.ErrAbortHandler)
 }
 
-// archiveDirectory adds a directory to the zip archive
-func (h *Handler) archiveDirectory(zipWriter *zip.Writer, dirPath, basePath string) error {
+// compressionLevels maps the compression parameter to a flate level
+var compressionLevels = map[string]int{
+	"":      flate.DefaultCompression,
+	"store": flate.NoCompression,
+	"fast":  flate.BestSpeed,
+	"best":  flate.BestCompression,
+}
+
+// storedExtensions are formats that are already compressed, so deflating them again wastes CPU
+var storedExtensions = map[string]bool{
+	".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".webp": true, ".heic": true,
+	".mp3": true, ".aac": true, ".ogg": true, ".flac": true, ".m4a": true, ".opus": true,
+	".mp4": true, ".mkv": true, ".mov": true, ".webm": true, ".avi": true,
+	".zip": true, ".gz": true, ".tgz": true, ".bz2": true, ".xz": true, ".7z": true, ".rar": true, ".zst": true,
+	".pdf": true, ".docx": true, ".xlsx": true, ".pptx": true, ".jar": true, ".apk": true,
+}
+
+// options controls how entries are added to an archive
+type options struct {
+	exclude []string // glob patterns of paths to skip inside directories
+	store   bool     // store every file without compression
+}
+
+// archiveEntry is a file or directory to be added to an archive
+type archiveEntry struct {
+	absPath string
+	info    os.FileInfo
+	zipPath string // name of the entry inside the zip
+}
+
+// archiveDirectory adds a directory to the zip archive, skipping paths that match exclude.
+// Only empty directories get their own entry, so a directory whose contents were all
+// excluded does not appear in the archive.
+func (h *Handler) archiveDirectory(zipWriter entryWriter, dirPath, basePath string, opts options) error {
 	return filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			return err
+			return skipUnreadable(path, err)
+		}
+
+		// Skip the root directory itself
+		if path == dirPath {
+			return nil
 		}
 
 		// Get relative path
@@ -120,36 +248,64 @@ func (h *Handler) archiveDirectory(zipWriter *zip.Writer, dirPath, basePath stri
 			return err
 		}
 
-		// Create zip path
-		zipPath := filepath.Join(basePath, relPath)
-		
-		// Skip the root directory itself
-		if path == dirPath {
+		if pathutil.MatchesAny(filepath.ToSlash(relPath), opts.exclude) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
+		// Create zip path
+		zipPath := filepath.Join(basePath, relPath)
+
 		if info.IsDir() {
-			// Add directory entry
-			_, err := zipWriter.Create(zipPath + "/")
-			return err
+			// Add an entry for empty directories; others are implied by their files
+			children, err := os.ReadDir(path)
+			if err != nil {
+				if err := skipUnreadable(path, err); err != nil {
+					return err
+				}
+				return filepath.SkipDir
+			}
+			if len(children) > 0 {
+				return nil
+			}
+			return zipWriter.createDir(filepath.ToSlash(zipPath) + "/")
+		}
+
+		// Leave out symlinks and special files; a link could point outside the served directory
+		if !info.Mode().IsRegular() {
+			return nil
 		}
 
 		// Add file
-		return h.addFileToZip(zipWriter, path, zipPath)
+		return h.addFileToZip(zipWriter, path, zipPath, opts)
 	})
 }
 
+// skipUnreadable leaves out an entry that cannot be read or was removed while the archive
+// was being built, returning nil so the rest is still archived. Other errors are returned
+// and abort the archive, as do failures to write it.
+func skipUnreadable(path string, err error) error {
+	if errors.Is(err, fs.ErrPermission) || errors.Is(err, fs.ErrNotExist) {
+		log.Printf("Leaving %s out of archive: %v", path, err)
+		return nil
+	}
+	return err
+}
+
 // archiveFile adds a single file to the zip archive
-func (h *Handler) archiveFile(zipWriter *zip.Writer, filePath, zipPath string) error {
-	return h.addFileToZip(zipWriter, filePath, zipPath)
+func (h *Handler) archiveFile(zipWriter entryWriter, filePath, zipPath string, opts options) error {
+	return h.addFileToZip(zipWriter, filePath, zipPath, opts)
 }
 
-// addFileToZip adds a file to the zip archive
-func (h *Handler) addFileToZip(zipWriter *zip.Writer, filePath, zipPath string) error {
+// addFileToZip adds a file to the zip archive, storing it uncompressed if requested
+// or if its format is already compressed
+func (h *Handler) addFileToZip(zipWriter entryWriter, filePath, zipPath string, opts options) error {
 	// Open source file
 	file, err := os.Open(filePath)
 	if err != nil {
-		return err
+		return skipUnreadable(filePath, err)
 	}
 	defer file.Close()
 
@@ -158,18 +314,14 @@ func (h *Handler) addFileToZip(zipWriter *zip.Writer, filePath, zipPath string)
 	if err != nil {
 		return err
 	}
-
-	// Create zip header
-	header, err := zip.FileInfoHeader(info)
-	if err != nil {
-		return err
+	if !info.Mode().IsRegular() {
+		log.Printf("Leaving %s out of archive: not a regular file", filePath)
+		return nil
 	}
 
-	header.Name = filepath.ToSlash(zipPath)
-	header.Method = zip.Deflate
-
 	// Create writer for file
-	writer, err := zipWriter.CreateHeader(header)
+	store := opts.store || storedExtensions[strings.ToLower(filepath.Ext(filePath))]
+	writer, err := zipWriter.createFile(filepath.ToSlash(zipPath), info, store)
 	if err != nil {
 		return err
 	}
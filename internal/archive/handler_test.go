@@ -0,0 +1,373 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"simple.http.server/internal/config"
+
+	securezip "github.com/yeka/zip"
+)
+
+// newTestHandler returns a handler archiving root
+func newTestHandler(t *testing.T, root string) *Handler {
+	t.Helper()
+	settings, err := json.Marshal(map[string]interface{}{"file_server_dir": root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(cfg)
+}
+
+// zipNames returns the sorted entry names of a zip archive
+func zipNames(t *testing.T, data []byte) []string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestArchiveSkipsUnreadableEntries(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "a.txt"), []byte("a"), 0644)
+	if err := os.Symlink(filepath.Join(root, "missing"), filepath.Join(root, "dangling")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+	want := []string{filepath.Base(root) + "/a.txt"}
+
+	// Permissions do not stop root from reading, so only check them as another user
+	if os.Geteuid() != 0 {
+		os.WriteFile(filepath.Join(root, "locked.txt"), []byte("locked"), 0)
+		os.Mkdir(filepath.Join(root, "locked"), 0)
+		t.Cleanup(func() { os.Chmod(filepath.Join(root, "locked"), 0755) })
+	}
+
+	rec := httptest.NewRecorder()
+	newTestHandler(t, root).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/archive?path=/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	names := zipNames(t, rec.Body.Bytes())
+	if len(names) != len(want) || names[0] != want[0] {
+		t.Errorf("archive holds %v, want %v", names, want)
+	}
+}
+
+func TestArchiveSkipsSymlinks(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	writeFiles(t, base, "outside/secret.txt", "root/d/a.txt")
+
+	tests := []struct{ name, target string }{
+		{"link to a file", filepath.Join(base, "outside", "secret.txt")},
+		{"link to a directory", filepath.Join(base, "outside")},
+	}
+	for _, tt := range tests {
+		link := filepath.Join(root, "d", "link")
+		os.Remove(link)
+		if err := os.Symlink(tt.target, link); err != nil {
+			t.Skipf("symlinks unsupported: %v", err)
+		}
+
+		rec := httptest.NewRecorder()
+		newTestHandler(t, root).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/archive?path=/d", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d: %s", tt.name, rec.Code, rec.Body)
+		}
+		if got, want := zipNames(t, rec.Body.Bytes()), []string{"d/a.txt"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: archive holds %v, want %v", tt.name, got, want)
+		}
+	}
+}
+
+// failingWriter is a response whose body cannot be written, as when the client went away
+type failingWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (f failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestArchiveAbortsWhenWriteFails(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "a.txt"), bytes.Repeat([]byte("a"), 1<<20), 0644)
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler", v)
+		}
+	}()
+	newTestHandler(t, root).ServeHTTP(failingWriter{httptest.NewRecorder()}, httptest.NewRequest(http.MethodGet, "/api/archive?path=/", nil))
+}
+
+// writeFiles creates each file under root, and its folders, holding its own name
+func writeFiles(t *testing.T, root string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// serve sends a request to h and returns the recorded response
+func serve(h *Handler, method, target, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestArchiveSelectedFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, "docs/a.txt", "photos/2024/b.jpg", "c.txt", "docs/unselected.txt")
+	h := newTestHandler(t, root)
+	want := []string{"c.txt", "docs/a.txt", "photos/2024/b.jpg"}
+
+	requests := []struct{ method, target, contentType, body string }{
+		{http.MethodGet, "/api/archive?path=/docs/a.txt&path=/photos/2024/b.jpg&path=c.txt", "", ""},
+		{http.MethodPost, "/api/archive", "application/json", `["/docs/a.txt", "/photos/2024/b.jpg", "/c.txt"]`},
+		{http.MethodPost, "/api/archive", "application/x-www-form-urlencoded", "path=%2Fdocs%2Fa.txt&path=%2Fphotos%2F2024%2Fb.jpg&path=%2Fc.txt"},
+	}
+	for _, req := range requests {
+		rec := serve(h, req.method, req.target, req.contentType, req.body)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s %s: status = %d: %s", req.method, req.target, rec.Code, rec.Body)
+		}
+		if got := rec.Header().Get("Content-Disposition"); !strings.Contains(got, "selection.zip") {
+			t.Errorf("%s %s: Content-Disposition = %q, want selection.zip", req.method, req.target, got)
+		}
+		data := rec.Body.Bytes()
+		if got := zipNames(t, data); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s %s: archive holds %v, want %v", req.method, req.target, got, want)
+		}
+		zr, _ := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, _ := io.ReadAll(rc)
+			rc.Close()
+			if string(content) != f.Name {
+				t.Errorf("%s holds %q", f.Name, content)
+			}
+		}
+	}
+}
+
+func TestArchiveSelectionFromListingForm(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, "docs/a.txt", "docs/sub/b.txt", "notes.txt", "skipped.txt")
+	h := newTestHandler(t, root)
+
+	// The form the listing builds: one "path" field per checked item, folders included
+	rec := serve(h, http.MethodPost, "/api/archive", "application/x-www-form-urlencoded", "path=%2Fdocs%2F&path=%2Fnotes.txt")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/zip" {
+		t.Fatalf("status = %d, Content-Type = %q: %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body)
+	}
+	want := []string{"docs/a.txt", "docs/sub/b.txt", "notes.txt"}
+	if got := zipNames(t, rec.Body.Bytes()); !reflect.DeepEqual(got, want) {
+		t.Errorf("archive holds %v, want %v", got, want)
+	}
+
+	// Submitting with nothing checked is refused rather than archiving everything
+	if rec := serve(h, http.MethodPost, "/api/archive", "application/x-www-form-urlencoded", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("empty selection: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestArchiveSelectionRejectsEscapes(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	writeFiles(t, base, "root/a.txt", "outside.txt")
+	h := newTestHandler(t, root)
+
+	rec := serve(h, http.MethodGet, "/api/archive?path=/a.txt&path=../outside.txt", "", "")
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+	if rec.Header().Get("Content-Type") == "application/zip" {
+		t.Error("part of an archive was sent for a rejected selection")
+	}
+
+	if rec := serve(h, http.MethodGet, "/api/archive?path=/a.txt&path=/missing.txt", "", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("missing path: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestArchiveExcludes(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, "project/main.go", "project/debug.log", "project/logs/old.log", "project/node_modules/pkg/index.js", "project/build/out.bin")
+	h := newTestHandler(t, root)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"&exclude=*.log", []string{"project/build/out.bin", "project/main.go", "project/node_modules/pkg/index.js"}},
+		{"&exclude=*.log,node_modules&exclude=build", []string{"project/main.go"}},
+	}
+	for _, tt := range tests {
+		rec := serve(h, http.MethodGet, "/api/archive?path=/project"+tt.query, "", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d: %s", tt.query, rec.Code, rec.Body)
+		}
+		// logs/ held only excluded files, so it has no entry of its own either
+		if got := zipNames(t, rec.Body.Bytes()); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: archive holds %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+// zipMethods returns the compression method of each entry of a zip archive
+func zipMethods(t *testing.T, data []byte) map[string]uint16 {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	methods := make(map[string]uint16)
+	for _, f := range zr.File {
+		methods[f.Name] = f.Method
+	}
+	return methods
+}
+
+func TestArchiveCompression(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, "album/photo.JPG", "album/notes.txt")
+	h := newTestHandler(t, root)
+
+	tests := []struct {
+		query      string
+		jpg, notes uint16
+	}{
+		{"", zip.Store, zip.Deflate}, // already-compressed formats are stored
+		{"&compression=fast", zip.Store, zip.Deflate},
+		{"&compression=best", zip.Store, zip.Deflate},
+		{"&compression=store", zip.Store, zip.Store},
+	}
+	for _, tt := range tests {
+		rec := serve(h, http.MethodGet, "/api/archive?path=/album"+tt.query, "", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%q: status = %d: %s", tt.query, rec.Code, rec.Body)
+		}
+		methods := zipMethods(t, rec.Body.Bytes())
+		if methods["album/photo.JPG"] != tt.jpg || methods["album/notes.txt"] != tt.notes {
+			t.Errorf("%q: methods = %v, want jpg %d and txt %d", tt.query, methods, tt.jpg, tt.notes)
+		}
+	}
+
+	if rec := serve(h, http.MethodGet, "/api/archive?path=/album&compression=max", "", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid compression: status = %d, want 400", rec.Code)
+	}
+}
+
+// readEncrypted opens each file of an encrypted archive with password and returns their contents
+func readEncrypted(t *testing.T, data []byte, password string) (map[string]string, error) {
+	t.Helper()
+	zr, err := securezip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	contents := make(map[string]string)
+	for _, f := range zr.File {
+		if strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		if !f.IsEncrypted() {
+			t.Errorf("%s is not encrypted", f.Name)
+		}
+		f.SetPassword(password)
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		contents[f.Name] = string(content)
+	}
+	return contents, nil
+}
+
+func TestArchiveWithPassword(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, "docs/a.txt")
+	secret := strings.Repeat("launch code 0000 ", 50)
+	if err := os.WriteFile(filepath.Join(root, "docs", "secret.txt"), []byte(secret), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h := newTestHandler(t, root)
+
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	requests := []struct{ method, target, contentType, body string }{
+		{http.MethodGet, "/api/archive?path=/docs&password=s3cret", "", ""},
+		{http.MethodPost, "/api/archive", "application/x-www-form-urlencoded", "path=%2Fdocs%2Fa.txt&path=%2Fdocs%2Fsecret.txt&password=s3cret"},
+	}
+	for _, req := range requests {
+		rec := serve(h, req.method, req.target, req.contentType, req.body)
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/zip" {
+			t.Fatalf("%s %s: status = %d, Content-Type = %q", req.method, req.target, rec.Code, rec.Header().Get("Content-Type"))
+		}
+		if got := rec.Header().Get("Content-Disposition"); !strings.Contains(got, "-encrypted.zip") {
+			t.Errorf("%s: Content-Disposition = %q, want the name to note the encryption", req.method, got)
+		}
+		data := rec.Body.Bytes()
+		if bytes.Contains(data, []byte("launch code")) {
+			t.Errorf("%s: archive holds the file contents in the clear", req.method)
+		}
+
+		contents, err := readEncrypted(t, data, "s3cret")
+		if err != nil {
+			t.Fatalf("%s: extracting with the password: %v", req.method, err)
+		}
+		if contents["docs/a.txt"] != "docs/a.txt" || contents["docs/secret.txt"] != secret {
+			t.Errorf("%s: extracted %d files with the wrong contents", req.method, len(contents))
+		}
+		if _, err := readEncrypted(t, data, "wrong"); err == nil {
+			t.Errorf("%s: extracting with a wrong password succeeded", req.method)
+		}
+	}
+
+	if strings.Contains(logged.String(), "s3cret") {
+		t.Errorf("the password was logged: %s", logged.String())
+	}
+}
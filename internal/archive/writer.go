@@ -0,0 +1,91 @@
+package archive
+
+import (
+	"archive/zip"
+	"compress/flate"
+	"io"
+	"os"
+
+	securezip "github.com/yeka/zip"
+)
+
+// entryWriter adds entries to a zip archive being streamed to the client
+type entryWriter interface {
+	// createDir adds an empty directory entry; name ends with "/"
+	createDir(name string) error
+	// createFile adds a file entry and returns the writer for its contents
+	createFile(name string, info os.FileInfo, store bool) (io.Writer, error)
+	// Close writes the central directory; without it the zip is unreadable
+	Close() error
+}
+
+// plainWriter writes an unencrypted zip using the requested compression level
+type plainWriter struct {
+	zw *zip.Writer
+}
+
+func newPlainWriter(w io.Writer, level int) *plainWriter {
+	zw := zip.NewWriter(w)
+	zw.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(out, level)
+	})
+	return &plainWriter{zw: zw}
+}
+
+func (p *plainWriter) createDir(name string) error {
+	_, err := p.zw.Create(name)
+	return err
+}
+
+func (p *plainWriter) createFile(name string, info os.FileInfo, store bool) (io.Writer, error) {
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return nil, err
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+	if store {
+		header.Method = zip.Store
+	}
+	return p.zw.CreateHeader(header)
+}
+
+func (p *plainWriter) Close() error {
+	return p.zw.Close()
+}
+
+// encryptedWriter writes a zip whose files are encrypted with WinZip AES-256, which
+// 7-Zip, WinZip and most archive tools can open. File names stay readable; only the
+// contents are protected. Deflated files always use the default compression level.
+type encryptedWriter struct {
+	zw       *securezip.Writer
+	password string
+}
+
+func newEncryptedWriter(w io.Writer, password string) *encryptedWriter {
+	return &encryptedWriter{zw: securezip.NewWriter(w), password: password}
+}
+
+func (e *encryptedWriter) createDir(name string) error {
+	_, err := e.zw.Create(name)
+	return err
+}
+
+func (e *encryptedWriter) createFile(name string, info os.FileInfo, store bool) (io.Writer, error) {
+	header, err := securezip.FileInfoHeader(info)
+	if err != nil {
+		return nil, err
+	}
+	header.Name = name
+	header.Method = securezip.Deflate
+	if store {
+		header.Method = securezip.Store
+	}
+	header.SetPassword(e.password)
+	header.SetEncryptionMethod(securezip.AES256Encryption)
+	return e.zw.CreateHeader(header)
+}
+
+func (e *encryptedWriter) Close() error {
+	return e.zw.Close()
+}
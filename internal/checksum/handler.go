@@ -0,0 +1,159 @@
+package checksum
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"simple.http.server/internal/config"
+	"simple.http.server/internal/pathutil"
+)
+
+// maxCacheEntries is the number of checksums kept in memory
+const maxCacheEntries = 1024
+
+// algorithms maps the algo parameter to a hash constructor
+var algorithms = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"md5":    md5.New,
+}
+
+// Result is the checksum of a file
+type Result struct {
+	Path string `json:"path"`
+	Algo string `json:"algo"`
+	Hex  string `json:"hex"`
+	Size int64  `json:"size"`
+}
+
+// Handler computes checksums of files in the served directory
+type Handler struct {
+	config *config.Config
+
+	mu    sync.Mutex
+	cache map[string]string // hex digest keyed by path, version and algorithm
+	order []string          // keys from oldest to newest
+}
+
+// NewHandler creates a new checksum handler
+func NewHandler(cfg *config.Config) *Handler {
+	return &Handler{
+		config: cfg,
+		cache:  make(map[string]string),
+	}
+}
+
+// ServeHTTP handles checksum requests
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filePath := r.URL.Query().Get("path")
+	if filePath == "" {
+		http.Error(w, "Path parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	algo := r.URL.Query().Get("algo")
+	if algo == "" {
+		algo = "sha256"
+	}
+	newHash, ok := algorithms[algo]
+	if !ok {
+		http.Error(w, "Query parameter 'algo' must be sha256 or md5", http.StatusBadRequest)
+		return
+	}
+
+	absBase, absFile, err := pathutil.Resolve(h.config.GetFileServerDir(), filePath)
+	if err != nil {
+		if errors.Is(err, pathutil.ErrOutsideRoot) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+		} else {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	info, err := os.Stat(absFile)
+	if err != nil || !info.Mode().IsRegular() {
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	}
+
+	// The modification time and size identify the file's version
+	key := absFile + "|" + info.ModTime().String() + "|" + strconv.FormatInt(info.Size(), 10) + "|" + algo
+	digest, ok := h.get(key)
+	if !ok {
+		digest, err = sum(absFile, newHash())
+		if err != nil {
+			log.Printf("Failed to compute checksum of %s: %v", absFile, err)
+			http.Error(w, "Failed to read file", http.StatusInternalServerError)
+			return
+		}
+		h.put(key, digest)
+	}
+
+	relPath, err := filepath.Rel(absBase, absFile)
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(Result{
+		Path: "/" + filepath.ToSlash(relPath),
+		Algo: algo,
+		Hex:  digest,
+		Size: info.Size(),
+	})
+}
+
+// sum streams a file through h and returns the digest in hex
+func sum(path string, h hash.Hash) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// get returns the digest stored under key
+func (h *Handler) get(key string) (string, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	digest, ok := h.cache[key]
+	return digest, ok
+}
+
+// put stores a digest under key, evicting the oldest entries beyond maxCacheEntries
+func (h *Handler) put(key, digest string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, exists := h.cache[key]; !exists {
+		h.order = append(h.order, key)
+	}
+	h.cache[key] = digest
+
+	for len(h.order) > maxCacheEntries {
+		delete(h.cache, h.order[0])
+		h.order = h.order[1:]
+	}
+}
@@ -0,0 +1,101 @@
+package checksum
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"simple.http.server/internal/config"
+)
+
+// newTestHandler returns a handler serving a temporary directory that holds
+// docs/hello.txt, with secret.txt next to the directory
+func newTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	if err := os.MkdirAll(filepath.Join(root, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "docs", "hello.txt"), []byte("hello world"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(base, "secret.txt"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := json.Marshal(map[string]interface{}{"file_server_dir": root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(cfg)
+}
+
+// get requests the checksum of path with algo, leaving algo out when it is empty
+func get(h *Handler, path, algo string) *httptest.ResponseRecorder {
+	query := url.Values{"path": {path}}
+	if algo != "" {
+		query.Set("algo", algo)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/checksum?"+query.Encode(), nil))
+	return rec
+}
+
+func TestChecksumOfKnownFile(t *testing.T) {
+	h := newTestHandler(t)
+
+	tests := []struct {
+		algo, wantAlgo, wantHex string
+	}{
+		{"", "sha256", "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"},
+		{"sha256", "sha256", "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"},
+		{"md5", "md5", "5eb63bbbe01eeed093cb22bb8f5acdc3"},
+	}
+	// Each request is made twice so the second answer comes from the cache
+	for _, tt := range tests {
+		for i := 0; i < 2; i++ {
+			rec := get(h, "/docs/hello.txt", tt.algo)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("algo %q: status = %d: %s", tt.algo, rec.Code, rec.Body)
+			}
+			var result Result
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatal(err)
+			}
+			want := Result{Path: "/docs/hello.txt", Algo: tt.wantAlgo, Hex: tt.wantHex, Size: 11}
+			if result != want {
+				t.Errorf("algo %q: got %+v, want %+v", tt.algo, result, want)
+			}
+		}
+	}
+}
+
+func TestChecksumRejectsBadRequests(t *testing.T) {
+	h := newTestHandler(t)
+
+	tests := []struct {
+		name, path, algo string
+		want             int
+	}{
+		{"traversal", "../secret.txt", "", http.StatusForbidden},
+		{"nested traversal", "docs/../../secret.txt", "md5", http.StatusForbidden},
+		{"missing path", "", "", http.StatusBadRequest},
+		{"unknown algorithm", "/docs/hello.txt", "sha1", http.StatusBadRequest},
+		{"missing file", "/docs/missing.txt", "", http.StatusNotFound},
+		{"folder", "/docs", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if rec := get(h, tt.path, tt.algo); rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
@@ -1,11 +1,21 @@
 package clipboard
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+
+	"simple.http.server/internal/config"
+	"simple.http.server/internal/qr"
 )
 
 // ClipItem represents a clipboard item
@@ -14,18 +24,35 @@ type ClipItem struct {
 	Content   string    `json:"content"`
 	CreatedAt time.Time `json:"created_at"`
 	ExpiresAt time.Time `json:"expires_at"`
+	Kind      string    `json:"kind"` // text, json, url, code or file
+
+	// File entries hold their bytes in Data and their file name in Content
+	Binary      bool   `json:"binary,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int    `json:"size,omitempty"`
+	Data        []byte `json:"-"` // served with ?raw=1
+
+	// Protected items need a password to be read; the list hides their content
+	Protected    bool   `json:"protected,omitempty"`
+	salt         []byte
+	passwordHash []byte
 }
 
+// maxFileSize limits the size of file entries
+const maxFileSize = 5 << 20 // 5 MB
+
 // Handler manages clipboard sharing
 type Handler struct {
 	mu        sync.RWMutex
 	clipboard map[string]*ClipItem
+	config    *config.Config
 }
 
 // NewHandler creates a new clipboard handler
-func NewHandler() *Handler {
+func NewHandler(cfg *config.Config) *Handler {
 	h := &Handler{
 		clipboard: make(map[string]*ClipItem),
+		config:    cfg,
 	}
 	
 	// Start cleanup goroutine
@@ -38,19 +65,30 @@ func NewHandler() *Handler {
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// CORS headers
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Clipboard-Password")
 
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
+	if r.URL.Path == "/api/clipboard/qr" {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.serveQR(w, r)
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		h.getClipboard(w, r)
 	case http.MethodPost:
 		h.setClipboard(w, r)
+	case http.MethodPut:
+		h.updateClipboard(w, r)
 	case http.MethodDelete:
 		h.clearClipboard(w, r)
 	default:
@@ -72,6 +110,21 @@ func (h *Handler) getClipboard(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Clipboard item not found or expired", http.StatusNotFound)
 			return
 		}
+		if !item.checkPassword(requestPassword(r)) {
+			http.Error(w, "Wrong or missing password", http.StatusForbidden)
+			return
+		}
+
+		// raw=1 serves the item itself rather than its JSON description,
+		// render=1 an HTML page that formats it according to its kind
+		if r.URL.Query().Get("raw") == "1" {
+			serveRaw(w, item)
+			return
+		}
+		if r.URL.Query().Get("render") == "1" {
+			serveRendered(w, item)
+			return
+		}
 		
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(item)
@@ -81,9 +134,16 @@ func (h *Handler) getClipboard(w http.ResponseWriter, r *http.Request) {
 	// Get all non-expired items
 	items := []*ClipItem{}
 	for _, item := range h.clipboard {
-		if time.Now().Before(item.ExpiresAt) {
-			items = append(items, item)
+		if !time.Now().Before(item.ExpiresAt) {
+			continue
 		}
+		if item.Protected {
+			// Listed without the content (or file name) it protects
+			hidden := *item
+			hidden.Content = ""
+			item = &hidden
+		}
+		items = append(items, item)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -93,8 +153,62 @@ func (h *Handler) getClipboard(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// serveQR renders an item as a QR code: text items encode their content,
+// file items a link to download the file
+func (h *Handler) serveQR(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+
+	h.mu.RLock()
+	item, exists := h.clipboard[id]
+	h.mu.RUnlock()
+
+	if !exists || time.Now().After(item.ExpiresAt) {
+		http.Error(w, "Clipboard item not found or expired", http.StatusNotFound)
+		return
+	}
+	if !item.checkPassword(requestPassword(r)) {
+		http.Error(w, "Wrong or missing password", http.StatusForbidden)
+		return
+	}
+
+	data := item.Content
+	if item.Binary {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		data = scheme + "://" + r.Host + "/api/clipboard?raw=1&id=" + url.QueryEscape(item.ID)
+	}
+	qr.WritePNG(w, r, data)
+}
+
+// serveRaw writes the content of an item with its own content type
+func serveRaw(w http.ResponseWriter, item *ClipItem) {
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if !item.Binary {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		io.WriteString(w, item.Content)
+		return
+	}
+
+	// Only images are shown inline; anything that can run scripts, such as HTML or SVG, is downloaded
+	disposition := "attachment"
+	if strings.HasPrefix(item.ContentType, "image/") && !strings.HasPrefix(item.ContentType, "image/svg") {
+		disposition = "inline"
+	}
+	w.Header().Set("Content-Type", item.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": item.Content}))
+	w.Write(item.Data)
+}
+
 // setClipboard saves content to clipboard
 func (h *Handler) setClipboard(w http.ResponseWriter, r *http.Request) {
+	// Files are sent as multipart/form-data, text as JSON
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		h.setClipboardFile(w, r)
+		return
+	}
+
 	// Read request body
 	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20)) // 1MB limit
 	if err != nil {
@@ -103,8 +217,9 @@ func (h *Handler) setClipboard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Content string `json:"content"`
-		TTL     int    `json:"ttl"` // Time to live in minutes (default: 60)
+		Content  string `json:"content"`
+		TTL      int    `json:"ttl"`      // Time to live in minutes (default: 60)
+		Password string `json:"password"` // optional, required to read the item
 	}
 
 	if err := json.Unmarshal(body, &req); err != nil {
@@ -117,23 +232,89 @@ func (h *Handler) setClipboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.saveItem(w, &ClipItem{Content: req.Content}, req.TTL, req.Password)
+}
+
+// setClipboardFile saves an uploaded file (form field "file") to the clipboard
+func (h *Handler) setClipboardFile(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxFileSize+1<<20) // room for the form around the file
+	if err := r.ParseMultipartForm(maxFileSize); err != nil {
+		http.Error(w, "File too large", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "File is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	if header.Size > maxFileSize {
+		http.Error(w, "File too large", http.StatusBadRequest)
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxFileSize))
+	if err != nil {
+		http.Error(w, "Failed to read file", http.StatusBadRequest)
+		return
+	}
+
+	// Trust the browser's content type unless it is missing or generic
+	contentType := header.Header.Get("Content-Type")
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(data)
+	}
+
+	name := filepath.Base(header.Filename)
+	if name == "." || name == string(filepath.Separator) {
+		name = "file"
+	}
+
+	ttl, _ := strconv.Atoi(r.FormValue("ttl"))
+	h.saveItem(w, &ClipItem{
+		Content:     name,
+		Binary:      true,
+		ContentType: contentType,
+		Size:        len(data),
+		Data:        data,
+	}, ttl, r.FormValue("password"))
+}
+
+// saveItem stores item with a new id, expiring after ttl minutes and protected by password
+// unless it is empty, and writes it to the response
+func (h *Handler) saveItem(w http.ResponseWriter, item *ClipItem, ttl int, password string) {
 	// Default TTL: 60 minutes
-	ttl := 60
-	if req.TTL > 0 && req.TTL <= 1440 { // Max 24 hours
-		ttl = req.TTL
+	if ttl <= 0 || ttl > 1440 { // Max 24 hours
+		ttl = 60
 	}
 
-	// Create clipboard item
-	now := time.Now()
-	item := &ClipItem{
-		ID:        generateID(),
-		Content:   req.Content,
-		CreatedAt: now,
-		ExpiresAt: now.Add(time.Duration(ttl) * time.Minute),
+	if password != "" {
+		if err := item.setPassword(password); err != nil {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	item.Kind = KindFile
+	if !item.Binary {
+		item.Kind = detectKind(item.Content)
+	}
+
+	id, err := generateID()
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
 	}
 
+	now := time.Now()
+	item.ID = id
+	item.CreatedAt = now
+	item.ExpiresAt = now.Add(time.Duration(ttl) * time.Minute)
+
 	h.mu.Lock()
 	h.clipboard[item.ID] = item
+	h.evictOldest(h.config.GetClipboardMax())
 	h.mu.Unlock()
 
 	w.Header().Set("Content-Type", "application/json")
@@ -141,7 +322,71 @@ func (h *Handler) setClipboard(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(item)
 }
 
-// clearClipboard removes clipboard content
+// updateClipboard changes the content of an item and optionally restarts its TTL, keeping its id
+func (h *Handler) updateClipboard(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "Query parameter 'id' is required", http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20)) // 1MB limit
+	if err != nil {
+		http.Error(w, "Failed to read request", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Content *string `json:"content"` // unchanged when omitted
+		TTL     int     `json:"ttl"`     // minutes from now; the expiry is unchanged when omitted
+	}
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if req.Content != nil && *req.Content == "" {
+		http.Error(w, "Content must not be empty", http.StatusBadRequest)
+		return
+	}
+	if req.TTL < 0 || req.TTL > 1440 { // Max 24 hours
+		http.Error(w, "TTL must be between 1 and 1440 minutes", http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	item, exists := h.clipboard[id]
+	if !exists || time.Now().After(item.ExpiresAt) {
+		http.Error(w, "Clipboard item not found or expired", http.StatusNotFound)
+		return
+	}
+	if !item.checkPassword(requestPassword(r)) {
+		http.Error(w, "Wrong or missing password", http.StatusForbidden)
+		return
+	}
+
+	// Replace rather than modify the item, so readers holding the old one never see a partial update
+	updated := *item
+	if req.Content != nil {
+		updated.Content = *req.Content
+		if !updated.Binary {
+			updated.Kind = detectKind(updated.Content)
+		}
+	}
+	if req.TTL > 0 {
+		updated.ExpiresAt = time.Now().Add(time.Duration(req.TTL) * time.Minute)
+	}
+	h.clipboard[id] = &updated
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&updated)
+}
+
+// clearClipboard removes clipboard content. Deleting a protected item needs its password,
+// and clearing everything leaves protected items in place.
 func (h *Handler) clearClipboard(w http.ResponseWriter, r *http.Request) {
 	id := r.URL.Query().Get("id")
 	
@@ -150,19 +395,42 @@ func (h *Handler) clearClipboard(w http.ResponseWriter, r *http.Request) {
 
 	if id != "" {
 		// Delete specific item
-		if _, exists := h.clipboard[id]; !exists {
+		item, exists := h.clipboard[id]
+		if !exists {
 			http.Error(w, "Clipboard item not found", http.StatusNotFound)
 			return
 		}
+		if !item.checkPassword(requestPassword(r)) {
+			http.Error(w, "Wrong or missing password", http.StatusForbidden)
+			return
+		}
 		delete(h.clipboard, id)
 	} else {
 		// Clear all
-		h.clipboard = make(map[string]*ClipItem)
+		for id, item := range h.clipboard {
+			if !item.Protected {
+				delete(h.clipboard, id)
+			}
+		}
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// evictOldest removes the items with the oldest CreatedAt until at most max remain.
+// The caller must hold h.mu.
+func (h *Handler) evictOldest(max int) {
+	for len(h.clipboard) > max {
+		var oldest *ClipItem
+		for _, item := range h.clipboard {
+			if oldest == nil || item.CreatedAt.Before(oldest.CreatedAt) {
+				oldest = item
+			}
+		}
+		delete(h.clipboard, oldest.ID)
+	}
+}
+
 // cleanupExpired removes expired clipboard items
 func (h *Handler) cleanupExpired() {
 	ticker := time.NewTicker(5 * time.Minute)
@@ -180,7 +448,12 @@ func (h *Handler) cleanupExpired() {
 	}
 }
 
-// generateID generates a simple unique ID
-func generateID() string {
-	return time.Now().Format("20060102150405")
+// generateID generates a random ID, so items saved within the same second stay apart
+// and IDs cannot be guessed
+func generateID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
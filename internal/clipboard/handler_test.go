@@ -0,0 +1,334 @@
+package clipboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"simple.http.server/internal/config"
+)
+
+// newTestHandler returns a clipboard handler with the default settings
+func newTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	return newTestHandlerWith(t, `{}`)
+}
+
+// newTestHandlerWith returns a clipboard handler with the given JSON settings
+func newTestHandlerWith(t *testing.T, settings string) *Handler {
+	t.Helper()
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings([]byte(settings)); err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(cfg)
+}
+
+// postItem saves content, protected by password when it is not empty, and returns the new item
+func postItem(t *testing.T, h *Handler, content, password string) ClipItem {
+	t.Helper()
+	body, _ := json.Marshal(map[string]string{"content": content, "password": password})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/clipboard", strings.NewReader(string(body))))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST: status = %d: %s", rec.Code, rec.Body)
+	}
+	var item ClipItem
+	if err := json.NewDecoder(rec.Body).Decode(&item); err != nil {
+		t.Fatal(err)
+	}
+	return item
+}
+
+func TestItemsSavedTogetherGetDistinctIDs(t *testing.T) {
+	h := newTestHandler(t)
+	seen := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		item := postItem(t, h, "item", "")
+		if seen[item.ID] {
+			t.Fatalf("id %q returned twice", item.ID)
+		}
+		seen[item.ID] = true
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if len(h.clipboard) != len(seen) {
+		t.Errorf("clipboard holds %d items, want %d", len(h.clipboard), len(seen))
+	}
+}
+
+// deleteItem sends a DELETE for id, or for everything when id is empty, and returns the status
+func deleteItem(h *Handler, id, password string) int {
+	target := "/api/clipboard"
+	if id != "" {
+		target += "?id=" + id
+	}
+	req := httptest.NewRequest(http.MethodDelete, target, nil)
+	if password != "" {
+		req.Header.Set("X-Clipboard-Password", password)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+// hasItem reports whether the item with id is still stored
+func hasItem(h *Handler, id string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	_, ok := h.clipboard[id]
+	return ok
+}
+
+func TestDeleteProtectedItemNeedsPassword(t *testing.T) {
+	h := newTestHandler(t)
+	item := postItem(t, h, "secret", "hunter2")
+
+	if code := deleteItem(h, item.ID, ""); code != http.StatusForbidden {
+		t.Errorf("DELETE without password: status = %d, want 403", code)
+	}
+	if code := deleteItem(h, item.ID, "wrong"); code != http.StatusForbidden {
+		t.Errorf("DELETE with wrong password: status = %d, want 403", code)
+	}
+	if !hasItem(h, item.ID) {
+		t.Fatal("protected item was deleted without its password")
+	}
+	if code := deleteItem(h, item.ID, "hunter2"); code != http.StatusNoContent {
+		t.Errorf("DELETE with password: status = %d, want 204", code)
+	}
+	if hasItem(h, item.ID) {
+		t.Error("item still stored after deleting it with its password")
+	}
+}
+
+func TestClearAllKeepsProtectedItems(t *testing.T) {
+	h := newTestHandler(t)
+	open := postItem(t, h, "open", "")
+	protected := postItem(t, h, "secret", "hunter2")
+
+	if code := deleteItem(h, "", ""); code != http.StatusNoContent {
+		t.Fatalf("DELETE all: status = %d, want 204", code)
+	}
+	if hasItem(h, open.ID) {
+		t.Error("unprotected item survived clearing")
+	}
+	if !hasItem(h, protected.ID) {
+		t.Error("protected item was cleared without its password")
+	}
+}
+
+func TestExpiredItemIsNotFound(t *testing.T) {
+	h := newTestHandler(t)
+	item := postItem(t, h, "short lived", "")
+
+	h.mu.Lock()
+	h.clipboard[item.ID].ExpiresAt = time.Now().Add(-time.Second)
+	h.mu.Unlock()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/clipboard?id="+item.ID, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET expired item: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestCapEvictsOldestItems(t *testing.T) {
+	h := newTestHandlerWith(t, `{"clipboard_max_items": 3}`)
+	var items []ClipItem
+	for i := 0; i < 5; i++ {
+		items = append(items, postItem(t, h, fmt.Sprintf("item %d", i), ""))
+	}
+
+	for i, item := range items {
+		if got, want := hasItem(h, item.ID), i >= 2; got != want {
+			t.Errorf("item %d stored = %v, want %v", i, got, want)
+		}
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if len(h.clipboard) != 3 {
+		t.Errorf("clipboard holds %d items, want the cap of 3", len(h.clipboard))
+	}
+}
+
+// putItem sends an update for id with the given JSON body
+func putItem(h *Handler, id, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/clipboard?id="+id, strings.NewReader(body)))
+	return rec
+}
+
+func TestUpdateItem(t *testing.T) {
+	h := newTestHandler(t)
+	item := postItem(t, h, "first draft", "")
+
+	// Changing the content keeps the id and expiry
+	rec := putItem(h, item.ID, `{"content":"second draft"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT content: status = %d: %s", rec.Code, rec.Body)
+	}
+	var updated ClipItem
+	if err := json.NewDecoder(rec.Body).Decode(&updated); err != nil {
+		t.Fatal(err)
+	}
+	if updated.ID != item.ID || updated.Content != "second draft" || !updated.ExpiresAt.Equal(item.ExpiresAt) {
+		t.Errorf("updated = %+v, want the new content with id and expiry kept", updated)
+	}
+	h.mu.RLock()
+	stored := h.clipboard[item.ID].Content
+	h.mu.RUnlock()
+	if stored != "second draft" {
+		t.Errorf("stored content = %q, want the update", stored)
+	}
+
+	// A new TTL extends the expiry
+	rec = putItem(h, item.ID, `{"ttl":600}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT ttl: status = %d: %s", rec.Code, rec.Body)
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&updated); err != nil {
+		t.Fatal(err)
+	}
+	if updated.Content != "second draft" || time.Until(updated.ExpiresAt) < 599*time.Minute {
+		t.Errorf("updated = %+v, want the content kept and 600 minutes left", updated)
+	}
+
+	for _, body := range []string{`{"content":""}`, `{"ttl":-1}`, `{"ttl":5000}`, `not json`} {
+		if rec := putItem(h, item.ID, body); rec.Code != http.StatusBadRequest {
+			t.Errorf("PUT %s: status = %d, want 400", body, rec.Code)
+		}
+	}
+	if rec := putItem(h, "unknown", `{"content":"x"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("PUT unknown id: status = %d, want 404", rec.Code)
+	}
+
+	h.mu.Lock()
+	h.clipboard[item.ID].ExpiresAt = time.Now().Add(-time.Second)
+	h.mu.Unlock()
+	if rec := putItem(h, item.ID, `{"ttl":60}`); rec.Code != http.StatusNotFound {
+		t.Errorf("PUT expired item: status = %d, want 404", rec.Code)
+	}
+}
+
+// pngImage returns a small encoded PNG
+func pngImage(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestFileItemRoundTrip(t *testing.T) {
+	h := newTestHandler(t)
+	data := pngImage(t)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", "dot.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write(data)
+	mw.Close()
+	req := httptest.NewRequest(http.MethodPost, "/api/clipboard", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST: status = %d: %s", rec.Code, rec.Body)
+	}
+	var item ClipItem
+	if err := json.NewDecoder(rec.Body).Decode(&item); err != nil {
+		t.Fatal(err)
+	}
+	if !item.Binary || item.Content != "dot.png" || item.ContentType != "image/png" || item.Size != len(data) || item.Kind != KindFile {
+		t.Errorf("item = %+v, want a %d byte PNG file", item, len(data))
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/clipboard?raw=1&id="+item.ID, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET raw: status = %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "image/png" {
+		t.Errorf("Content-Type = %q, want image/png", got)
+	}
+	if !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Error("raw body differs from the uploaded PNG")
+	}
+	if _, err := png.Decode(rec.Body); err != nil {
+		t.Errorf("raw body is not a PNG: %v", err)
+	}
+}
+
+func TestProtectedItemNeedsPasswordToRead(t *testing.T) {
+	h := newTestHandler(t)
+	item := postItem(t, h, "the launch code", "hunter2")
+	if !item.Protected {
+		t.Error("item is not marked protected")
+	}
+
+	get := func(query, header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/clipboard?id="+item.ID+query, nil)
+		if header != "" {
+			req.Header.Set("X-Clipboard-Password", header)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	for _, tt := range []struct{ query, header string }{{"", ""}, {"&password=wrong", ""}, {"", "wrong"}, {"&raw=1", ""}} {
+		if rec := get(tt.query, tt.header); rec.Code != http.StatusForbidden || strings.Contains(rec.Body.String(), "launch code") {
+			t.Errorf("query %q, header %q: status = %d, want 403 without the content", tt.query, tt.header, rec.Code)
+		}
+	}
+	for _, tt := range []struct{ query, header string }{{"&password=hunter2", ""}, {"", "hunter2"}} {
+		rec := get(tt.query, tt.header)
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "the launch code") {
+			t.Errorf("query %q, header %q: status = %d, body %q, want the content", tt.query, tt.header, rec.Code, rec.Body)
+		}
+	}
+
+	// The list marks the item without revealing its content or hash
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/clipboard", nil))
+	if body := rec.Body.String(); strings.Contains(body, "launch code") || !strings.Contains(body, `"protected":true`) {
+		t.Errorf("list = %s, want the item marked protected without its content", body)
+	}
+	if body := rec.Body.String(); strings.Contains(body, "hash") || strings.Contains(body, "salt") {
+		t.Errorf("list = %s, want no password hash", body)
+	}
+}
+
+func TestItemQRCode(t *testing.T) {
+	h := newTestHandler(t)
+	item := postItem(t, h, "https://example.com/shared", "")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/clipboard/qr?id="+item.ID, nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("status = %d, Content-Type = %q, want a PNG", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if img, err := png.Decode(rec.Body); err != nil || img.Bounds().Empty() {
+		t.Errorf("invalid PNG: %v", err)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/clipboard/qr?id=unknown", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown id: status = %d, want 404", rec.Code)
+	}
+}
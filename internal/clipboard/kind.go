@@ -0,0 +1,114 @@
+package clipboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Kinds of clipboard content, detected when an item is saved
+const (
+	KindText = "text"
+	KindJSON = "json"
+	KindURL  = "url"
+	KindCode = "code"
+	KindFile = "file"
+)
+
+// codeLine matches lines that are typical of source code rather than prose
+var codeLine = regexp.MustCompile(`^\s*(` +
+	`(func|def|class|import|package|return|const|let|var|function|public|private|if|for|while)\b` +
+	`|#include|//|/\*|\}|\{` +
+	`)|[;{]\s*$|=>`)
+
+// detectKind guesses what text content is: a JSON document, a single http(s) URL,
+// source code, or otherwise plain text
+func detectKind(content string) string {
+	trimmed := strings.TrimSpace(content)
+
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		if json.Valid([]byte(trimmed)) {
+			return KindJSON
+		}
+	}
+
+	if isURL(trimmed) {
+		return KindURL
+	}
+
+	// Code needs a few lines of evidence, so a sentence ending in ";" stays text
+	lines := strings.Split(trimmed, "\n")
+	codeLines := 0
+	for _, line := range lines {
+		if codeLine.MatchString(line) {
+			codeLines++
+		}
+	}
+	if codeLines >= 2 && codeLines*3 >= len(lines) {
+		return KindCode
+	}
+	return KindText
+}
+
+// isURL reports whether s is a single absolute http or https URL
+func isURL(s string) bool {
+	if s == "" || strings.ContainsAny(s, " \t\r\n") {
+		return false
+	}
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// serveRendered writes a text item as an HTML page suited to its kind:
+// JSON is pretty-printed, a URL becomes a link, anything else is shown as-is
+func serveRendered(w http.ResponseWriter, item *ClipItem) {
+	if item.Binary {
+		serveRaw(w, item)
+		return
+	}
+
+	var body string
+	switch item.Kind {
+	case KindJSON:
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, []byte(item.Content), "", "  "); err == nil {
+			body = "<pre>" + html.EscapeString(pretty.String()) + "</pre>"
+			break
+		}
+		body = "<pre>" + html.EscapeString(item.Content) + "</pre>"
+	case KindURL:
+		link := html.EscapeString(strings.TrimSpace(item.Content))
+		body = fmt.Sprintf(`<p><a href="%s" rel="noopener noreferrer">%s</a></p>`, link, link)
+	case KindCode:
+		body = "<pre><code>" + html.EscapeString(item.Content) + "</code></pre>"
+	default:
+		body = `<pre class="text">` + html.EscapeString(item.Content) + "</pre>"
+	}
+
+	// Content is user-supplied, so nothing on the page may load or run anything
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, `<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Clipboard: %s</title>
+    <style>
+        body { margin: 0; padding: 20px; background: #f8f9fa; color: #1e2939; font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Arial, sans-serif; }
+        pre { background: white; border: 1px solid #e8eaed; border-radius: 4px; padding: 16px; overflow-x: auto; font-family: 'Monaco', 'Menlo', 'Courier New', monospace; font-size: 14px; }
+        pre.text { white-space: pre-wrap; word-wrap: break-word; font-family: inherit; }
+        a { color: #3498db; font-size: 18px; word-break: break-all; }
+    </style>
+</head>
+<body>
+    %s
+</body>
+</html>`, html.EscapeString(item.Kind), body)
+}
@@ -0,0 +1,67 @@
+package clipboard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDetectKind(t *testing.T) {
+	tests := []struct {
+		content, want string
+	}{
+		{`{"name": "server", "ports": [8080, 8443]}`, KindJSON},
+		{"  [1, 2, 3]\n", KindJSON},
+		{`{"unterminated": `, KindText},
+		{"https://example.com/a?b=c", KindURL},
+		{"  http://localhost:8080/\n", KindURL},
+		{"ftp://example.com/file", KindText},
+		{"see https://example.com for details", KindText},
+		{"func main() {\n\tfmt.Println(\"hi\")\n}\n", KindCode},
+		{"Remember to buy milk; and eggs.", KindText},
+		{"just some notes", KindText},
+	}
+	for _, tt := range tests {
+		if got := detectKind(tt.content); got != tt.want {
+			t.Errorf("detectKind(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestItemKindAndRender(t *testing.T) {
+	h := newTestHandler(t)
+
+	render := func(id string) string {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/clipboard?render=1&id="+id, nil))
+		if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+			t.Fatalf("render %s: status = %d, Content-Type = %q", id, rec.Code, rec.Header().Get("Content-Type"))
+		}
+		return rec.Body.String()
+	}
+
+	doc := postItem(t, h, `{"a":1,"b":[true]}`, "")
+	if doc.Kind != KindJSON {
+		t.Errorf("JSON item kind = %q, want json", doc.Kind)
+	}
+	if body := render(doc.ID); !strings.Contains(body, "{\n  &#34;a&#34;: 1,") {
+		t.Errorf("rendered JSON is not pretty-printed:\n%s", body)
+	}
+
+	link := postItem(t, h, "https://example.com/?q=<b>", "")
+	if link.Kind != KindURL {
+		t.Errorf("URL item kind = %q, want url", link.Kind)
+	}
+	if body := render(link.ID); !strings.Contains(body, `<a href="https://example.com/?q=&lt;b&gt;"`) {
+		t.Errorf("rendered URL is not an escaped link:\n%s", body)
+	}
+
+	text := postItem(t, h, "<script>alert(1)</script> plain", "")
+	if text.Kind != KindText {
+		t.Errorf("text item kind = %q, want text", text.Kind)
+	}
+	if body := render(text.ID); strings.Contains(body, "<script>") {
+		t.Errorf("rendered text is not escaped:\n%s", body)
+	}
+}
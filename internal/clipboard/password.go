@@ -0,0 +1,43 @@
+package clipboard
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+)
+
+// setPassword protects the item with password, storing only a salted hash
+func (item *ClipItem) setPassword(password string) error {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	item.Protected = true
+	item.salt = salt
+	item.passwordHash = hashPassword(salt, password)
+	return nil
+}
+
+// checkPassword reports whether password unlocks the item; unprotected items need none
+func (item *ClipItem) checkPassword(password string) bool {
+	if !item.Protected {
+		return true
+	}
+	return subtle.ConstantTimeCompare(hashPassword(item.salt, password), item.passwordHash) == 1
+}
+
+// hashPassword returns the SHA-256 hash of salt followed by password
+func hashPassword(salt []byte, password string) []byte {
+	sum := sha256.Sum256(append(append([]byte(nil), salt...), password...))
+	return sum[:]
+}
+
+// requestPassword returns the password sent with a request, from the X-Clipboard-Password
+// header or the password query parameter
+func requestPassword(r *http.Request) string {
+	if password := r.Header.Get("X-Clipboard-Password"); password != "" {
+		return password
+	}
+	return r.URL.Query().Get("password")
+}
@@ -0,0 +1,244 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"simple.http.server/internal/config"
+
+	"github.com/andybalholm/brotli"
+)
+
+// brotliLevel trades some of brotli's ratio for a speed close to gzip's default level
+const brotliLevel = 5
+
+// compressibleTypes are the content type prefixes that are compressed. Media and archives
+// are already compressed, and text/event-stream is excluded so SSE is never buffered.
+var compressibleTypes = []string{
+	"text/html",
+	"text/css",
+	"text/plain",
+	"text/csv",
+	"text/javascript",
+	"text/markdown",
+	"text/xml",
+	"application/json",
+	"application/javascript",
+	"application/xml",
+	"image/svg+xml",
+}
+
+// encoder is a compressing writer that can be reset and reused for another response
+type encoder interface {
+	io.Writer
+	Flush() error
+	Close() error
+	Reset(w io.Writer)
+}
+
+// encoderPools holds reusable encoders for each supported Content-Encoding
+var encoderPools = map[string]*sync.Pool{
+	"br": {New: func() interface{} {
+		return brotli.NewWriterLevel(nil, brotliLevel)
+	}},
+	"gzip": {New: func() interface{} {
+		return gzip.NewWriter(nil)
+	}},
+}
+
+// Compressor compresses text responses for clients that accept brotli or gzip
+type Compressor struct {
+	config *config.Config
+}
+
+// New creates a compressor using the minimum size configured in cfg
+func New(cfg *config.Config) *Compressor {
+	return &Compressor{config: cfg}
+}
+
+// Wrap returns a handler that encodes text responses of at least the configured minimum
+// size with brotli when the client accepts it, and with gzip otherwise
+func (c *Compressor) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Range responses must stay byte-for-byte, and HEAD has no body to compress
+		encoding := negotiate(r)
+		if encoding == "" || r.Header.Get("Range") != "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: c.config.GetCompressMinBytes()}
+		defer cw.finish()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiate picks the Content-Encoding for a response: brotli when the client accepts
+// it, gzip when only that is accepted, or "" to send the response as it is
+func negotiate(r *http.Request) string {
+	header := r.Header.Get("Accept-Encoding")
+	if accepts(header, "br") {
+		return "br"
+	}
+	if accepts(header, "gzip") {
+		return "gzip"
+	}
+	return ""
+}
+
+// accepts reports whether an Accept-Encoding header allows coding
+func accepts(header, coding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		if strings.TrimSpace(params[0]) != coding {
+			continue
+		}
+		// e.g. "gzip;q=0" explicitly refuses gzip
+		for _, param := range params[1:] {
+			if q, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// compressWriter buffers the start of a response until it knows whether to compress it
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string // Content-Encoding used if the response is compressed
+	minSize  int    // smallest response worth compressing; smaller ones are sent as they are
+	status   int
+	buf      []byte
+	decided  bool
+	enc      encoder // set once compression has started
+}
+
+func (c *compressWriter) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+	}
+	// Bodiless and partial responses are passed through untouched
+	if status == http.StatusNoContent || status == http.StatusNotModified || status == http.StatusPartialContent {
+		c.decide(false)
+	}
+}
+
+func (c *compressWriter) Write(b []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	if !c.decided {
+		c.buf = append(c.buf, b...)
+		if len(c.buf) < c.minSize {
+			return len(b), nil
+		}
+		c.decide(c.compressible())
+		if err := c.flushBuffer(); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if c.enc != nil {
+		return c.enc.Write(b)
+	}
+	return c.ResponseWriter.Write(b)
+}
+
+// compressible reports whether the buffered response should be compressed
+func (c *compressWriter) compressible() bool {
+	header := c.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(c.buf)
+		header.Set("Content-Type", contentType)
+	}
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// decide commits to compressing or not and writes the response header
+func (c *compressWriter) decide(compress bool) {
+	if c.decided {
+		return
+	}
+	c.decided = true
+
+	header := c.Header()
+	header.Add("Vary", "Accept-Encoding")
+	if compress {
+		header.Set("Content-Encoding", c.encoding)
+		header.Del("Content-Length")
+		c.enc = encoderPools[c.encoding].Get().(encoder)
+		c.enc.Reset(c.ResponseWriter)
+	}
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	c.ResponseWriter.WriteHeader(c.status)
+}
+
+// flushBuffer writes out whatever was buffered before the decision
+func (c *compressWriter) flushBuffer() error {
+	buf := c.buf
+	c.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if c.enc != nil {
+		_, err := c.enc.Write(buf)
+		return err
+	}
+	_, err := c.ResponseWriter.Write(buf)
+	return err
+}
+
+// Flush sends everything written so far, giving up on compressing a still-small response
+func (c *compressWriter) Flush() {
+	if !c.decided {
+		c.decide(false)
+		c.flushBuffer()
+	}
+	if c.enc != nil {
+		c.enc.Flush()
+	}
+	if flusher, ok := c.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// finish sends a response that stayed below the minimum size and closes the encoder
+func (c *compressWriter) finish() {
+	if !c.decided {
+		if c.status == 0 && len(c.buf) == 0 {
+			return // Nothing was written; let net/http send its default response
+		}
+		c.decide(false)
+		c.flushBuffer()
+	}
+	if c.enc != nil {
+		c.enc.Close()
+		c.enc.Reset(nil)
+		encoderPools[c.encoding].Put(c.enc)
+		c.enc = nil
+	}
+}
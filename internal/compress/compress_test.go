@@ -0,0 +1,190 @@
+package compress
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"simple.http.server/internal/config"
+
+	"github.com/andybalholm/brotli"
+)
+
+// newTestCompressor returns a compressor with the given JSON settings
+func newTestCompressor(t *testing.T, settings string) *Compressor {
+	t.Helper()
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings([]byte(settings)); err != nil {
+		t.Fatal(err)
+	}
+	return New(cfg)
+}
+
+// serveBody returns a handler answering with body as contentType
+func serveBody(contentType, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		io.WriteString(w, body)
+	})
+}
+
+// fetch sends a GET accepting acceptEncoding through h and returns the recorded response
+func fetch(h http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// largeHTML is a listing-sized page, well above the default minimum size
+var largeHTML = "<html><body>" + strings.Repeat("<tr><td>file.txt</td><td>1.0 KB</td></tr>\n", 500) + "</body></html>"
+
+func TestGzipsLargeHTML(t *testing.T) {
+	c := newTestCompressor(t, `{}`)
+	rec := fetch(c.Wrap(serveBody("text/html; charset=utf-8", largeHTML)), "gzip, deflate")
+
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("Vary = %q, want Accept-Encoding", got)
+	}
+	if rec.Body.Len() >= len(largeHTML) {
+		t.Errorf("compressed body is %d bytes, not smaller than %d", rec.Body.Len(), len(largeHTML))
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, err := io.ReadAll(zr); err != nil || string(body) != largeHTML {
+		t.Errorf("decompressed body differs from the page: %v", err)
+	}
+}
+
+func TestLeavesOtherResponsesAlone(t *testing.T) {
+	c := newTestCompressor(t, `{}`)
+	tests := []struct {
+		name           string
+		handler        http.Handler
+		acceptEncoding string
+	}{
+		{"no Accept-Encoding", serveBody("text/html", largeHTML), ""},
+		{"gzip refused", serveBody("text/html", largeHTML), "gzip;q=0"},
+		{"small page", serveBody("text/html", "<p>hi</p>"), "gzip"},
+		{"JPEG image", serveBody("image/jpeg", largeHTML), "gzip"},
+		{"zip archive", serveBody("application/zip", largeHTML), "gzip"},
+	}
+	for _, tt := range tests {
+		rec := fetch(c.Wrap(tt.handler), tt.acceptEncoding)
+		if got := rec.Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("%s: Content-Encoding = %q, want none", tt.name, got)
+		}
+		if rec.Body.Len() == 0 {
+			t.Errorf("%s: body is empty", tt.name)
+		}
+	}
+}
+
+func TestNeverCompressesSSE(t *testing.T) {
+	c := newTestCompressor(t, `{}`)
+	event := "data: " + strings.Repeat("x", 4096) + "\n\n"
+	stream := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, event)
+		w.(http.Flusher).Flush()
+	})
+
+	rec := fetch(c.Wrap(stream), "gzip")
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want the stream sent as it is", got)
+	}
+	if !rec.Flushed || rec.Body.String() != event {
+		t.Errorf("flushed %v, body of %d bytes; want the event flushed unchanged", rec.Flushed, rec.Body.Len())
+	}
+}
+
+// decode returns the body of rec decoded according to its Content-Encoding
+func decode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var r io.Reader = rec.Body
+	switch encoding := rec.Header().Get("Content-Encoding"); encoding {
+	case "br":
+		r = brotli.NewReader(rec.Body)
+	case "gzip":
+		zr, err := gzip.NewReader(rec.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = zr
+	case "":
+	default:
+		t.Fatalf("unexpected Content-Encoding %q", encoding)
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+	return string(body)
+}
+
+func TestPrefersBrotli(t *testing.T) {
+	c := newTestCompressor(t, `{}`)
+	largeJSON := `{"entries":[` + strings.Repeat(`{"name":"file.txt","size":1024},`, 200) + `{}]}`
+
+	tests := []struct {
+		acceptEncoding string
+		contentType    string
+		body           string
+		want           string
+	}{
+		{"gzip, deflate, br", "text/html; charset=utf-8", largeHTML, "br"},
+		{"br", "application/json", largeJSON, "br"},
+		{"gzip, deflate", "text/html; charset=utf-8", largeHTML, "gzip"},
+		{"br;q=0, gzip", "text/html; charset=utf-8", largeHTML, "gzip"},
+	}
+	for _, tt := range tests {
+		rec := fetch(c.Wrap(serveBody(tt.contentType, tt.body)), tt.acceptEncoding)
+		if got := rec.Header().Get("Content-Encoding"); got != tt.want {
+			t.Errorf("%q: Content-Encoding = %q, want %q", tt.acceptEncoding, got, tt.want)
+			continue
+		}
+		if rec.Body.Len() >= len(tt.body) {
+			t.Errorf("%q: compressed body is %d bytes, not smaller than %d", tt.acceptEncoding, rec.Body.Len(), len(tt.body))
+		}
+		if got := decode(t, rec); got != tt.body {
+			t.Errorf("%q: decoded body differs from the response", tt.acceptEncoding)
+		}
+	}
+
+	// Media stays as it is for brotli clients too
+	if got := fetch(c.Wrap(serveBody("image/png", largeHTML)), "br").Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("PNG: Content-Encoding = %q, want none", got)
+	}
+}
+
+func TestConfigurableMinSize(t *testing.T) {
+	small := strings.Repeat("<p>hi</p>", 20) // 180 bytes
+
+	c := newTestCompressor(t, `{"compress_min_bytes": 100}`)
+	rec := fetch(c.Wrap(serveBody("text/html", small)), "br")
+	if got := rec.Header().Get("Content-Encoding"); got != "br" || decode(t, rec) != small {
+		t.Errorf("above a 100 byte minimum: Content-Encoding = %q, want br", got)
+	}
+
+	c = newTestCompressor(t, fmt.Sprintf(`{"compress_min_bytes": %d}`, len(largeHTML)+1))
+	rec = fetch(c.Wrap(serveBody("text/html", largeHTML)), "br, gzip")
+	if got := rec.Header().Get("Content-Encoding"); got != "" || rec.Body.String() != largeHTML {
+		t.Errorf("below a raised minimum: Content-Encoding = %q, want the page unencoded", got)
+	}
+
+	if err := (&config.Config{}).ImportSettings([]byte(`{"compress_min_bytes": -1}`)); err == nil {
+		t.Error("a negative compress_min_bytes was accepted")
+	}
+}
@@ -2,7 +2,13 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 )
 
 // ProxyRule represents a reverse proxy configuration
@@ -10,29 +16,187 @@ type ProxyRule struct {
 	ID          string `json:"id"`
 	PathPrefix  string `json:"path_prefix"`  // e.g., "/api" (optional if Port is set)
 	Port        int    `json:"port"`         // e.g., 8081 (optional, enables port-based proxying)
+	Host        string `json:"host"`         // e.g., "app.localhost" (optional, matched before path-only rules)
 	TargetURL   string `json:"target_url"`   // e.g., "http://localhost:3000"
 	StripPrefix bool   `json:"strip_prefix"` // whether to strip the path prefix when proxying
+	Enabled     bool   `json:"enabled"`      // disabled rules are kept but never matched
+
+	// TargetURLs lists additional targets; requests rotate round-robin across all targets
+	TargetURLs []string `json:"target_urls,omitempty"`
+
+	// RewriteFrom is a regexp matched against the request path (after StripPrefix);
+	// matches are replaced with RewriteTo, which may reference groups as $1
+	RewriteFrom string `json:"rewrite_from,omitempty"`
+	RewriteTo   string `json:"rewrite_to,omitempty"`
+
+	// Headers are set on every proxied request; an empty value removes the header
+	Headers map[string]string `json:"headers,omitempty"`
+
+	DialTimeout     int `json:"dial_timeout,omitempty"`     // seconds to wait for a connection to the target (0 uses the default)
+	ResponseTimeout int `json:"response_timeout,omitempty"` // seconds to wait for response headers (0 waits indefinitely)
+	MaxRetries      int `json:"max_retries,omitempty"`      // retries for GET/HEAD requests that fail to connect
+
+	// Certificate checks for https targets, which are verified against the system roots by
+	// default. CAFile names a PEM file of extra trusted CAs, e.g. for a private CA;
+	// InsecureSkipVerify accepts any certificate, such as a self-signed one.
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+	CAFile             string `json:"ca_file,omitempty"`
+
+	// Priority orders matching: higher priorities are tried first, then longer path prefixes
+	Priority int `json:"priority,omitempty"`
+
+	// DebugCapture records the start of request and response bodies in the admin request log
+	DebugCapture bool `json:"debug_capture,omitempty"`
+}
+
+// Targets returns every target URL of the rule, starting with TargetURL
+func (r ProxyRule) Targets() []string {
+	targets := make([]string, 0, 1+len(r.TargetURLs))
+	if r.TargetURL != "" {
+		targets = append(targets, r.TargetURL)
+	}
+	for _, target := range r.TargetURLs {
+		if target != "" && target != r.TargetURL {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// UnmarshalJSON decodes a proxy rule, treating rules without an "enabled" field as enabled
+func (r *ProxyRule) UnmarshalJSON(data []byte) error {
+	type rawRule ProxyRule
+	raw := rawRule{Enabled: true}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*r = ProxyRule(raw)
+	return nil
 }
 
 // Settings represents the application configuration
 type Settings struct {
-	ProxyRules     []ProxyRule `json:"proxy_rules"`
-	FileServerPort int         `json:"file_server_port"`
-	FileServerDir  string      `json:"file_server_dir"`
+	ProxyRules      []ProxyRule `json:"proxy_rules"`
+	FileServerPort  int         `json:"file_server_port"`
+	FileServerDir   string      `json:"file_server_dir"`
+	AutoIndex       bool        `json:"auto_index"`        // serve index.html instead of a directory listing
+	DisableListing  bool        `json:"disable_listing"`   // never list directories; those without an index.html are forbidden
+	PreviewMaxBytes int64       `json:"preview_max_bytes"` // maximum bytes read for text, code and CSV previews
+	MaxUploadBytes  int64       `json:"max_upload_bytes"`  // maximum size of an upload request
+	PreviewLinks    bool        `json:"preview_links"`     // link code and text file names to their preview instead of the raw file
+	WatchIgnore     []string    `json:"watch_ignore"`      // glob patterns for paths the file watcher ignores
+	WatchPoll       bool        `json:"watch_poll"`        // poll for changes instead of using fsnotify
+	WatchDebounceMs int         `json:"watch_debounce_ms"` // delay before a burst of changes is broadcast (0 sends immediately)
+	SSEKeepAliveMs  int         `json:"sse_keepalive_ms"`  // interval between SSE keep-alive comments
+
+	ShowHidden bool `json:"show_hidden"` // list entries starting with "." unless ?hidden=0 is given
+
+	MediaMaxAge int `json:"media_max_age"` // seconds browsers may cache images, fonts, audio and video; 0 disables
+
+	CompressMinBytes int `json:"compress_min_bytes"` // text responses smaller than this are sent uncompressed
+
+	// Upload extension filters, e.g. [".jpg", ".png"]; an empty allow list permits everything not denied
+	UploadAllowExtensions []string `json:"upload_allow_extensions"`
+	UploadDenyExtensions  []string `json:"upload_deny_extensions"`
+
+	ClipboardMax int `json:"clipboard_max_items"` // oldest clipboard items are evicted beyond this count
+
+	// Requests per minute allowed from each client IP; 0 disables the limit.
+	// Uploads and archive downloads count against RateLimitExpensive instead of RateLimit.
+	RateLimit          int `json:"rate_limit"`
+	RateLimitExpensive int `json:"rate_limit_expensive"`
+
+	// Server timeouts in seconds, applied at startup; 0 disables a timeout.
+	// Live update streams, uploads, archives, file downloads and proxied requests
+	// are exempt from the read and write timeouts.
+	ReadHeaderTimeout int `json:"read_header_timeout"`
+	ReadTimeout       int `json:"read_timeout"`
+	WriteTimeout      int `json:"write_timeout"`
+	IdleTimeout       int `json:"idle_timeout"`
+}
+
+// ServerTimeouts are the HTTP server's timeouts in seconds; 0 disables a timeout
+type ServerTimeouts struct {
+	ReadHeader int
+	Read       int
+	Write      int
+	Idle       int
+}
+
+// validate checks settings loaded from a file or import
+func (s Settings) validate() error {
+	if s.WatchDebounceMs < 0 {
+		return errors.New("watch_debounce_ms must not be negative")
+	}
+	if s.SSEKeepAliveMs <= 0 {
+		return errors.New("sse_keepalive_ms must be positive")
+	}
+	if s.MaxUploadBytes <= 0 {
+		return errors.New("max_upload_bytes must be positive")
+	}
+	if s.MediaMaxAge < 0 {
+		return errors.New("media_max_age must not be negative")
+	}
+	if s.CompressMinBytes < 0 {
+		return errors.New("compress_min_bytes must not be negative")
+	}
+	if s.ClipboardMax <= 0 {
+		return errors.New("clipboard_max_items must be positive")
+	}
+	if s.RateLimit < 0 || s.RateLimitExpensive < 0 {
+		return errors.New("rate_limit and rate_limit_expensive must not be negative")
+	}
+	if s.ReadHeaderTimeout < 0 || s.ReadTimeout < 0 || s.WriteTimeout < 0 || s.IdleTimeout < 0 {
+		return errors.New("read_header_timeout, read_timeout, write_timeout and idle_timeout must not be negative")
+	}
+	return nil
 }
 
 // Config manages the runtime configuration
 type Config struct {
 	mu       sync.RWMutex
 	settings Settings
+	path     string // config file persisted on change; empty disables saving
+
+	// Directory and port last read from or written to the config file, so Reload can
+	// tell a value edited in the file from the one chosen at startup with -dir or -port
+	fileDir  string
+	filePort int
 }
 
 var globalConfig = &Config{
-	settings: Settings{
-		ProxyRules:     []ProxyRule{},
-		FileServerPort: 8080,
-		FileServerDir:  ".",
-	},
+	settings: defaultSettings(),
+}
+
+// defaultSettings returns the settings used when nothing else is configured
+func defaultSettings() Settings {
+	return Settings{
+		ProxyRules:      []ProxyRule{},
+		FileServerPort:  8080,
+		FileServerDir:   ".",
+		AutoIndex:       true,
+		PreviewMaxBytes: 2 << 20,   // 2 MB
+		MaxUploadBytes:  500 << 20, // 500 MB
+		PreviewLinks:    true,
+		WatchIgnore:     []string{".git", "node_modules", "*.tmp"},
+		WatchDebounceMs: 500,
+		SSEKeepAliveMs:  15000,
+		ClipboardMax:    100,
+		MediaMaxAge:     3600,
+
+		CompressMinBytes: 1024,
+
+		RateLimit:          600,
+		RateLimitExpensive: 30,
+
+		ReadHeaderTimeout: 10,
+		ReadTimeout:       60,
+		WriteTimeout:      60,
+		IdleTimeout:       120,
+
+		UploadAllowExtensions: []string{},
+		UploadDenyExtensions:  []string{},
+	}
 }
 
 // GetConfig returns the global configuration instance
@@ -45,15 +209,16 @@ func (c *Config) GetSettings() Settings {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	
+	settings := c.settings
+	
 	// Deep copy proxy rules
-	rules := make([]ProxyRule, len(c.settings.ProxyRules))
-	copy(rules, c.settings.ProxyRules)
+	settings.ProxyRules = make([]ProxyRule, len(c.settings.ProxyRules))
+	copy(settings.ProxyRules, c.settings.ProxyRules)
+	settings.WatchIgnore = append([]string(nil), c.settings.WatchIgnore...)
+	settings.UploadAllowExtensions = append([]string(nil), c.settings.UploadAllowExtensions...)
+	settings.UploadDenyExtensions = append([]string(nil), c.settings.UploadDenyExtensions...)
 	
-	return Settings{
-		ProxyRules:     rules,
-		FileServerPort: c.settings.FileServerPort,
-		FileServerDir:  c.settings.FileServerDir,
-	}
+	return settings
 }
 
 // GetProxyRules returns all proxy rules
@@ -66,11 +231,25 @@ func (c *Config) GetProxyRules() []ProxyRule {
 	return rules
 }
 
+// GetProxyRule returns the proxy rule with the given ID
+func (c *Config) GetProxyRule(id string) (ProxyRule, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, r := range c.settings.ProxyRules {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return ProxyRule{}, false
+}
+
 // AddProxyRule adds a new proxy rule
 func (c *Config) AddProxyRule(rule ProxyRule) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.settings.ProxyRules = append(c.settings.ProxyRules, rule)
+	c.persistLocked()
 }
 
 // UpdateProxyRule updates an existing proxy rule
@@ -82,6 +261,7 @@ func (c *Config) UpdateProxyRule(id string, rule ProxyRule) bool {
 		if r.ID == id {
 			rule.ID = id // Ensure ID doesn't change
 			c.settings.ProxyRules[i] = rule
+			c.persistLocked()
 			return true
 		}
 	}
@@ -96,6 +276,7 @@ func (c *Config) DeleteProxyRule(id string) bool {
 	for i, r := range c.settings.ProxyRules {
 		if r.ID == id {
 			c.settings.ProxyRules = append(c.settings.ProxyRules[:i], c.settings.ProxyRules[i+1:]...)
+			c.persistLocked()
 			return true
 		}
 	}
@@ -111,17 +292,156 @@ func (c *Config) ExportSettings() ([]byte, error) {
 
 // ImportSettings imports settings from JSON
 func (c *Config) ImportSettings(data []byte) error {
-	var newSettings Settings
-	if err := json.Unmarshal(data, &newSettings); err != nil {
+	newSettings, err := parseSettings(data)
+	if err != nil {
 		return err
 	}
 	
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.settings = newSettings
+	c.persistLocked()
+	return nil
+}
+
+// DefaultConfigPath returns the default config file location in the user's home directory
+func DefaultConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".simple-http-server", "config.json"), nil
+}
+
+// Load reads settings from a JSON file and remembers the path so later changes are saved.
+// If the file does not exist it is created with the current settings.
+func (c *Config) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		c.mu.Lock()
+		c.path = path
+		c.mu.Unlock()
+		return c.Save(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	newSettings, err := parseSettings(data)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.settings = newSettings
+	c.path = path
+	c.fileDir, c.filePort = newSettings.FileServerDir, newSettings.FileServerPort
+	return nil
+}
+
+// Reload reads the loaded config file again and replaces the current settings.
+// The served directory and port are only changed when they were edited in the file,
+// and a new directory must exist. The current settings are kept if the file cannot be
+// read or is invalid.
+func (c *Config) Reload() error {
+	c.mu.RLock()
+	path := c.path
+	c.mu.RUnlock()
+	if path == "" {
+		return errors.New("no config file loaded")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	newSettings, err := parseSettings(data)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fileDir, filePort := newSettings.FileServerDir, newSettings.FileServerPort
+	if fileDir == c.fileDir {
+		newSettings.FileServerDir = c.settings.FileServerDir
+	} else {
+		dir, err := filepath.Abs(fileDir)
+		if err != nil {
+			return err
+		}
+		info, err := os.Stat(dir)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return errors.New("file_server_dir is not a directory: " + dir)
+		}
+		newSettings.FileServerDir = dir
+	}
+	if filePort == c.filePort {
+		newSettings.FileServerPort = c.settings.FileServerPort
+	}
+	c.settings = newSettings
+	c.fileDir, c.filePort = fileDir, filePort
+	return nil
+}
+
+// parseSettings decodes and validates settings JSON; fields missing from it keep their default values
+func parseSettings(data []byte) (Settings, error) {
+	settings := defaultSettings()
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return Settings{}, err
+	}
+	if err := settings.validate(); err != nil {
+		return Settings{}, err
+	}
+	return settings, nil
+}
+
+// Save writes the current settings to a JSON file
+func (c *Config) Save(path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.writeLocked(path)
+}
+
+// writeLocked writes settings to path; the caller must hold c.mu
+func (c *Config) writeLocked(path string) error {
+	data, err := json.MarshalIndent(c.settings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated config
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	if path == c.path {
+		c.fileDir, c.filePort = c.settings.FileServerDir, c.settings.FileServerPort
+	}
 	return nil
 }
 
+// persistLocked saves settings to the loaded config file, if any; the caller must hold c.mu
+func (c *Config) persistLocked() {
+	if c.path == "" {
+		return
+	}
+	if err := c.writeLocked(c.path); err != nil {
+		log.Printf("Failed to save config to %s: %v", c.path, err)
+	}
+}
+
 // SetFileServerDir sets the file server directory
 func (c *Config) SetFileServerDir(dir string) {
 	c.mu.Lock()
@@ -149,3 +469,151 @@ func (c *Config) GetFileServerPort() int {
 	defer c.mu.RUnlock()
 	return c.settings.FileServerPort
 }
+
+// SetAutoIndex sets whether index.html is served in place of a directory listing
+func (c *Config) SetAutoIndex(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.settings.AutoIndex = enabled
+}
+
+// GetAutoIndex gets whether index.html is served in place of a directory listing
+func (c *Config) GetAutoIndex() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings.AutoIndex
+}
+
+// GetDisableListing gets whether directory listings are turned off
+func (c *Config) GetDisableListing() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings.DisableListing
+}
+
+// GetShowHidden gets whether directory listings show entries starting with "." by default
+func (c *Config) GetShowHidden() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings.ShowHidden
+}
+
+// GetPreviewMaxBytes gets the maximum number of bytes read for text and code previews
+func (c *Config) GetPreviewMaxBytes() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings.PreviewMaxBytes
+}
+
+// GetPreviewLinks gets whether code and text file names link to their preview
+func (c *Config) GetPreviewLinks() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings.PreviewLinks
+}
+
+// GetMaxUploadBytes gets the maximum size of an upload request
+func (c *Config) GetMaxUploadBytes() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings.MaxUploadBytes
+}
+
+// GetWatchIgnore gets the glob patterns for paths the file watcher ignores
+func (c *Config) GetWatchIgnore() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.settings.WatchIgnore...)
+}
+
+// SetWatchPoll sets whether the file watcher polls instead of using fsnotify
+func (c *Config) SetWatchPoll(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.settings.WatchPoll = enabled
+}
+
+// GetWatchPoll gets whether the file watcher polls instead of using fsnotify
+func (c *Config) GetWatchPoll() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings.WatchPoll
+}
+
+// GetWatchDebounce gets how long the file watcher waits before broadcasting a burst of changes
+func (c *Config) GetWatchDebounce() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return time.Duration(c.settings.WatchDebounceMs) * time.Millisecond
+}
+
+// GetSSEKeepAlive gets the interval between SSE keep-alive comments
+func (c *Config) GetSSEKeepAlive() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return time.Duration(c.settings.SSEKeepAliveMs) * time.Millisecond
+}
+
+// GetMediaMaxAge gets how many seconds browsers may cache media files
+func (c *Config) GetMediaMaxAge() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings.MediaMaxAge
+}
+
+// GetCompressMinBytes gets the size below which responses are not compressed
+func (c *Config) GetCompressMinBytes() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings.CompressMinBytes
+}
+
+// GetClipboardMax gets the maximum number of clipboard items kept at once
+func (c *Config) GetClipboardMax() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings.ClipboardMax
+}
+
+// GetRateLimits gets the requests per minute allowed from each client, for most requests
+// and for uploads and archives; 0 means unlimited
+func (c *Config) GetRateLimits() (general, expensive int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings.RateLimit, c.settings.RateLimitExpensive
+}
+
+// GetServerTimeouts gets the HTTP server's timeouts
+func (c *Config) GetServerTimeouts() ServerTimeouts {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return ServerTimeouts{
+		ReadHeader: c.settings.ReadHeaderTimeout,
+		Read:       c.settings.ReadTimeout,
+		Write:      c.settings.WriteTimeout,
+		Idle:       c.settings.IdleTimeout,
+	}
+}
+
+// GetUploadExtensions gets the allowed and denied upload extensions, lowercased with a leading dot
+func (c *Config) GetUploadExtensions() (allow, deny []string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return normalizeExtensions(c.settings.UploadAllowExtensions), normalizeExtensions(c.settings.UploadDenyExtensions)
+}
+
+// normalizeExtensions lowercases extensions and adds a missing leading dot, so "EXE" matches ".exe"
+func normalizeExtensions(exts []string) []string {
+	normalized := make([]string, 0, len(exts))
+	for _, ext := range exts {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		normalized = append(normalized, ext)
+	}
+	return normalized
+}
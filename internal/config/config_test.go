@@ -0,0 +1,253 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// editConfigFile rewrites one field of the config file at path, as a user would by hand
+func editConfigFile(t *testing.T, path, key string, value interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	fields[key] = value
+	data, err = json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadKeepsRuntimeDirAndPort(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	served := t.TempDir()
+
+	c := &Config{settings: defaultSettings()}
+	if err := c.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	c.SetFileServerDir(served)
+	c.SetFileServerPort(9123)
+
+	editConfigFile(t, path, "auto_index", false)
+	if err := c.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got := c.GetFileServerDir(); got != served {
+		t.Errorf("dir = %q after reload, want %q", got, served)
+	}
+	if got := c.GetFileServerPort(); got != 9123 {
+		t.Errorf("port = %d after reload, want 9123", got)
+	}
+	if c.GetSettings().AutoIndex {
+		t.Error("auto_index from the file was not applied")
+	}
+}
+
+func TestReloadAppliesEditedDirAndPort(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	c := &Config{settings: defaultSettings()}
+	if err := c.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	c.SetFileServerDir(t.TempDir())
+
+	other := t.TempDir()
+	editConfigFile(t, path, "file_server_dir", other)
+	editConfigFile(t, path, "file_server_port", 9200)
+	if err := c.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got := c.GetFileServerDir(); got != other {
+		t.Errorf("dir = %q, want %q", got, other)
+	}
+	if got := c.GetFileServerPort(); got != 9200 {
+		t.Errorf("port = %d, want 9200", got)
+	}
+
+	// Reloading the same file again must not undo a later runtime change
+	c.SetFileServerDir(t.TempDir())
+	runtime := c.GetFileServerDir()
+	if err := c.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got := c.GetFileServerDir(); got != runtime {
+		t.Errorf("dir = %q on second reload, want %q", got, runtime)
+	}
+}
+
+func TestReloadRejectsMissingDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	served := t.TempDir()
+	c := &Config{settings: defaultSettings()}
+	if err := c.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	c.SetFileServerDir(served)
+
+	editConfigFile(t, path, "file_server_dir", filepath.Join(served, "missing"))
+	editConfigFile(t, path, "auto_index", false)
+	if err := c.Reload(); err == nil {
+		t.Fatal("Reload succeeded with a missing directory")
+	}
+	if got := c.GetFileServerDir(); got != served {
+		t.Errorf("dir = %q, want %q kept", got, served)
+	}
+	if !c.GetSettings().AutoIndex {
+		t.Error("settings changed although the reload failed")
+	}
+}
+
+func TestReloadRejectsMalformedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	c := &Config{settings: defaultSettings()}
+	if err := c.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	before := c.GetSettings()
+
+	for _, content := range []string{`{"auto_index": false,`, `{"max_upload_bytes": -1}`, `[]`} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Reload(); err == nil {
+			t.Errorf("Reload accepted %s", content)
+		}
+		if got := c.GetSettings(); !reflect.DeepEqual(got, before) {
+			t.Errorf("settings changed after reloading %s: %+v", content, got)
+		}
+	}
+	// The file is left as the user wrote it rather than overwritten
+	if data, _ := os.ReadFile(path); string(data) != `[]` {
+		t.Errorf("config file = %s, want it untouched", data)
+	}
+}
+
+// readConfigFile returns the settings stored in the config file at path
+func readConfigFile(t *testing.T, path string) Settings {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s Settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.json")
+	c := &Config{settings: defaultSettings()}
+	c.SetFileServerDir(t.TempDir())
+	c.SetFileServerPort(9300)
+	c.SetAutoIndex(false)
+	c.AddProxyRule(ProxyRule{ID: "api", PathPrefix: "/api/", TargetURL: "http://localhost:3000", Enabled: true})
+	if err := c.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded := &Config{settings: defaultSettings()}
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.GetSettings(), c.GetSettings()) {
+		t.Errorf("loaded settings = %+v, want %+v", loaded.GetSettings(), c.GetSettings())
+	}
+}
+
+func TestLoadCreatesMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".simple-http-server", "config.json")
+	c := &Config{settings: defaultSettings()}
+	if err := c.Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := readConfigFile(t, path); !reflect.DeepEqual(got, defaultSettings()) {
+		t.Errorf("created file holds %+v, want the defaults", got)
+	}
+}
+
+func TestMutationsAreSaved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	c := &Config{settings: defaultSettings()}
+	if err := c.Load(path); err != nil {
+		t.Fatal(err)
+	}
+
+	rule := ProxyRule{ID: "api", PathPrefix: "/api/", TargetURL: "http://localhost:3000", Enabled: true}
+	c.AddProxyRule(rule)
+	if rules := readConfigFile(t, path).ProxyRules; len(rules) != 1 || rules[0].PathPrefix != "/api/" {
+		t.Fatalf("after add, file holds rules %+v", rules)
+	}
+
+	rule.PathPrefix = "/v2/"
+	c.UpdateProxyRule("api", rule)
+	if rules := readConfigFile(t, path).ProxyRules; len(rules) != 1 || rules[0].PathPrefix != "/v2/" {
+		t.Fatalf("after update, file holds rules %+v", rules)
+	}
+
+	c.DeleteProxyRule("api")
+	if rules := readConfigFile(t, path).ProxyRules; len(rules) != 0 {
+		t.Fatalf("after delete, file holds rules %+v", rules)
+	}
+
+	if err := c.ImportSettings([]byte(`{"auto_index": false}`)); err != nil {
+		t.Fatal(err)
+	}
+	if readConfigFile(t, path).AutoIndex {
+		t.Error("imported settings were not saved")
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temporary file left behind")
+	}
+}
+
+func TestImportedRulesDefaultToEnabled(t *testing.T) {
+	c := &Config{}
+	err := c.ImportSettings([]byte(`{"proxy_rules": [
+		{"id": "old", "path_prefix": "/old/", "target_url": "http://localhost:3000"},
+		{"id": "off", "path_prefix": "/off/", "target_url": "http://localhost:3001", "enabled": false}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule, ok := c.GetProxyRule("old"); !ok || !rule.Enabled {
+		t.Errorf("rule without an enabled field: %+v, want enabled", rule)
+	}
+	if rule, ok := c.GetProxyRule("off"); !ok || rule.Enabled {
+		t.Errorf("rule with enabled false: %+v, want disabled", rule)
+	}
+}
+
+func TestWatchDurations(t *testing.T) {
+	c := &Config{settings: defaultSettings()}
+	if c.GetWatchDebounce() != 500*time.Millisecond || c.GetSSEKeepAlive() != 15*time.Second {
+		t.Errorf("defaults = %s and %s, want 500ms and 15s", c.GetWatchDebounce(), c.GetSSEKeepAlive())
+	}
+
+	if err := c.ImportSettings([]byte(`{"watch_debounce_ms": 0, "sse_keepalive_ms": 2000}`)); err != nil {
+		t.Fatal(err)
+	}
+	if c.GetWatchDebounce() != 0 || c.GetSSEKeepAlive() != 2*time.Second {
+		t.Errorf("imported = %s and %s, want 0s and 2s", c.GetWatchDebounce(), c.GetSSEKeepAlive())
+	}
+
+	for _, settings := range []string{`{"watch_debounce_ms": -1}`, `{"sse_keepalive_ms": 0}`} {
+		if err := c.ImportSettings([]byte(settings)); err == nil {
+			t.Errorf("%s was accepted", settings)
+		}
+	}
+}
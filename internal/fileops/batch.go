@@ -0,0 +1,85 @@
+package fileops
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+
+	"simple.http.server/internal/pathutil"
+)
+
+// maxBatchPaths is the largest number of paths accepted by one batch request
+const maxBatchPaths = 1000
+
+// BatchResult reports the outcome for one path of a batch delete
+type BatchResult struct {
+	Path    string `json:"path"`
+	Deleted bool   `json:"deleted"`
+	Error   string `json:"error,omitempty"`
+}
+
+// deleteBatch deletes every path in a JSON array. Each path is checked and deleted on
+// its own, so a bad path is reported in its result without stopping the others.
+func (h *Handler) deleteBatch(w http.ResponseWriter, r *http.Request) {
+	var paths []string
+	if err := json.NewDecoder(r.Body).Decode(&paths); err != nil {
+		http.Error(w, "Request body must be a JSON array of paths", http.StatusBadRequest)
+		return
+	}
+	if len(paths) == 0 || len(paths) > maxBatchPaths {
+		http.Error(w, "Between 1 and 1000 paths are required", http.StatusBadRequest)
+		return
+	}
+
+	results := make([]BatchResult, 0, len(paths))
+	var deleted []string
+	for _, reqPath := range paths {
+		result := BatchResult{Path: reqPath}
+
+		absBase, absPath, err := pathutil.Resolve(h.config.GetFileServerDir(), reqPath)
+		switch {
+		case reqPath == "":
+			result.Error = "Path is required"
+		case errors.Is(err, pathutil.ErrOutsideRoot):
+			result.Error = "Forbidden"
+		case err != nil:
+			result.Error = "Internal server error"
+		default:
+			if status, message := remove(absBase, absPath); status != http.StatusOK {
+				result.Error = message
+			} else {
+				result.Path = relativePath(absBase, absPath)
+				result.Deleted = true
+				deleted = append(deleted, result.Path)
+			}
+		}
+		results = append(results, result)
+	}
+
+	if len(deleted) > 0 {
+		log.Printf("Deleted %d of %d paths", len(deleted), len(paths))
+		// One event for the folder holding everything deleted, instead of one per path
+		h.notify("removed", commonDir(deleted))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"results": results,
+		"deleted": len(deleted),
+		"failed":  len(paths) - len(deleted),
+	})
+}
+
+// commonDir returns the deepest folder containing every one of the slash-separated paths
+func commonDir(paths []string) string {
+	dir := path.Dir(paths[0])
+	for _, p := range paths[1:] {
+		for dir != "/" && !strings.HasPrefix(p, dir+"/") {
+			dir = path.Dir(dir)
+		}
+	}
+	return dir
+}
@@ -0,0 +1,72 @@
+package fileops
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestDeleteBatchReportsEachPath(t *testing.T) {
+	h, root := newTestHandler(t)
+	notifier := &recordingNotifier{}
+	h.SetChangeNotifier(notifier)
+	writeFile(t, filepath.Join(root, "docs", "a.txt"), "a")
+	writeFile(t, filepath.Join(root, "docs", "b.txt"), "b")
+
+	rec := serve(h, http.MethodPost, "/api/delete/batch",
+		`["/docs/a.txt", "../outside.txt", "/docs/missing.txt", "/docs/b.txt"]`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Results []BatchResult `json:"results"`
+		Deleted int           `json:"deleted"`
+		Failed  int           `json:"failed"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []BatchResult{
+		{Path: "/docs/a.txt", Deleted: true},
+		{Path: "../outside.txt", Error: "Forbidden"},
+		{Path: "/docs/missing.txt", Error: "Path not found"},
+		{Path: "/docs/b.txt", Deleted: true},
+	}
+	if len(resp.Results) != len(want) {
+		t.Fatalf("results = %+v, want %+v", resp.Results, want)
+	}
+	for i := range want {
+		if resp.Results[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, resp.Results[i], want[i])
+		}
+	}
+	if resp.Deleted != 2 || resp.Failed != 2 {
+		t.Errorf("deleted, failed = %d, %d, want 2, 2", resp.Deleted, resp.Failed)
+	}
+
+	if exists(filepath.Join(root, "docs", "a.txt")) || exists(filepath.Join(root, "docs", "b.txt")) {
+		t.Error("valid paths were not deleted")
+	}
+	if !exists(filepath.Join(filepath.Dir(root), "outside.txt")) {
+		t.Error("file outside the root was deleted")
+	}
+	if len(notifier.changes) != 1 || notifier.changes[0] != "removed /docs" {
+		t.Errorf("changes = %v, want one removed /docs", notifier.changes)
+	}
+}
+
+func TestDeleteBatchRejectsInvalidBodies(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	for name, body := range map[string]string{
+		"not an array": `{"path": "/a.txt"}`,
+		"empty array":  `[]`,
+		"not json":     `/a.txt`,
+	} {
+		if rec := serve(h, http.MethodPost, "/api/delete/batch", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", name, rec.Code)
+		}
+	}
+}
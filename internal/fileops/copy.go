@@ -0,0 +1,221 @@
+package fileops
+
+import (
+	"encoding/json"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"simple.http.server/internal/pathutil"
+)
+
+// ChangeNotifier is told about changes made through the file operations API
+type ChangeNotifier interface {
+	NotifyChange(eventType, urlPath string)
+}
+
+// SetChangeNotifier sets who is told about copied and moved files
+func (h *Handler) SetChangeNotifier(notifier ChangeNotifier) {
+	h.notifier = notifier
+}
+
+// notify reports a change when a notifier is set
+func (h *Handler) notify(eventType, urlPath string) {
+	if h.notifier != nil {
+		h.notifier.NotifyChange(eventType, urlPath)
+	}
+}
+
+// transferRequest is the body of copy and move requests. When To is an existing
+// folder the source is placed inside it; otherwise To is the new path itself.
+type transferRequest struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Overwrite bool   `json:"overwrite"`
+}
+
+// transferPaths validates a copy or move request and returns the absolute base,
+// source and destination, writing an error response when the request is invalid
+func (h *Handler) transferPaths(w http.ResponseWriter, r *http.Request) (absBase, absFrom, absTo string, ok bool) {
+	var req transferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return "", "", "", false
+	}
+	if req.From == "" || req.To == "" {
+		http.Error(w, "Both from and to are required", http.StatusBadRequest)
+		return "", "", "", false
+	}
+
+	absBase, absFrom, ok = h.resolve(w, req.From)
+	if !ok {
+		return "", "", "", false
+	}
+	if absFrom == absBase {
+		http.Error(w, "Cannot copy or move the root directory", http.StatusForbidden)
+		return "", "", "", false
+	}
+	if _, err := os.Lstat(absFrom); err != nil {
+		http.Error(w, "Source not found", http.StatusNotFound)
+		return "", "", "", false
+	}
+
+	_, absTo, ok = h.resolve(w, req.To)
+	if !ok {
+		return "", "", "", false
+	}
+	if info, err := os.Stat(absTo); err == nil && info.IsDir() {
+		absTo = filepath.Join(absTo, filepath.Base(absFrom))
+	}
+
+	if absTo == absFrom {
+		http.Error(w, "Source and destination are the same", http.StatusBadRequest)
+		return "", "", "", false
+	}
+	// Copying a folder into itself would never finish
+	if pathutil.IsWithin(absFrom, absTo) {
+		http.Error(w, "Destination is inside the source", http.StatusBadRequest)
+		return "", "", "", false
+	}
+	if _, err := os.Stat(filepath.Dir(absTo)); err != nil {
+		http.Error(w, "Destination folder not found", http.StatusNotFound)
+		return "", "", "", false
+	}
+	if _, err := os.Lstat(absTo); err == nil && !req.Overwrite {
+		http.Error(w, "Destination already exists", http.StatusConflict)
+		return "", "", "", false
+	}
+	return absBase, absFrom, absTo, true
+}
+
+// copyPath copies a file, or a folder and its contents, to another path
+func (h *Handler) copyPath(w http.ResponseWriter, r *http.Request) {
+	absBase, absFrom, absTo, ok := h.transferPaths(w, r)
+	if !ok {
+		return
+	}
+
+	// Copy next to the destination first so a failed copy leaves any existing file intact
+	tmp, err := os.MkdirTemp(filepath.Dir(absTo), ".copy-*")
+	if err != nil {
+		log.Printf("Copy error for %s: %v", absFrom, err)
+		http.Error(w, "Failed to copy", http.StatusInternalServerError)
+		return
+	}
+	defer os.RemoveAll(tmp)
+
+	staged := filepath.Join(tmp, filepath.Base(absTo))
+	if err := copyTree(absFrom, staged); err != nil {
+		log.Printf("Copy error for %s: %v", absFrom, err)
+		http.Error(w, "Failed to copy", http.StatusInternalServerError)
+		return
+	}
+	if err := replace(staged, absTo); err != nil {
+		log.Printf("Copy error for %s: %v", absFrom, err)
+		http.Error(w, "Failed to copy", http.StatusInternalServerError)
+		return
+	}
+
+	from, to := relativePath(absBase, absFrom), relativePath(absBase, absTo)
+	log.Printf("Copied: %s -> %s", from, to)
+	h.notify("created", to)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"from": from, "copied": to})
+}
+
+// movePath moves a file or folder to another path
+func (h *Handler) movePath(w http.ResponseWriter, r *http.Request) {
+	absBase, absFrom, absTo, ok := h.transferPaths(w, r)
+	if !ok {
+		return
+	}
+
+	if err := replace(absFrom, absTo); err != nil {
+		log.Printf("Move error for %s: %v", absFrom, err)
+		http.Error(w, "Failed to move", http.StatusInternalServerError)
+		return
+	}
+
+	from, to := relativePath(absBase, absFrom), relativePath(absBase, absTo)
+	log.Printf("Moved: %s -> %s", from, to)
+	h.notify("removed", from)
+	h.notify("created", to)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"from": from, "moved": to})
+}
+
+// replace renames src to dst, replacing whatever dst holds. Renaming cannot replace
+// a folder, so an existing dst is moved aside first and restored if the rename fails.
+func replace(src, dst string) error {
+	if _, err := os.Lstat(dst); err != nil {
+		return os.Rename(src, dst)
+	}
+
+	aside, err := os.MkdirTemp(filepath.Dir(dst), ".replace-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(aside)
+
+	old := filepath.Join(aside, "old")
+	if err := os.Rename(dst, old); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		os.Rename(old, dst)
+		return err
+	}
+	return nil
+}
+
+// copyTree copies the file or folder at src to dst, which must not exist.
+// Symbolic links and other special files are skipped, as they may point outside the served directory.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.Mkdir(target, info.Mode().Perm()|0700)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		}
+		return nil
+	})
+}
+
+// copyFile streams the contents of src into a new file at dst
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
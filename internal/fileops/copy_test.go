@@ -0,0 +1,140 @@
+package fileops
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// transferBody returns the JSON body of a copy or move request
+func transferBody(t *testing.T, from, to string, overwrite bool) string {
+	t.Helper()
+	data, err := json.Marshal(transferRequest{From: from, To: to, Overwrite: overwrite})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// recordingNotifier keeps the changes it is told about
+type recordingNotifier struct {
+	changes []string
+}
+
+func (n *recordingNotifier) NotifyChange(eventType, urlPath string) {
+	n.changes = append(n.changes, eventType+" "+urlPath)
+}
+
+// readFile returns the content of the file at path, failing the test if it cannot be read
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestCopyFileIntoFolder(t *testing.T) {
+	h, root := newTestHandler(t)
+	notifier := &recordingNotifier{}
+	h.SetChangeNotifier(notifier)
+	writeFile(t, filepath.Join(root, "a.txt"), "alpha")
+	os.Mkdir(filepath.Join(root, "sub"), 0755)
+
+	rec := serve(h, http.MethodPost, "/api/copy", transferBody(t, "/a.txt", "/sub", false))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if got := readFile(t, filepath.Join(root, "sub", "a.txt")); got != "alpha" {
+		t.Errorf("copy holds %q, want alpha", got)
+	}
+	if !exists(filepath.Join(root, "a.txt")) {
+		t.Error("source was removed by a copy")
+	}
+	if len(notifier.changes) != 1 || notifier.changes[0] != "created /sub/a.txt" {
+		t.Errorf("changes = %v, want created /sub/a.txt", notifier.changes)
+	}
+
+	// Copying again needs overwrite
+	if rec := serve(h, http.MethodPost, "/api/copy", transferBody(t, "/a.txt", "/sub", false)); rec.Code != http.StatusConflict {
+		t.Errorf("second copy: status = %d, want 409", rec.Code)
+	}
+	writeFile(t, filepath.Join(root, "a.txt"), "beta")
+	if rec := serve(h, http.MethodPost, "/api/copy", transferBody(t, "/a.txt", "/sub/a.txt", true)); rec.Code != http.StatusCreated {
+		t.Fatalf("overwriting copy: status = %d: %s", rec.Code, rec.Body)
+	}
+	if got := readFile(t, filepath.Join(root, "sub", "a.txt")); got != "beta" {
+		t.Errorf("overwritten copy holds %q, want beta", got)
+	}
+}
+
+func TestCopyDirectoryTree(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, filepath.Join(root, "src", "a.txt"), "a")
+	writeFile(t, filepath.Join(root, "src", "deep", "er", "b.txt"), "b")
+	os.Mkdir(filepath.Join(root, "src", "empty"), 0755)
+
+	rec := serve(h, http.MethodPost, "/api/copy", transferBody(t, "/src", "/dst", false))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	for path, want := range map[string]string{"a.txt": "a", "deep/er/b.txt": "b"} {
+		if got := readFile(t, filepath.Join(root, "dst", filepath.FromSlash(path))); got != want {
+			t.Errorf("dst/%s holds %q, want %q", path, got, want)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(root, "dst", "empty")); err != nil || !info.IsDir() {
+		t.Errorf("empty folder not copied: %v", err)
+	}
+
+	// A folder cannot be copied into itself
+	if rec := serve(h, http.MethodPost, "/api/copy", transferBody(t, "/src", "/src/deep", false)); rec.Code != http.StatusBadRequest {
+		t.Errorf("copy into itself: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestMoveFile(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, filepath.Join(root, "a.txt"), "alpha")
+	os.Mkdir(filepath.Join(root, "sub"), 0755)
+
+	rec := serve(h, http.MethodPost, "/api/move", transferBody(t, "/a.txt", "/sub/renamed.txt", false))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if exists(filepath.Join(root, "a.txt")) {
+		t.Error("source still exists after a move")
+	}
+	if got := readFile(t, filepath.Join(root, "sub", "renamed.txt")); got != "alpha" {
+		t.Errorf("moved file holds %q, want alpha", got)
+	}
+}
+
+func TestTransferRejectsEscapes(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, filepath.Join(root, "a.txt"), "alpha")
+	outside := filepath.Join(filepath.Dir(root), "outside.txt")
+
+	for _, endpoint := range []string{"/api/copy", "/api/move"} {
+		for _, p := range traversalPaths {
+			if rec := serve(h, http.MethodPost, endpoint, transferBody(t, "/a.txt", p, true)); rec.Code != http.StatusForbidden {
+				t.Errorf("%s to %q: status = %d, want 403", endpoint, p, rec.Code)
+			}
+			if rec := serve(h, http.MethodPost, endpoint, transferBody(t, p, "/copied.txt", false)); rec.Code != http.StatusForbidden {
+				t.Errorf("%s from %q: status = %d, want 403", endpoint, p, rec.Code)
+			}
+		}
+		if rec := serve(h, http.MethodPost, endpoint, transferBody(t, "/", "/sub", false)); rec.Code != http.StatusForbidden {
+			t.Errorf("%s of the root: status = %d, want 403", endpoint, rec.Code)
+		}
+	}
+	if got := readFile(t, outside); got != "outside" {
+		t.Errorf("file outside the root was changed to %q", got)
+	}
+	if !exists(filepath.Join(root, "a.txt")) || exists(filepath.Join(root, "copied.txt")) {
+		t.Error("a rejected request changed the served directory")
+	}
+}
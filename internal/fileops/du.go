@@ -0,0 +1,88 @@
+package fileops
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"simple.http.server/internal/format"
+)
+
+// maxUsageWalk bounds how long a disk usage request may walk; the totals found so far are returned
+const maxUsageWalk = 5 * time.Second
+
+// DiskUsage is the total size of a folder and everything below it
+type DiskUsage struct {
+	Path       string `json:"path"`
+	TotalBytes int64  `json:"total_bytes"`
+	FileCount  int    `json:"file_count"`
+	DirCount   int    `json:"dir_count"` // folders below the path, not counting the path itself
+	Human      string `json:"human"`     // TotalBytes formatted for display, e.g. "1.5 MB"
+	Partial    bool   `json:"partial"`   // the walk ran out of time, so the totals are too low
+}
+
+// diskUsage adds up the sizes of the files below a folder. Entries that cannot be
+// read are skipped, and symbolic links are not followed.
+func (h *Handler) diskUsage(w http.ResponseWriter, r *http.Request) {
+	reqPath := r.URL.Query().Get("path")
+	if reqPath == "" {
+		reqPath = "/"
+	}
+
+	absBase, absPath, ok := h.resolve(w, reqPath)
+	if !ok {
+		return
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			http.Error(w, "Path not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	usage := DiskUsage{Path: relativePath(absBase, absPath)}
+	if !info.IsDir() {
+		usage.TotalBytes = info.Size()
+		usage.FileCount = 1
+	} else {
+		deadline := time.Now().Add(maxUsageWalk)
+		ctx := r.Context()
+
+		filepath.WalkDir(absPath, func(path string, d fs.DirEntry, err error) error {
+			if ctx.Err() != nil {
+				return filepath.SkipAll
+			}
+			if time.Now().After(deadline) {
+				usage.Partial = true
+				return filepath.SkipAll
+			}
+			if err != nil || path == absPath {
+				return nil // Skip unreadable entries, continue walking
+			}
+
+			if d.IsDir() {
+				usage.DirCount++
+				return nil
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			if fileInfo, err := d.Info(); err == nil {
+				usage.TotalBytes += fileInfo.Size()
+				usage.FileCount++
+			}
+			return nil
+		})
+	}
+	usage.Human = format.FileSize(usage.TotalBytes)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(usage)
+}
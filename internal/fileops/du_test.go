@@ -0,0 +1,67 @@
+package fileops
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// diskUsage requests the usage of path from h, failing the test unless it succeeds
+func diskUsage(t *testing.T, h *Handler, path string) DiskUsage {
+	t.Helper()
+	rec := serve(h, http.MethodGet, query("/api/du", path), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: status = %d: %s", path, rec.Code, rec.Body)
+	}
+	var usage DiskUsage
+	if err := json.NewDecoder(rec.Body).Decode(&usage); err != nil {
+		t.Fatal(err)
+	}
+	return usage
+}
+
+func TestDiskUsage(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, filepath.Join(root, "docs", "a.txt"), strings.Repeat("a", 100))
+	writeFile(t, filepath.Join(root, "docs", "sub", "b.txt"), strings.Repeat("b", 2000))
+	writeFile(t, filepath.Join(root, "docs", "sub", "deep", "c.bin"), strings.Repeat("c", 1500))
+	if err := os.Mkdir(filepath.Join(root, "docs", "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Links are not followed, so the file outside isn't counted
+	if err := os.Symlink(filepath.Join(filepath.Dir(root), "outside.txt"), filepath.Join(root, "docs", "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	got := diskUsage(t, h, "/docs")
+	want := DiskUsage{Path: "/docs", TotalBytes: 3600, FileCount: 3, DirCount: 3, Human: "3.5 KB"}
+	if got != want {
+		t.Errorf("usage = %+v, want %+v", got, want)
+	}
+
+	got = diskUsage(t, h, "/docs/sub/b.txt")
+	want = DiskUsage{Path: "/docs/sub/b.txt", TotalBytes: 2000, FileCount: 1, Human: "2.0 KB"}
+	if got != want {
+		t.Errorf("file usage = %+v, want %+v", got, want)
+	}
+
+	// The whole served directory is the default
+	if got := diskUsage(t, h, ""); got.TotalBytes != 3600 || got.FileCount != 3 || got.DirCount != 4 {
+		t.Errorf("root usage = %+v, want 3600 bytes in 3 files and 4 folders", got)
+	}
+}
+
+func TestDiskUsageRejections(t *testing.T) {
+	h, _ := newTestHandler(t)
+	for _, p := range traversalPaths {
+		if rec := serve(h, http.MethodGet, query("/api/du", p), ""); rec.Code != http.StatusForbidden {
+			t.Errorf("%q: status = %d, want 403", p, rec.Code)
+		}
+	}
+	if rec := serve(h, http.MethodGet, query("/api/du", "/missing"), ""); rec.Code != http.StatusNotFound {
+		t.Errorf("missing path: status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,174 @@
+package fileops
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"simple.http.server/internal/config"
+	"simple.http.server/internal/pathutil"
+)
+
+// Handler manages file and folder operations
+type Handler struct {
+	config   *config.Config
+	notifier ChangeNotifier
+}
+
+// NewHandler creates a new file operations handler
+func NewHandler(cfg *config.Config) *Handler {
+	return &Handler{config: cfg}
+}
+
+// ServeHTTP routes file operation requests
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/api/delete" && (r.Method == http.MethodPost || r.Method == http.MethodDelete):
+		h.deletePath(w, r)
+	case r.URL.Path == "/api/delete/batch" && r.Method == http.MethodPost:
+		h.deleteBatch(w, r)
+	case r.URL.Path == "/api/mkdir" && r.Method == http.MethodPost:
+		h.makeDir(w, r)
+	case r.URL.Path == "/api/copy" && r.Method == http.MethodPost:
+		h.copyPath(w, r)
+	case r.URL.Path == "/api/move" && r.Method == http.MethodPost:
+		h.movePath(w, r)
+	case r.URL.Path == "/api/stat" && r.Method == http.MethodGet:
+		h.statPath(w, r)
+	case r.URL.Path == "/api/du" && r.Method == http.MethodGet:
+		h.diskUsage(w, r)
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// deletePath removes a file or a folder and its contents
+func (h *Handler) deletePath(w http.ResponseWriter, r *http.Request) {
+	reqPath := r.FormValue("path")
+	if reqPath == "" {
+		http.Error(w, "Path parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	absBase, absPath, ok := h.resolve(w, reqPath)
+	if !ok {
+		return
+	}
+
+	if status, message := remove(absBase, absPath); status != http.StatusOK {
+		http.Error(w, message, status)
+		return
+	}
+
+	relPath := relativePath(absBase, absPath)
+	log.Printf("Deleted: %s", relPath)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"deleted": relPath})
+}
+
+// remove deletes a file, or a folder and its contents. It returns the HTTP status
+// and, when the deletion failed, a message for the client.
+func remove(absBase, absPath string) (status int, message string) {
+	// Never allow removing the served directory itself
+	if absPath == absBase {
+		return http.StatusForbidden, "Cannot delete the root directory"
+	}
+
+	info, err := os.Lstat(absPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return http.StatusNotFound, "Path not found"
+		}
+		return http.StatusInternalServerError, "Internal server error"
+	}
+
+	if info.IsDir() {
+		err = os.RemoveAll(absPath)
+	} else {
+		err = os.Remove(absPath)
+	}
+	if err != nil {
+		log.Printf("Delete error for %s: %v", absPath, err)
+		return http.StatusInternalServerError, "Failed to delete"
+	}
+	return http.StatusOK, ""
+}
+
+// makeDir creates a new folder inside an existing path
+func (h *Handler) makeDir(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Path string `json:"path"`
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || name == "." || name == ".." {
+		http.Error(w, "A valid folder name is required", http.StatusBadRequest)
+		return
+	}
+
+	absBase, absPath, ok := h.resolve(w, filepath.Join(req.Path, name))
+	if !ok {
+		return
+	}
+
+	if _, err := os.Stat(absPath); err == nil {
+		http.Error(w, "Path already exists", http.StatusConflict)
+		return
+	}
+
+	if err := os.MkdirAll(absPath, 0755); err != nil {
+		log.Printf("Mkdir error for %s: %v", absPath, err)
+		http.Error(w, "Failed to create folder", http.StatusInternalServerError)
+		return
+	}
+
+	relPath := relativePath(absBase, absPath)
+	log.Printf("Created folder: %s", relPath)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"created": relPath})
+}
+
+// resolve maps a request path into the served directory, writing an error response on failure
+func (h *Handler) resolve(w http.ResponseWriter, reqPath string) (absBase, absPath string, ok bool) {
+	absBase, absPath, err := pathutil.Resolve(h.config.GetFileServerDir(), reqPath)
+	if err != nil {
+		if errors.Is(err, pathutil.ErrOutsideRoot) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+		} else {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return "", "", false
+	}
+	return absBase, absPath, true
+}
+
+// relativePath returns absPath as a slash-separated path rooted at absBase
+func relativePath(absBase, absPath string) string {
+	rel, err := filepath.Rel(absBase, absPath)
+	if err != nil || rel == "." {
+		return "/"
+	}
+	return "/" + filepath.ToSlash(rel)
+}
@@ -0,0 +1,194 @@
+package fileops
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"simple.http.server/internal/config"
+)
+
+// newTestHandler returns a handler for a temporary served directory, which sits next to
+// outside.txt so that traversal attempts have something to reach
+func newTestHandler(t *testing.T) (*Handler, string) {
+	t.Helper()
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	if err := os.Mkdir(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(base, "outside.txt"), "outside")
+
+	settings, err := json.Marshal(map[string]interface{}{"file_server_dir": root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(cfg), root
+}
+
+// writeFile creates the file at path, and its folders, holding content
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// serve sends a request to h and returns the recorded response
+func serve(h *Handler, method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+// exists reports whether path exists
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// traversalPaths are request paths that escape the served directory
+var traversalPaths = []string{"../outside.txt", "docs/../../outside.txt", "../root2"}
+
+// query returns target with path added as its query
+func query(target, path string) string {
+	return target + "?path=" + url.QueryEscape(path)
+}
+
+// decodeJSON decodes the body of rec into a string map
+func decodeJSON(t *testing.T, rec *httptest.ResponseRecorder) map[string]string {
+	t.Helper()
+	var body map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding %q: %v", rec.Body, err)
+	}
+	return body
+}
+
+func TestDeleteFile(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, filepath.Join(root, "docs", "a.txt"), "a")
+
+	rec := serve(h, http.MethodDelete, query("/api/delete", "/docs/a.txt"), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if got := decodeJSON(t, rec)["deleted"]; got != "/docs/a.txt" {
+		t.Errorf("deleted = %q, want /docs/a.txt", got)
+	}
+	if exists(filepath.Join(root, "docs", "a.txt")) {
+		t.Error("file still exists")
+	}
+	if !exists(filepath.Join(root, "docs")) {
+		t.Error("parent folder was deleted too")
+	}
+
+	// Deleting it again finds nothing
+	if rec := serve(h, http.MethodDelete, query("/api/delete", "/docs/a.txt"), ""); rec.Code != http.StatusNotFound {
+		t.Errorf("second delete: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestDeleteNonEmptyFolder(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, filepath.Join(root, "docs", "a.txt"), "a")
+	writeFile(t, filepath.Join(root, "docs", "nested", "b.txt"), "b")
+
+	rec := serve(h, http.MethodPost, query("/api/delete", "/docs"), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if exists(filepath.Join(root, "docs")) {
+		t.Error("folder still exists")
+	}
+}
+
+func TestDeleteRejectsTraversal(t *testing.T) {
+	h, root := newTestHandler(t)
+	os.Mkdir(filepath.Join(filepath.Dir(root), "root2"), 0755)
+
+	for _, p := range traversalPaths {
+		if rec := serve(h, http.MethodDelete, query("/api/delete", p), ""); rec.Code != http.StatusForbidden {
+			t.Errorf("%q: status = %d, want 403", p, rec.Code)
+		}
+	}
+	for _, p := range []string{"/", ".", "docs/.."} {
+		if rec := serve(h, http.MethodDelete, query("/api/delete", p), ""); rec.Code != http.StatusForbidden {
+			t.Errorf("root as %q: status = %d, want 403", p, rec.Code)
+		}
+	}
+	if rec := serve(h, http.MethodDelete, "/api/delete", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("no path: status = %d, want 400", rec.Code)
+	}
+
+	if !exists(filepath.Join(filepath.Dir(root), "outside.txt")) || !exists(filepath.Join(filepath.Dir(root), "root2")) || !exists(root) {
+		t.Error("a rejected delete removed something")
+	}
+}
+
+// mkdirBody returns the JSON body of a create-folder request
+func mkdirBody(t *testing.T, path, name string) string {
+	t.Helper()
+	data, err := json.Marshal(map[string]string{"path": path, "name": name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestMakeDir(t *testing.T) {
+	h, root := newTestHandler(t)
+	os.Mkdir(filepath.Join(root, "docs"), 0755)
+
+	rec := serve(h, http.MethodPost, "/api/mkdir", mkdirBody(t, "/docs", "new folder"))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if got := decodeJSON(t, rec)["created"]; got != "/docs/new folder" {
+		t.Errorf("created = %q, want /docs/new folder", got)
+	}
+	if info, err := os.Stat(filepath.Join(root, "docs", "new folder")); err != nil || !info.IsDir() {
+		t.Fatalf("folder not created: %v", err)
+	}
+
+	if rec := serve(h, http.MethodPost, "/api/mkdir", mkdirBody(t, "/docs", "new folder")); rec.Code != http.StatusConflict {
+		t.Errorf("existing folder: status = %d, want 409", rec.Code)
+	}
+	for _, name := range []string{"", " ", ".", ".."} {
+		if rec := serve(h, http.MethodPost, "/api/mkdir", mkdirBody(t, "/docs", name)); rec.Code != http.StatusBadRequest {
+			t.Errorf("name %q: status = %d, want 400", name, rec.Code)
+		}
+	}
+}
+
+func TestMakeDirRejectsTraversal(t *testing.T) {
+	h, root := newTestHandler(t)
+	base := filepath.Dir(root)
+
+	tests := []struct{ path, name string }{
+		{"..", "escaped"},
+		{"docs/../..", "escaped"},
+		{"docs", "../../escaped"},
+		{"", "../root2"},
+	}
+	for _, tt := range tests {
+		if rec := serve(h, http.MethodPost, "/api/mkdir", mkdirBody(t, tt.path, tt.name)); rec.Code != http.StatusForbidden {
+			t.Errorf("%q in %q: status = %d, want 403", tt.name, tt.path, rec.Code)
+		}
+	}
+	if exists(filepath.Join(base, "escaped")) || exists(filepath.Join(base, "root2")) {
+		t.Error("a folder was created outside the served directory")
+	}
+}
@@ -0,0 +1,73 @@
+package fileops
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileStat describes a file or folder inside the served directory
+type FileStat struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"` // relative to the served directory, e.g. "/docs/a.txt"
+	Size        int64  `json:"size"`
+	Mode        string `json:"mode"`        // permission bits in octal, e.g. "0644"
+	Permissions string `json:"permissions"` // e.g. "-rw-r--r--"
+	Modified    string `json:"modified"`
+	IsDir       bool   `json:"is_dir"`
+	Children    *int   `json:"children,omitempty"`  // number of entries, for folders
+	MIMEType    string `json:"mime_type,omitempty"` // guessed from the extension, for files
+}
+
+// statPath returns the details of a file or folder
+func (h *Handler) statPath(w http.ResponseWriter, r *http.Request) {
+	reqPath := r.URL.Query().Get("path")
+	if reqPath == "" {
+		http.Error(w, "Path parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	absBase, absPath, ok := h.resolve(w, reqPath)
+	if !ok {
+		return
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			http.Error(w, "Path not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	stat := FileStat{
+		Name:        info.Name(),
+		Path:        relativePath(absBase, absPath),
+		Size:        info.Size(),
+		Mode:        fmt.Sprintf("%04o", info.Mode().Perm()),
+		Permissions: info.Mode().String(),
+		Modified:    info.ModTime().Format(time.RFC3339),
+		IsDir:       info.IsDir(),
+	}
+	if info.IsDir() {
+		stat.Size = 0
+		if entries, err := os.ReadDir(absPath); err == nil {
+			count := len(entries)
+			stat.Children = &count
+		}
+	} else {
+		stat.MIMEType = mime.TypeByExtension(filepath.Ext(absPath))
+		if stat.MIMEType == "" {
+			stat.MIMEType = "application/octet-stream"
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stat)
+}
@@ -0,0 +1,64 @@
+package fileops
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestStatFile(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, filepath.Join(root, "docs", "notes.txt"), "hello")
+
+	rec := serve(h, http.MethodGet, query("/api/stat", "/docs/notes.txt"), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var stat FileStat
+	if err := json.NewDecoder(rec.Body).Decode(&stat); err != nil {
+		t.Fatal(err)
+	}
+	if stat.Name != "notes.txt" || stat.Path != "/docs/notes.txt" || stat.Size != 5 || stat.IsDir {
+		t.Errorf("stat = %+v", stat)
+	}
+	if stat.Mode != "0644" || stat.Permissions != "-rw-r--r--" {
+		t.Errorf("mode = %s %s, want 0644 -rw-r--r--", stat.Mode, stat.Permissions)
+	}
+	if stat.MIMEType != "text/plain; charset=utf-8" || stat.Children != nil {
+		t.Errorf("mime type = %q, children = %v", stat.MIMEType, stat.Children)
+	}
+}
+
+func TestStatDirectory(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, filepath.Join(root, "docs", "a.txt"), "a")
+	writeFile(t, filepath.Join(root, "docs", "sub", "b.txt"), "b")
+
+	rec := serve(h, http.MethodGet, query("/api/stat", "/docs"), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var stat FileStat
+	if err := json.NewDecoder(rec.Body).Decode(&stat); err != nil {
+		t.Fatal(err)
+	}
+	if !stat.IsDir || stat.Path != "/docs" || stat.Size != 0 || stat.MIMEType != "" {
+		t.Errorf("stat = %+v", stat)
+	}
+	if stat.Children == nil || *stat.Children != 2 {
+		t.Errorf("children = %v, want 2", stat.Children)
+	}
+}
+
+func TestStatRejectsTraversal(t *testing.T) {
+	h, _ := newTestHandler(t)
+	for _, p := range traversalPaths {
+		if rec := serve(h, http.MethodGet, query("/api/stat", p), ""); rec.Code != http.StatusForbidden {
+			t.Errorf("stat %q: status = %d, want 403", p, rec.Code)
+		}
+	}
+	if rec := serve(h, http.MethodGet, query("/api/stat", "/missing.txt"), ""); rec.Code != http.StatusNotFound {
+		t.Errorf("stat of a missing file: status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,48 @@
+package fileserver
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// fontExtensions are font files missing from some systems' MIME tables
+var fontExtensions = map[string]bool{
+	".ttf":   true,
+	".otf":   true,
+	".woff":  true,
+	".woff2": true,
+	".eot":   true,
+}
+
+// isMedia reports whether name is an image, font, audio or video file
+func isMedia(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	if fontExtensions[ext] {
+		return true
+	}
+
+	mediaType := mime.TypeByExtension(ext)
+	for _, prefix := range []string{"image/", "font/", "audio/", "video/"} {
+		if strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaCacheControl returns the Cache-Control header for a served file: a max-age for
+// media files, "no-cache" when the request has ?nocache=1, and "" to leave it unset
+func (fs *FileServer) mediaCacheControl(r *http.Request, name string) string {
+	if r.URL.Query().Get("nocache") == "1" {
+		return "no-cache"
+	}
+
+	maxAge := fs.config.GetMediaMaxAge()
+	if maxAge <= 0 || !isMedia(name) {
+		return ""
+	}
+	return fmt.Sprintf("public, max-age=%d", maxAge)
+}
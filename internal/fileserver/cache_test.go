@@ -0,0 +1,42 @@
+package fileserver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMediaCacheControl(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"photo.png", "song.MP3", "font.woff2", "notes.txt", "page.html"} {
+		writeTestFile(t, dir, name, "x")
+	}
+	fs := newTestFileServer(t, dir, map[string]interface{}{"media_max_age": 600})
+
+	tests := []struct {
+		target, want string
+	}{
+		{"/photo.png", "public, max-age=600"},
+		{"/song.MP3", "public, max-age=600"},
+		{"/font.woff2", "public, max-age=600"},
+		{"/photo.png?nocache=1", "no-cache"},
+		{"/notes.txt", ""},
+		{"/page.html", ""},
+		{"/__watcher.js", "no-cache"},
+	}
+	for _, tt := range tests {
+		if got := get(fs, tt.target).Header().Get("Cache-Control"); got != tt.want {
+			t.Errorf("%s: Cache-Control = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+
+	// The generated listing must be revalidated, never cached for a max-age
+	if got := get(fs, "/").Header().Get("Cache-Control"); got != "" && got != "no-cache" {
+		t.Errorf("listing: Cache-Control = %q, want none or no-cache", got)
+	}
+
+	// A max-age of 0 turns media caching off
+	fs = newTestFileServer(t, dir, map[string]interface{}{"media_max_age": 0})
+	if got := get(fs, "/photo.png").Header().Get("Cache-Control"); strings.Contains(got, "max-age") {
+		t.Errorf("with media_max_age 0: Cache-Control = %q, want no max-age", got)
+	}
+}
@@ -0,0 +1,347 @@
+package fileserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"simple.http.server/internal/config"
+)
+
+// newTestFileServer serves dir with the given settings added, stopping its watcher at cleanup
+func newTestFileServer(tb testing.TB, dir string, settings map[string]interface{}) *FileServer {
+	tb.Helper()
+	if settings == nil {
+		settings = map[string]interface{}{}
+	}
+	settings["file_server_dir"] = dir
+	data, err := json.Marshal(settings)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings(data); err != nil {
+		tb.Fatal(err)
+	}
+	fs := NewFileServer(cfg)
+	tb.Cleanup(func() {
+		fs.watchMu.Lock()
+		close(fs.stopWatch)
+		fs.stopWatch = nil
+		fs.watchMu.Unlock()
+	})
+	return fs
+}
+
+func TestListingEscapesNames(t *testing.T) {
+	dir := t.TempDir()
+	const fileName = `<img src=x onerror="alert(1)">.txt`
+	const dirName = `<script>alert('dir')</script>`
+	if err := os.WriteFile(filepath.Join(dir, fileName), []byte("x"), 0644); err != nil {
+		t.Skipf("file system does not allow the name: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, dirName), 0755); err != nil {
+		t.Skipf("file system does not allow the name: %v", err)
+	}
+	fs := newTestFileServer(t, dir, nil)
+
+	for _, target := range []string{"/", "/" + dirName + "/"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.URL.Path = target
+		rec := httptest.NewRecorder()
+		fs.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %q: status = %d", target, rec.Code)
+		}
+		body := rec.Body.String()
+		for _, raw := range []string{fileName, dirName, `<img src=x`, `<script>alert(`} {
+			if strings.Contains(body, raw) {
+				t.Errorf("GET %q: listing contains the unescaped %q", target, raw)
+			}
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	fs.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := rec.Body.String()
+	for _, escaped := range []string{"&lt;img src=x onerror=&#34;alert(1)&#34;&gt;.txt", "&lt;script&gt;alert(&#39;dir&#39;)&lt;/script&gt;"} {
+		if !strings.Contains(body, escaped) {
+			t.Errorf("listing does not contain the escaped name %q", escaped)
+		}
+	}
+}
+
+// writeTestFile creates the file at the slash-separated name under dir, and its folders
+func writeTestFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// get requests target from fs and returns the recorded response
+func get(fs *FileServer, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	fs.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestListingShowsSizeAndModified(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "report.bin")
+	if err := os.WriteFile(file, make([]byte, 2048), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Date(2024, 3, 5, 14, 30, 0, 0, time.Local)
+	if err := os.Chtimes(file, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "docs", "only"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	fs := newTestFileServer(t, dir, nil)
+
+	rec := get(fs, "/")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"2.0 KB", "2024-03-05 14:30", "1 item"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("listing does not contain %q", want)
+		}
+	}
+}
+
+func TestServesIndexWhenPresent(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "site/index.html", "<h1>welcome</h1>")
+	writeTestFile(t, dir, "site/other.txt", "other")
+	fs := newTestFileServer(t, dir, nil)
+
+	rec := get(fs, "/site/")
+	if rec.Code != http.StatusOK || rec.Body.String() != "<h1>welcome</h1>" {
+		t.Errorf("GET /site/: status = %d, body = %q, want the index", rec.Code, rec.Body)
+	}
+
+	rec = get(fs, "/site")
+	if rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "/site/" {
+		t.Errorf("GET /site: status = %d, Location = %q, want a redirect to /site/", rec.Code, rec.Header().Get("Location"))
+	}
+
+	// list=1 still shows the listing
+	if body := get(fs, "/site/?list=1").Body.String(); !strings.Contains(body, "other.txt") {
+		t.Error("?list=1 did not show the listing")
+	}
+}
+
+func TestListsDirectoryWithoutIndex(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "site/other.txt", "other")
+	fs := newTestFileServer(t, dir, nil)
+
+	rec := get(fs, "/site/")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "other.txt") {
+		t.Errorf("GET /site/: status = %d, want a listing naming other.txt", rec.Code)
+	}
+}
+
+func TestAutoIndexDisabled(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "site/index.html", "<h1>welcome</h1>")
+	fs := newTestFileServer(t, dir, map[string]interface{}{"auto_index": false})
+
+	rec := get(fs, "/site/")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "index.html") || strings.Contains(rec.Body.String(), "<h1>welcome</h1>") {
+		t.Errorf("GET /site/: status = %d, want a listing instead of the index", rec.Code)
+	}
+}
+
+func TestDisableListing(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "site/index.html", "<h1>welcome</h1>")
+	writeTestFile(t, dir, "private/secret.txt", "secret")
+	fs := newTestFileServer(t, dir, map[string]interface{}{"disable_listing": true, "auto_index": false})
+
+	for _, target := range []string{"/", "/private/", "/private/?list=1", "/private/?format=json"} {
+		rec := get(fs, target)
+		if rec.Code != http.StatusForbidden || strings.Contains(rec.Body.String(), "secret.txt") || strings.Contains(rec.Body.String(), "index.html") {
+			t.Errorf("GET %s: status = %d, body = %q, want 403 without a listing", target, rec.Code, rec.Body)
+		}
+	}
+
+	// An index.html is still served, even with auto_index off or list=1, and files stay reachable
+	for _, target := range []string{"/site/", "/site/?list=1"} {
+		if rec := get(fs, target); rec.Code != http.StatusOK || rec.Body.String() != "<h1>welcome</h1>" {
+			t.Errorf("GET %s: status = %d, body = %q, want the index", target, rec.Code, rec.Body)
+		}
+	}
+	if rec := get(fs, "/private/secret.txt"); rec.Code != http.StatusOK || rec.Body.String() != "secret" {
+		t.Errorf("GET /private/secret.txt: status = %d, want the file", rec.Code)
+	}
+}
+
+func TestListingRendersKnownDirectory(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "docs", "sub"), 0755)
+	writeTestFile(t, dir, "docs/a.txt", "hi\n")
+	writeTestFile(t, dir, "docs/b file.bin", "x\n")
+	fs := newTestFileServer(t, dir, nil)
+
+	body := get(fs, "/docs/").Body.String()
+	// The rows in order: parent, folder, then files by name
+	want := []string{
+		`<a href=".." class="dir item-name">..</a>`,
+		`<a href="/docs/sub/" class="dir item-name">sub</a>`,
+		`<span class="item-size">0 items</span>`,
+		`<a href="/api/archive?path=%2Fdocs%2Fsub%2F" class="action-btn" title="Download as ZIP">`,
+		`<a href="/api/preview?path=%2Fdocs%2Fa.txt" class="file item-name">a.txt</a>`,
+		`<span class="item-size">3 B</span>`,
+		`<a href="/docs/a.txt" class="action-btn" target="_blank" rel="noopener" title="Open raw file in a new tab">`,
+		`<a href="/docs/a.txt?download=1" class="action-btn" title="Download">`,
+		`<a href="/docs/b%20file.bin" class="file item-name">b file.bin</a>`,
+		`<span class="item-size">2 B</span>`,
+		`<a href="/docs/b%20file.bin?download=1" class="action-btn" title="Download">`,
+	}
+	rest := body
+	for _, fragment := range want {
+		i := strings.Index(rest, fragment)
+		if i < 0 {
+			t.Fatalf("listing is missing %s after the previous rows", fragment)
+		}
+		rest = rest[i+len(fragment):]
+	}
+
+	// Rendering again from the parsed template gives the same page
+	if again := get(fs, "/docs/").Body.String(); again != body {
+		t.Error("a second render of the unchanged directory differs")
+	}
+}
+
+// benchmarkListing renders a directory of 200 files with render, as serveDirectory does
+func benchmarkListing(b *testing.B, render func(fs *FileServer, w http.ResponseWriter, r *http.Request, fullPath, urlPath string)) {
+	dir := b.TempDir()
+	for i := 0; i < 200; i++ {
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("file-%03d.txt", i)), []byte("x"), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	fs := newTestFileServer(b, dir, nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rec := httptest.NewRecorder()
+		render(fs, rec, httptest.NewRequest(http.MethodGet, "/", nil), dir, "/")
+		if rec.Code != http.StatusOK {
+			b.Fatalf("status = %d", rec.Code)
+		}
+	}
+}
+
+func BenchmarkListing(b *testing.B) {
+	benchmarkListing(b, (*FileServer).serveDirectory)
+}
+
+func BenchmarkLegacyListing(b *testing.B) {
+	benchmarkListing(b, (*FileServer).legacyServeDirectory)
+}
+
+// getIfNoneMatch requests target from fs with an If-None-Match of tag
+func getIfNoneMatch(fs *FileServer, target, tag string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("If-None-Match", tag)
+	rec := httptest.NewRecorder()
+	fs.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestListingETag(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "a.txt", "a")
+	fs := newTestFileServer(t, dir, nil)
+
+	tag := get(fs, "/").Header().Get("ETag")
+	if tag == "" {
+		t.Fatal("listing has no ETag")
+	}
+	rec := getIfNoneMatch(fs, "/", tag)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("unchanged listing: status = %d with %d bytes, want an empty 304", rec.Code, rec.Body.Len())
+	}
+
+	// A different sort order is a different page
+	if rec := getIfNoneMatch(fs, "/?sort=size", tag); rec.Code != http.StatusOK {
+		t.Errorf("other sort order: status = %d, want 200", rec.Code)
+	}
+
+	changes := []func(){
+		func() { writeTestFile(t, dir, "b.txt", "b") },
+		func() { writeTestFile(t, dir, "a.txt", "longer") },
+		func() { os.Remove(filepath.Join(dir, "b.txt")) },
+	}
+	for i, change := range changes {
+		change()
+		rec := getIfNoneMatch(fs, "/", tag)
+		if rec.Code != http.StatusOK {
+			t.Errorf("change %d: status = %d, want 200", i, rec.Code)
+		}
+		if next := rec.Header().Get("ETag"); next == tag {
+			t.Errorf("change %d: ETag unchanged", i)
+		} else {
+			tag = next
+		}
+	}
+}
+
+func TestNotFoundPage(t *testing.T) {
+	dir := t.TempDir()
+	os.Mkdir(filepath.Join(dir, "docs"), 0755)
+	fs := newTestFileServer(t, dir, nil)
+
+	rec := get(fs, "/docs/missing%20file.txt")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want HTML", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"404", "/docs/missing file.txt", `href="/docs/"`, `href="/"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("404 page does not contain %s", want)
+		}
+	}
+
+	// JSON clients still get a plain 404
+	req := httptest.NewRequest(http.MethodGet, "/docs/missing.txt", nil)
+	req.Header.Set("Accept", "application/json")
+	rec = httptest.NewRecorder()
+	fs.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound || strings.Contains(rec.Body.String(), "<html") {
+		t.Errorf("JSON client: status = %d, body %q, want a plain 404", rec.Code, rec.Body)
+	}
+}
+
+func TestCustomNotFoundPage(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "404.html", "<h1>Nothing here, sorry</h1>")
+	fs := newTestFileServer(t, dir, nil)
+
+	for _, target := range []string{"/missing.txt", "/deep/missing/path"} {
+		rec := get(fs, target)
+		if rec.Code != http.StatusNotFound || rec.Body.String() != "<h1>Nothing here, sorry</h1>" {
+			t.Errorf("GET %s: status = %d, body %q, want the site's 404.html", target, rec.Code, rec.Body)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package fileserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"simple.http.server/internal/etag"
+	"simple.http.server/internal/search"
+)
+
+// listingEntry holds the details of a directory entry used to render a listing
+type listingEntry struct {
+	Name    string
+	IsDir   bool
+	Size    int64
+	ModTime time.Time
+	HasInfo bool
+}
+
+// listingSort describes how a directory listing is ordered, whether it shows hidden
+// entries and whether it is laid out as a list or a grid of thumbnails
+type listingSort struct {
+	Key  string // "name", "size" or "mtime"
+	Desc bool
+
+	ShowHidden    bool // whether entries starting with "." are listed
+	defaultHidden bool // the configured ShowHidden, which the query string can omit
+
+	Grid        bool // whether entries are shown as a grid instead of a list
+	defaultGrid bool // the view remembered in the viewCookie, which the query string can omit
+}
+
+// viewCookie remembers the listing view ("list" or "grid") chosen with the toggle button
+const viewCookie = "listing_view"
+
+// readListing reads a directory and collects file info for each entry
+func readListing(dir string) ([]listingEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	listing := make([]listingEntry, 0, len(entries))
+	for _, entry := range entries {
+		le := listingEntry{
+			Name:  entry.Name(),
+			IsDir: entry.IsDir(),
+		}
+		if info, err := entry.Info(); err == nil {
+			if !le.IsDir {
+				le.Size = info.Size()
+			}
+			le.ModTime = info.ModTime()
+			le.HasInfo = true
+		}
+		listing = append(listing, le)
+	}
+	return listing, nil
+}
+
+// listingETag identifies a rendered listing by its path, sort order, the mounts it
+// lists and the name, size and modification time of every entry
+func listingETag(urlPath string, ls listingSort, entries []listingEntry, mounts []Mount) string {
+	parts := []string{urlPath, ls.Query(), fmt.Sprintf("hidden=%t grid=%t", ls.ShowHidden, ls.Grid)}
+	for _, m := range mounts {
+		parts = append(parts, "mount|"+m.Name)
+	}
+	for _, entry := range entries {
+		parts = append(parts, fmt.Sprintf("%s|%t|%d|%d", entry.Name, entry.IsDir, entry.Size, entry.ModTime.UnixNano()))
+	}
+	return etag.New(parts...)
+}
+
+// parseListingSort reads the sort, order, hidden and view query parameters, defaulting to
+// name ascending, to showHidden for hidden entries and to the remembered view
+func parseListingSort(r *http.Request, showHidden bool) listingSort {
+	ls := listingSort{Key: "name", ShowHidden: showHidden, defaultHidden: showHidden}
+	if cookie, err := r.Cookie(viewCookie); err == nil && cookie.Value == "grid" {
+		ls.Grid, ls.defaultGrid = true, true
+	}
+
+	switch key := r.URL.Query().Get("sort"); key {
+	case "name", "size", "mtime":
+		ls.Key = key
+	}
+	ls.Desc = r.URL.Query().Get("order") == "desc"
+
+	switch r.URL.Query().Get("hidden") {
+	case "1":
+		ls.ShowHidden = true
+	case "0":
+		ls.ShowHidden = false
+	}
+
+	switch r.URL.Query().Get("view") {
+	case "grid":
+		ls.Grid = true
+	case "list":
+		ls.Grid = false
+	}
+	return ls
+}
+
+// Query returns the query string that preserves this sort, or "" for the default view
+func (ls listingSort) Query() string {
+	values := url.Values{}
+	if ls.Key != "name" || ls.Desc {
+		order := "asc"
+		if ls.Desc {
+			order = "desc"
+		}
+		values.Set("sort", ls.Key)
+		values.Set("order", order)
+	}
+	if ls.ShowHidden != ls.defaultHidden {
+		if ls.ShowHidden {
+			values.Set("hidden", "1")
+		} else {
+			values.Set("hidden", "0")
+		}
+	}
+	if ls.Grid != ls.defaultGrid {
+		if ls.Grid {
+			values.Set("view", "grid")
+		} else {
+			values.Set("view", "list")
+		}
+	}
+
+	if len(values) == 0 {
+		return ""
+	}
+	return "?" + values.Encode()
+}
+
+// filterHidden drops entries whose names start with "." unless the sort shows them
+func filterHidden(entries []listingEntry, ls listingSort) []listingEntry {
+	if ls.ShowHidden {
+		return entries
+	}
+
+	visible := entries[:0]
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name, ".") {
+			visible = append(visible, entry)
+		}
+	}
+	return visible
+}
+
+// sortListing orders entries with directories first, then by the chosen key.
+// Ties are broken by name so the order is stable across requests.
+func sortListing(entries []listingEntry, ls listingSort) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.IsDir != b.IsDir {
+			return a.IsDir
+		}
+
+		var cmp int
+		switch ls.Key {
+		case "size":
+			cmp = compareInt64(a.Size, b.Size)
+		case "mtime":
+			cmp = compareInt64(a.ModTime.UnixNano(), b.ModTime.UnixNano())
+		}
+		if cmp == 0 {
+			cmp = strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		}
+		if cmp == 0 {
+			cmp = strings.Compare(a.Name, b.Name)
+		}
+
+		if ls.Desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+}
+
+// wantsJSON reports whether the client asked for a machine-readable listing
+func wantsJSON(r *http.Request) bool {
+	return r.URL.Query().Get("format") == "json" ||
+		strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+// serveDirectoryJSON writes the directory listing as a JSON array
+func (fs *FileServer) serveDirectoryJSON(w http.ResponseWriter, r *http.Request, fullPath, urlPath string) {
+	entries, err := readListing(fullPath)
+	if err != nil {
+		http.Error(w, "Unable to read directory", http.StatusInternalServerError)
+		return
+	}
+	listSort := parseListingSort(r, fs.config.GetShowHidden())
+	entries = filterHidden(entries, listSort)
+	sortListing(entries, listSort)
+
+	results := make([]search.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		fi := search.FileInfo{
+			Name:  entry.Name,
+			Path:  path.Join("/", fs.pagePath(filepath.ToSlash(urlPath)), entry.Name),
+			Size:  entry.Size,
+			IsDir: entry.IsDir,
+		}
+		if entry.HasInfo {
+			fi.Modified = entry.ModTime.Format(time.RFC3339)
+		}
+		results = append(results, fi)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// breadcrumbHTML renders the current path as links to each ancestor directory.
+// Inside a mount or under a prefix, its last segment is the first link after Home.
+func (fs *FileServer) breadcrumbHTML(urlPath, query string) string {
+	var b strings.Builder
+	b.WriteString(`<nav class="breadcrumb">`)
+	fmt.Fprintf(&b, `<a href="/%s">Home</a>`, query)
+
+	href := "/"
+	if fs.prefix != "" {
+		href = fs.prefix + "/"
+		fmt.Fprintf(&b, `<span class="crumb-sep">/</span><a href="%s%s">%s</a>`,
+			escapeURLPath(href), query, html.EscapeString(path.Base(fs.prefix)))
+	}
+	for _, segment := range strings.Split(strings.Trim(filepath.ToSlash(urlPath), "/"), "/") {
+		if segment == "" {
+			continue
+		}
+		href += segment + "/"
+		fmt.Fprintf(&b, `<span class="crumb-sep">/</span><a href="%s%s">%s</a>`,
+			escapeURLPath(href), query, html.EscapeString(segment))
+	}
+	b.WriteString(`</nav>`)
+	return b.String()
+}
+
+// sortBarHTML renders links for choosing the listing order.
+// Clicking the active key toggles between ascending and descending.
+func sortBarHTML(current listingSort) string {
+	keys := []struct{ key, label string }{
+		{"name", "Name"},
+		{"size", "Size"},
+		{"mtime", "Modified"},
+	}
+
+	var b strings.Builder
+	b.WriteString(`<div class="sort-bar"><span>Sort:</span>`)
+	for _, k := range keys {
+		next := current
+		next.Key, next.Desc = k.key, false
+		class := "sort-link"
+		label := k.label
+		if current.Key == k.key {
+			next.Desc = !current.Desc
+			class += " active"
+			if current.Desc {
+				label += " ▼"
+			} else {
+				label += " ▲"
+			}
+		}
+
+		href := next.Query()
+		if href == "" {
+			href = "?"
+		}
+		fmt.Fprintf(&b, `<a href="%s" class="%s">%s</a>`, html.EscapeString(href), class, label)
+	}
+	b.WriteString(`</div>`)
+	return b.String()
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
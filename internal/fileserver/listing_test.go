@@ -0,0 +1,330 @@
+package fileserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// names returns the names of entries in order
+func names(entries []listingEntry) []string {
+	result := make([]string, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.Name
+	}
+	return result
+}
+
+func TestSortListing(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []listingEntry{
+		{Name: "b.txt", Size: 10, ModTime: base.Add(2 * time.Hour)},
+		{Name: "A.txt", Size: 30, ModTime: base},
+		{Name: "c.txt", Size: 10, ModTime: base.Add(time.Hour)},
+		{Name: "zdir", IsDir: true, ModTime: base},
+		{Name: "adir", IsDir: true, ModTime: base.Add(time.Hour)},
+		{Name: "a.txt", Size: 20, ModTime: base.Add(time.Hour)},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"adir", "zdir", "A.txt", "a.txt", "b.txt", "c.txt"}},
+		{"?sort=bogus", []string{"adir", "zdir", "A.txt", "a.txt", "b.txt", "c.txt"}},
+		{"?sort=name&order=desc", []string{"zdir", "adir", "c.txt", "b.txt", "a.txt", "A.txt"}},
+		// Equal sizes and times fall back to the name
+		{"?sort=size", []string{"adir", "zdir", "b.txt", "c.txt", "a.txt", "A.txt"}},
+		{"?sort=size&order=desc", []string{"zdir", "adir", "A.txt", "a.txt", "c.txt", "b.txt"}},
+		{"?sort=mtime", []string{"zdir", "adir", "A.txt", "a.txt", "c.txt", "b.txt"}},
+		{"?sort=mtime&order=desc", []string{"adir", "zdir", "b.txt", "c.txt", "a.txt", "A.txt"}},
+	}
+	for _, tt := range tests {
+		sorted := append([]listingEntry(nil), entries...)
+		sortListing(sorted, parseListingSort(httptest.NewRequest(http.MethodGet, "/"+tt.query, nil), false))
+		if got := names(sorted); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: order = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestListingSortQuery(t *testing.T) {
+	tests := []struct {
+		query, want string
+	}{
+		{"", ""},
+		{"?sort=bogus&order=desc", "?order=desc&sort=name"},
+		{"?sort=size", "?order=asc&sort=size"},
+		{"?sort=mtime&order=desc", "?order=desc&sort=mtime"},
+	}
+	for _, tt := range tests {
+		ls := parseListingSort(httptest.NewRequest(http.MethodGet, "/"+tt.query, nil), false)
+		if got := ls.Query(); got != tt.want {
+			t.Errorf("%q: Query() = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestListingLinksKeepSort(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "docs/a.txt", "a")
+	writeTestFile(t, dir, "b.txt", "bb")
+	fs := newTestFileServer(t, dir, nil)
+
+	body := get(fs, "/docs/?sort=size&order=desc").Body.String()
+	for _, want := range []string{
+		`href="/?order=desc&amp;sort=size"`,
+		`href="/docs/?order=desc&amp;sort=size"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("listing does not link to %s", want)
+		}
+	}
+}
+
+func TestListingJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "docs/a.txt", "a")
+	writeTestFile(t, dir, "docs/zeta/b.txt", "b")
+	writeTestFile(t, dir, "docs/Beta.txt", "hello")
+	writeTestFile(t, dir, "docs/alpha/c.txt", "c")
+	fs := newTestFileServer(t, dir, nil)
+
+	requests := []struct{ name, target, accept string }{
+		{"format=json", "/docs/?format=json", ""},
+		{"Accept", "/docs/", "application/json"},
+	}
+	for _, tt := range requests {
+		name := tt.name
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		req.Header.Set("Accept", tt.accept)
+		rec := httptest.NewRecorder()
+		fs.ServeHTTP(rec, req)
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Fatalf("%s: Content-Type = %q", name, ct)
+		}
+
+		var got []map[string]interface{}
+		if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var order []string
+		for _, entry := range got {
+			order = append(order, entry["name"].(string))
+		}
+		if want := []string{"alpha", "zeta", "a.txt", "Beta.txt"}; !reflect.DeepEqual(order, want) {
+			t.Errorf("%s: order = %v, want directories first: %v", name, order, want)
+		}
+
+		beta := got[3]
+		for key, want := range map[string]interface{}{
+			"name": "Beta.txt", "path": "/docs/Beta.txt", "size": float64(5), "is_dir": false,
+		} {
+			if beta[key] != want {
+				t.Errorf("%s: %s = %v, want %v", name, key, beta[key], want)
+			}
+		}
+		if _, err := time.Parse(time.RFC3339, beta["modified"].(string)); err != nil {
+			t.Errorf("%s: modified is not RFC 3339: %v", name, err)
+		}
+		if got[0]["is_dir"] != true || got[0]["path"] != "/docs/alpha" {
+			t.Errorf("%s: first entry = %v, want the alpha directory", name, got[0])
+		}
+	}
+
+	// Browsers still get HTML
+	rec := get(fs, "/docs/")
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("default Content-Type = %q, want HTML", ct)
+	}
+}
+
+func TestListingBreadcrumb(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "a/b/c/file.txt", "x")
+	writeTestFile(t, dir, "x&y/<z>/file.txt", "x")
+	fs := newTestFileServer(t, dir, nil)
+
+	body := get(fs, "/a/b/c/").Body.String()
+	crumbs := []string{
+		`<a href="/">Home</a>`,
+		`<a href="/a/">a</a>`,
+		`<a href="/a/b/">b</a>`,
+		`<a href="/a/b/c/">c</a>`,
+	}
+	last := -1
+	for _, crumb := range crumbs {
+		i := strings.Index(body, crumb)
+		if i < 0 {
+			t.Errorf("breadcrumb has no %s", crumb)
+			continue
+		}
+		if i < last {
+			t.Errorf("breadcrumb %s is out of order", crumb)
+		}
+		last = i
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.URL.Path = "/x&y/<z>/"
+	rec := httptest.NewRecorder()
+	fs.ServeHTTP(rec, req)
+	for _, crumb := range []string{
+		`<a href="/x&amp;y/">x&amp;y</a>`,
+		`<a href="/x&amp;y/%3Cz%3E/">&lt;z&gt;</a>`,
+	} {
+		if !strings.Contains(rec.Body.String(), crumb) {
+			t.Errorf("breadcrumb has no escaped %s", crumb)
+		}
+	}
+}
+
+// listedNames returns the names of the entries in the JSON listing of target
+func listedNames(t *testing.T, fs *FileServer, target string) []string {
+	t.Helper()
+	rec := get(fs, target)
+	var entries []struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&entries); err != nil {
+		t.Fatalf("%s: %v", target, err)
+	}
+	var result []string
+	for _, entry := range entries {
+		result = append(result, entry.Name)
+	}
+	return result
+}
+
+func TestListingHiddenEntries(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "project/.env", "SECRET=1")
+	writeTestFile(t, dir, "project/.git/HEAD", "ref")
+	writeTestFile(t, dir, "project/main.go", "package main")
+	all := []string{".git", ".env", "main.go"}
+	visible := []string{"main.go"}
+
+	tests := []struct {
+		showHidden bool
+		query      string
+		want       []string
+	}{
+		{false, "", visible},
+		{false, "&hidden=1", all},
+		{true, "", all},
+		{true, "&hidden=0", visible},
+	}
+	for _, tt := range tests {
+		fs := newTestFileServer(t, dir, map[string]interface{}{"show_hidden": tt.showHidden})
+		if got := listedNames(t, fs, "/project/?format=json"+tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("show_hidden %v, query %q: entries = %v, want %v", tt.showHidden, tt.query, got, tt.want)
+		}
+
+		// The HTML listing agrees, and always links to the parent
+		body := get(fs, "/project/?"+strings.TrimPrefix(tt.query, "&")).Body.String()
+		if shown := strings.Contains(body, `item-name">.env</a>`); shown != (len(tt.want) == len(all)) {
+			t.Errorf("show_hidden %v, query %q: .env shown = %v in the HTML listing", tt.showHidden, tt.query, shown)
+		}
+		if !strings.Contains(body, `class="dir item-name">..</a>`) {
+			t.Errorf("show_hidden %v, query %q: parent link missing", tt.showHidden, tt.query)
+		}
+	}
+}
+
+func TestListingGridView(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "photos/beach.jpg", "jpg")
+	writeTestFile(t, dir, "photos/notes.txt", "txt")
+	fs := newTestFileServer(t, dir, nil)
+
+	list := get(fs, "/photos/").Body.String()
+	grid := get(fs, "/photos/?view=grid").Body.String()
+	if list == grid {
+		t.Fatal("grid view renders the same markup as the list view")
+	}
+
+	if !strings.Contains(list, `<ul id="file-list">`) || strings.Contains(list, `class="grid-view"`) {
+		t.Error("list view is not the default")
+	}
+	if !strings.Contains(list, "/api/thumbnail?size=80&amp;path=%2Fphotos%2Fbeach.jpg") || !strings.Contains(list, "Grid view") {
+		t.Error("list view lacks small thumbnails or the grid toggle")
+	}
+
+	if !strings.Contains(grid, `<ul id="file-list" class="grid-view">`) {
+		t.Error("grid view lacks the grid layout")
+	}
+	if !strings.Contains(grid, "/api/thumbnail?size=256&amp;path=%2Fphotos%2Fbeach.jpg") || !strings.Contains(grid, "List view") {
+		t.Error("grid view lacks large thumbnails or the list toggle")
+	}
+	// Files without thumbnails keep their icon
+	if strings.Contains(grid, "/api/thumbnail?size=256&amp;path=%2Fphotos%2Fnotes.txt") {
+		t.Error("grid view requests a thumbnail for a text file")
+	}
+
+	// The remembered view applies unless the query overrides it
+	view := func(target string) string {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.AddCookie(&http.Cookie{Name: viewCookie, Value: "grid"})
+		rec := httptest.NewRecorder()
+		fs.ServeHTTP(rec, req)
+		if strings.Contains(rec.Body.String(), `class="grid-view"`) {
+			return "grid"
+		}
+		return "list"
+	}
+	if got := view("/photos/"); got != "grid" {
+		t.Errorf("with the grid cookie: view = %s, want grid", got)
+	}
+	if got := view("/photos/?view=list"); got != "list" {
+		t.Errorf("with the grid cookie and view=list: view = %s, want list", got)
+	}
+}
+
+func TestListingNameLinks(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "src/main.go", "package main")
+	writeTestFile(t, dir, "src/data.bin", "\x00\x01")
+
+	goPreview := `<a href="/api/preview?path=%2Fsrc%2Fmain.go" class="file item-name">main.go</a>`
+	goRaw := `<a href="/src/main.go" class="file item-name">main.go</a>`
+	binRaw := `<a href="/src/data.bin" class="file item-name">data.bin</a>`
+
+	fs := newTestFileServer(t, dir, map[string]interface{}{"preview_links": true})
+	body := get(fs, "/src/").Body.String()
+	if !strings.Contains(body, goPreview) {
+		t.Error("main.go name does not link to its preview")
+	}
+	if !strings.Contains(body, `<a href="/src/main.go" class="action-btn" target="_blank"`) {
+		t.Error("main.go has no action opening the raw file")
+	}
+	if !strings.Contains(body, binRaw) {
+		t.Error("data.bin name does not link to the raw file")
+	}
+
+	fs = newTestFileServer(t, dir, map[string]interface{}{"preview_links": false})
+	body = get(fs, "/src/").Body.String()
+	if !strings.Contains(body, goRaw) || !strings.Contains(body, binRaw) {
+		t.Error("with preview_links off, names do not link to the raw files")
+	}
+}
+
+func TestListingSelectionCheckboxes(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "docs/a.txt", "a")
+	writeTestFile(t, dir, "docs/sub/b.txt", "b")
+	fs := newTestFileServer(t, dir, nil)
+
+	body := get(fs, "/docs/").Body.String()
+	for _, path := range []string{"/docs/a.txt", "/docs/sub/"} {
+		if !strings.Contains(body, `<input type="checkbox" class="select-box" value="`+path+`"`) {
+			t.Errorf("no checkbox selecting %s", path)
+		}
+	}
+	if !strings.Contains(body, `id="downloadSelectedBtn"`) || !strings.Contains(body, "form.action = '/api/archive'") {
+		t.Error("listing lacks the button posting the selection to the archive endpoint")
+	}
+}
@@ -0,0 +1,57 @@
+package fileserver
+
+import (
+	"path/filepath"
+	"strings"
+
+	"simple.http.server/internal/config"
+)
+
+// Mount is an extra directory served under /mnt/<Name>/
+type Mount struct {
+	Name string
+	Dir  string // absolute path of the directory
+}
+
+// URLPath returns the path the mount is served under, e.g. "/mnt/docs/"
+func (m Mount) URLPath() string {
+	return "/mnt/" + m.Name + "/"
+}
+
+// NewMount creates a file server for a mounted directory. It expects requests with
+// the mount's URL prefix stripped, and it does not watch the directory for changes.
+func NewMount(cfg *config.Config, m Mount) *FileServer {
+	return &FileServer{
+		clients: make(map[chan ChangeEvent]bool),
+		config:  cfg,
+		mount:   &m,
+		prefix:  strings.TrimSuffix(m.URLPath(), "/"),
+	}
+}
+
+// SetMounts sets the mounts listed on the root page
+func (fs *FileServer) SetMounts(mounts []Mount) {
+	fs.mounts = mounts
+}
+
+// SetPrefix sets the URL path the server is served under, such as "/files", so its
+// pages link to the right place. Requests must reach it with the prefix stripped.
+func (fs *FileServer) SetPrefix(prefix string) {
+	fs.prefix = strings.TrimSuffix(prefix, "/")
+}
+
+// rootDir returns the directory being served
+func (fs *FileServer) rootDir() string {
+	if fs.mount != nil {
+		return fs.mount.Dir
+	}
+	return fs.config.GetFileServerDir()
+}
+
+// pagePath returns the URL path a browser sees for urlPath, which is relative to the prefix
+func (fs *FileServer) pagePath(urlPath string) string {
+	if fs.prefix == "" {
+		return urlPath
+	}
+	return fs.prefix + filepath.ToSlash(urlPath)
+}
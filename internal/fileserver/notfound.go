@@ -0,0 +1,50 @@
+package fileserver
+
+import (
+	_ "embed"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+//go:embed notfound.html
+var notFoundHTML string
+
+// notFoundTemplate renders the 404 page used when the served directory has no 404.html
+var notFoundTemplate = template.Must(template.New("notfound").Parse(notFoundHTML))
+
+// serveNotFound replies 404 with the served directory's 404.html when there is one,
+// otherwise with a page linking to the parent directory and the root
+func (fs *FileServer) serveNotFound(w http.ResponseWriter, r *http.Request, dir, urlPath string) {
+	if wantsJSON(r) {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	if custom, err := os.ReadFile(filepath.Join(dir, "404.html")); err == nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write(custom)
+		return
+	}
+
+	urlPath = filepath.ToSlash(urlPath)
+	parent := path.Dir(urlPath)
+	if parent != "/" {
+		parent += "/"
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+	data := struct {
+		Path   string
+		Parent template.URL
+	}{urlPath, template.URL(urlPathEscape(parent))}
+	if err := notFoundTemplate.Execute(w, data); err != nil {
+		log.Printf("Failed to render 404 page for %s: %v", urlPath, err)
+	}
+}
@@ -0,0 +1,76 @@
+package fileserver
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// pollInterval is how often the polling watcher rescans the served directory
+const pollInterval = 2 * time.Second
+
+// fileState is the part of a file's metadata the poller compares between scans
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// pollFiles detects changes by periodically walking root and comparing modification
+// times and sizes until stop is closed. It is used where fsnotify is unavailable.
+func (fs *FileServer) pollFiles(root string, ignore []string, stop <-chan struct{}) {
+	log.Printf("Polling %s for changes every %s", root, pollInterval)
+
+	prev := scanTree(root, ignore)
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			log.Printf("Stopped polling %s", root)
+			return
+		case <-ticker.C:
+		}
+
+		cur := scanTree(root, ignore)
+
+		for path, state := range cur {
+			old, existed := prev[path]
+			if !existed {
+				fs.BroadcastChange(newChangeEvent(root, path, "created"))
+			} else if !state.modTime.Equal(old.modTime) || state.size != old.size {
+				fs.BroadcastChange(newChangeEvent(root, path, "modified"))
+			}
+		}
+		for path := range prev {
+			if _, exists := cur[path]; !exists {
+				fs.BroadcastChange(newChangeEvent(root, path, "removed"))
+			}
+		}
+
+		prev = cur
+	}
+}
+
+// scanTree records the state of every path below root that is not ignored
+func scanTree(root string, ignore []string) map[string]fileState {
+	states := make(map[string]fileState)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		if path == root {
+			return nil
+		}
+		if isIgnored(root, path, ignore) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		states[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	return states
+}
@@ -8,21 +8,38 @@
         console.log('Connected to file watcher');
     };
     
-    eventSource.onmessage = function(event) {
-        console.log('File change detected:', event.data);
-        if (event.data && event.data !== 'Connected to file watcher') {
-            // Reload the page when a file change is detected
-            setTimeout(() => {
-                window.location.reload();
-            }, 300);
+    // Each change is sent as a named event whose data is a JSON object:
+    // {"type": "created", "path": "/dir/file.txt", "name": "file.txt", "time": "..."}
+    function onChange(event) {
+        let change;
+        try {
+            change = JSON.parse(event.data);
+        } catch (e) {
+            return;
         }
-    };
+        console.log('File change detected:', change.type, change.path);
+        
+        // Reload the page when a file change is detected
+        setTimeout(() => {
+            window.location.reload();
+        }, 300);
+    }
     
+    // "resync" means changes were missed while disconnected and can't be replayed
+    ['created', 'modified', 'removed', 'renamed', 'resync'].forEach(function(type) {
+        eventSource.addEventListener(type, onChange);
+    });
+    
+    // After a network blip the browser reconnects by itself, sending the id of the last
+    // event it received so the server can replay the changes made in the meantime
     eventSource.onerror = function(error) {
+        if (eventSource.readyState !== EventSource.CLOSED) {
+            console.warn('File watcher disconnected, reconnecting');
+            return;
+        }
         console.error('File watcher error:', error);
-        eventSource.close();
         
-        // Attempt to reconnect after 5 seconds
+        // The browser gave up; reload after 5 seconds to start over
         setTimeout(() => {
             window.location.reload();
         }, 5000);
@@ -4,18 +4,26 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
+	"simple.http.server/internal/pathutil"
+
 	"github.com/fsnotify/fsnotify"
 )
 
-// addDirRecursive adds a directory and all its subdirectories to the watcher
-func addDirRecursive(watcher *fsnotify.Watcher, dir string) error {
+// addDirRecursive adds a directory and all its subdirectories to the watcher,
+// skipping directories that match an ignore pattern
+func addDirRecursive(watcher *fsnotify.Watcher, root, dir string, ignore []string) error {
 	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 		if info.IsDir() {
+			if isIgnored(root, path, ignore) {
+				log.Printf("Not watching ignored directory: %s", path)
+				return filepath.SkipDir
+			}
 			err = watcher.Add(path)
 			if err != nil {
 				log.Printf("Error watching directory %s: %v", path, err)
@@ -27,15 +35,46 @@ func addDirRecursive(watcher *fsnotify.Watcher, dir string) error {
 	})
 }
 
-// watchFiles watches for file system changes and broadcasts them
-func (fs *FileServer) watchFiles() {
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		log.Printf("Error creating file watcher: %v", err)
-		return
+// isIgnored reports whether path, relative to root, matches an ignore pattern
+func isIgnored(root, path string, ignore []string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return false
 	}
-	defer watcher.Close()
+	return pathutil.MatchesAny(filepath.ToSlash(rel), ignore)
+}
 
+// eventType names the kind of change an fsnotify operation represents
+func eventType(op fsnotify.Op) string {
+	switch {
+	case op&fsnotify.Create == fsnotify.Create:
+		return "created"
+	case op&fsnotify.Remove == fsnotify.Remove:
+		return "removed"
+	case op&fsnotify.Rename == fsnotify.Rename:
+		return "renamed"
+	default:
+		return "modified"
+	}
+}
+
+// newChangeEvent builds the event broadcast for a change to path inside root
+func newChangeEvent(root, path, eventType string) ChangeEvent {
+	urlPath := "/" + filepath.Base(path)
+	if rel, err := filepath.Rel(root, path); err == nil {
+		urlPath = "/" + filepath.ToSlash(rel)
+	}
+	return ChangeEvent{
+		Type: eventType,
+		Path: urlPath,
+		Name: filepath.Base(path),
+		Time: time.Now(),
+	}
+}
+
+// watchFiles watches for file system changes and broadcasts them until stop is closed,
+// falling back to polling when polling is configured or fsnotify cannot be set up
+func (fs *FileServer) watchFiles(stop <-chan struct{}) {
 	// Watch the configured directory
 	dir := fs.config.GetFileServerDir()
 	absDir, err := filepath.Abs(dir)
@@ -43,52 +82,82 @@ func (fs *FileServer) watchFiles() {
 		log.Printf("Error getting absolute path: %v", err)
 		return
 	}
+	ignore := fs.config.GetWatchIgnore()
+
+	if fs.config.GetWatchPoll() {
+		fs.pollFiles(absDir, ignore, stop)
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("Error creating file watcher, falling back to polling: %v", err)
+		fs.pollFiles(absDir, ignore, stop)
+		return
+	}
+	defer watcher.Close()
 
 	// Add the directory and all subdirectories recursively
-	err = addDirRecursive(watcher, absDir)
+	err = addDirRecursive(watcher, absDir, absDir, ignore)
 	if err != nil {
-		log.Printf("Error setting up recursive watch: %v", err)
+		log.Printf("Error setting up recursive watch, falling back to polling: %v", err)
+		watcher.Close()
+		fs.pollFiles(absDir, ignore, stop)
 		return
 	}
 
-	// Debounce timer to avoid too many updates
+	// Debounce timer to avoid too many updates; the changes of a burst are
+	// collected so each changed path is still reported once
 	var debounceTimer *time.Timer
-	debounceDuration := 500 * time.Millisecond
+	debounceDuration := fs.config.GetWatchDebounce()
+	batch := &changeBatch{}
 
 	for {
 		select {
+		case <-stop:
+			if debounceTimer != nil {
+				debounceTimer.Stop()
+			}
+			log.Printf("Stopped watching %s", absDir)
+			return
+
 		case event, ok := <-watcher.Events:
 			if !ok {
 				return
 			}
 
+			if isIgnored(absDir, event.Name, ignore) {
+				continue
+			}
+
 			// If a new directory is created, add it to the watcher
 			if event.Op&fsnotify.Create == fsnotify.Create {
 				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
-					err = addDirRecursive(watcher, event.Name)
+					err = addDirRecursive(watcher, absDir, event.Name, ignore)
 					if err != nil {
 						log.Printf("Error adding new directory to watch: %v", err)
 					}
 				}
 			}
 
+			change := newChangeEvent(absDir, event.Name, eventType(event.Op))
+
+			// Without a debounce every event is forwarded as it arrives
+			if debounceDuration == 0 {
+				fs.BroadcastChange(change)
+				continue
+			}
+
 			// Reset debounce timer
 			if debounceTimer != nil {
 				debounceTimer.Stop()
 			}
 
+			batch.add(change)
 			debounceTimer = time.AfterFunc(debounceDuration, func() {
-				eventType := "modified"
-				if event.Op&fsnotify.Create == fsnotify.Create {
-					eventType = "created"
-				} else if event.Op&fsnotify.Remove == fsnotify.Remove {
-					eventType = "removed"
-				} else if event.Op&fsnotify.Rename == fsnotify.Rename {
-					eventType = "renamed"
+				for _, change := range batch.take() {
+					fs.BroadcastChange(change)
 				}
-
-				fileName := filepath.Base(event.Name)
-				fs.BroadcastChange(fileName + " " + eventType)
 			})
 
 		case err, ok := <-watcher.Errors:
@@ -99,3 +168,40 @@ func (fs *FileServer) watchFiles() {
 		}
 	}
 }
+
+// changeBatch collects the changes seen during one debounce burst, keeping one change
+// per path in the order the paths first changed
+type changeBatch struct {
+	mu      sync.Mutex
+	paths   []string
+	changes map[string]ChangeEvent
+}
+
+// add records change, replacing an earlier change to the same path. A file created and
+// then written during the burst is still reported as created.
+func (b *changeBatch) add(change ChangeEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.changes == nil {
+		b.changes = make(map[string]ChangeEvent)
+	}
+	earlier, seen := b.changes[change.Path]
+	if !seen {
+		b.paths = append(b.paths, change.Path)
+	} else if earlier.Type == "created" && change.Type == "modified" {
+		change.Type = "created"
+	}
+	b.changes[change.Path] = change
+}
+
+// take returns the collected changes and empties the batch
+func (b *changeBatch) take() []ChangeEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	changes := make([]ChangeEvent, 0, len(b.paths))
+	for _, path := range b.paths {
+		changes = append(changes, b.changes[path])
+	}
+	b.paths, b.changes = nil, nil
+	return changes
+}
@@ -0,0 +1,340 @@
+package fileserver
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChangeBatchKeepsOneChangePerPath(t *testing.T) {
+	b := &changeBatch{}
+	for _, c := range []ChangeEvent{
+		{Type: "created", Path: "/a.txt"},
+		{Type: "modified", Path: "/b.txt"},
+		{Type: "modified", Path: "/a.txt"},
+		{Type: "removed", Path: "/c.txt"},
+		{Type: "removed", Path: "/b.txt"},
+	} {
+		b.add(c)
+	}
+
+	got := b.take()
+	want := []ChangeEvent{
+		{Type: "created", Path: "/a.txt"},
+		{Type: "removed", Path: "/b.txt"},
+		{Type: "removed", Path: "/c.txt"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("take() = %+v, want %+v", got, want)
+	}
+	if rest := b.take(); len(rest) != 0 {
+		t.Errorf("second take() = %+v, want nothing", rest)
+	}
+}
+
+func TestWatcherReportsEveryPathOfABurst(t *testing.T) {
+	dir := t.TempDir()
+	fs := newTestFileServer(t, dir, map[string]interface{}{"watch_debounce_ms": 200})
+
+	events := make(chan ChangeEvent, 100)
+	fs.mu.Lock()
+	fs.clients[events] = true
+	fs.mu.Unlock()
+
+	// Give the watcher time to start before changing files
+	time.Sleep(200 * time.Millisecond)
+	names := []string{"one.txt", "two.txt", "three.txt"}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	timeout := time.After(5 * time.Second)
+	for len(seen) < len(names) {
+		select {
+		case event := <-events:
+			seen[event.Path] = true
+		case <-timeout:
+			t.Fatalf("got changes for %v, want one for each of %v", seen, names)
+		}
+	}
+	for _, name := range names {
+		if !seen["/"+name] {
+			t.Errorf("no change reported for %s", name)
+		}
+	}
+}
+
+// waitForClient waits until fs has a connected live update client
+func waitForClient(t *testing.T, fs *FileServer) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for fs.ClientCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the SSE client never connected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSSESendsCreateEventAsJSON(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	fs := newTestFileServer(t, dir, map[string]interface{}{"watch_debounce_ms": 0})
+	server := httptest.NewServer(http.HandlerFunc(fs.HandleSSE))
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	waitForClient(t, fs)
+	// Give the watcher time to start before changing files
+	time.Sleep(200 * time.Millisecond)
+	if err := os.WriteFile(filepath.Join(dir, "docs", "new.txt"), []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	timeout := time.After(5 * time.Second)
+	var eventName string
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatal("the stream ended before the create event")
+			}
+			if name, ok := strings.CutPrefix(line, "event: "); ok {
+				eventName = name
+				continue
+			}
+			data, ok := strings.CutPrefix(line, "data: ")
+			if !ok || eventName != "created" {
+				continue
+			}
+			var event ChangeEvent
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				t.Fatalf("data %q is not JSON: %v", data, err)
+			}
+			if event.Type != "created" || event.Path != "/docs/new.txt" || event.Name != "new.txt" || event.Time.IsZero() {
+				t.Errorf("event = %+v, want created /docs/new.txt", event)
+			}
+			return
+		case <-timeout:
+			t.Fatal("no create event was sent")
+		}
+	}
+}
+
+// subscribe registers a channel that receives the changes fs broadcasts
+func subscribe(fs *FileServer) chan ChangeEvent {
+	events := make(chan ChangeEvent, 100)
+	fs.mu.Lock()
+	fs.clients[events] = true
+	fs.mu.Unlock()
+	return events
+}
+
+// waitForChange returns the first change broadcast on events for path, failing the
+// test if none arrives within timeout. Changes to other paths are returned in skipped.
+func waitForChange(t *testing.T, events chan ChangeEvent, path string, timeout time.Duration) (event ChangeEvent, skipped []ChangeEvent) {
+	t.Helper()
+	deadline := time.After(timeout)
+	for {
+		select {
+		case event := <-events:
+			if event.Path == path {
+				return event, skipped
+			}
+			skipped = append(skipped, event)
+		case <-deadline:
+			t.Fatalf("no change reported for %s within %s", path, timeout)
+		}
+	}
+}
+
+func TestWatcherSkipsIgnoredPaths(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "node_modules", "pkg"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	fs := newTestFileServer(t, dir, map[string]interface{}{"watch_debounce_ms": 0})
+	events := subscribe(fs)
+
+	// Give the watcher time to start before changing files
+	time.Sleep(200 * time.Millisecond)
+	for _, name := range []string{"node_modules/pkg/index.js", "node_modules/a.js", "draft.tmp", "visible.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The last change arrives after the ignored ones would have
+	_, skipped := waitForChange(t, events, "/visible.txt", 5*time.Second)
+	if len(skipped) != 0 {
+		t.Errorf("changes to ignored paths were broadcast: %+v", skipped)
+	}
+}
+
+func TestPollerDetectsNewFile(t *testing.T) {
+	dir := t.TempDir()
+	fs := newTestFileServer(t, dir, map[string]interface{}{"watch_poll": true})
+	events := subscribe(fs)
+
+	// Let the poller take its first scan before creating the file
+	time.Sleep(200 * time.Millisecond)
+	if err := os.WriteFile(filepath.Join(dir, "polled.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	event, _ := waitForChange(t, events, "/polled.txt", pollInterval+time.Second)
+	if event.Type != "created" || event.Name != "polled.txt" {
+		t.Errorf("event = %+v, want polled.txt created", event)
+	}
+}
+
+func TestZeroDebounceForwardsImmediately(t *testing.T) {
+	dir := t.TempDir()
+	fs := newTestFileServer(t, dir, map[string]interface{}{"watch_debounce_ms": 0})
+	events := subscribe(fs)
+
+	// Give the watcher time to start before changing files
+	time.Sleep(200 * time.Millisecond)
+	start := time.Now()
+	if err := os.WriteFile(filepath.Join(dir, "now.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Well inside the default 500ms debounce
+	waitForChange(t, events, "/now.txt", 5*time.Second)
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("change reported after %s, want it forwarded immediately", elapsed)
+	}
+}
+
+// sseEvent is a change event read from an SSE stream
+type sseEvent struct {
+	id, name, path string
+}
+
+// openEvents connects to the SSE endpoint at url, resuming after lastEventID unless it
+// is empty, and returns the change events received
+func openEvents(t *testing.T, url, lastEventID string) <-chan sseEvent {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	events := make(chan sseEvent, 200)
+	go func() {
+		var current sseEvent
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				current.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				current.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: ") && current.name != "":
+				var change ChangeEvent
+				json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &change)
+				current.path = change.Path
+				events <- current
+			case line == "":
+				current = sseEvent{}
+			}
+		}
+	}()
+	return events
+}
+
+// nextEvent returns the next event from events, failing the test if none arrives soon
+func nextEvent(t *testing.T, events <-chan sseEvent) sseEvent {
+	t.Helper()
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(3 * time.Second):
+		t.Fatal("no event received")
+		return sseEvent{}
+	}
+}
+
+func TestSSEReplaysMissedEvents(t *testing.T) {
+	fs := newTestFileServer(t, t.TempDir(), nil)
+	server := httptest.NewServer(http.HandlerFunc(fs.HandleSSE))
+	t.Cleanup(server.Close)
+
+	first := openEvents(t, server.URL, "")
+	waitForClient(t, fs)
+	for _, path := range []string{"/a.txt", "/b.txt", "/c.txt"} {
+		fs.BroadcastChange(ChangeEvent{Type: "created", Path: path, Time: time.Now()})
+	}
+	var ids []string
+	for range 3 {
+		ids = append(ids, nextEvent(t, first).id)
+	}
+	if ids[0] == "" || ids[0] == ids[1] || ids[1] == ids[2] {
+		t.Fatalf("event ids = %v, want distinct increasing ids", ids)
+	}
+
+	// Reconnecting after the first event replays the two that followed it, in order
+	resumed := openEvents(t, server.URL, ids[0])
+	for i, want := range []string{"/b.txt", "/c.txt"} {
+		if e := nextEvent(t, resumed); e.path != want || e.id != ids[i+1] || e.name != "created" {
+			t.Errorf("replayed %+v, want %s with id %s", e, want, ids[i+1])
+		}
+	}
+
+	// An up to date client gets only new events
+	current := openEvents(t, server.URL, ids[2])
+	for deadline := time.Now().Add(5 * time.Second); fs.ClientCount() < 3; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the SSE client never connected")
+		}
+	}
+	fs.BroadcastChange(ChangeEvent{Type: "removed", Path: "/a.txt", Time: time.Now()})
+	if e := nextEvent(t, current); e.path != "/a.txt" || e.name != "removed" {
+		t.Errorf("up to date client received %+v first, want the new removal", e)
+	}
+
+	// Ids the history no longer covers ask the client to reload instead
+	if e := nextEvent(t, openEvents(t, server.URL, "999")); e.name != "resync" {
+		t.Errorf("Last-Event-ID ahead of the server: got %+v, want a resync", e)
+	}
+	for range eventHistorySize {
+		fs.BroadcastChange(ChangeEvent{Type: "modified", Path: "/b.txt", Time: time.Now()})
+	}
+	if e := nextEvent(t, openEvents(t, server.URL, ids[0])); e.name != "resync" {
+		t.Errorf("Last-Event-ID older than the history: got %+v, want a resync", e)
+	}
+}
@@ -0,0 +1,72 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"simple.http.server/internal/config"
+)
+
+// Handler answers liveness (/healthz) and readiness (/readyz) checks
+type Handler struct {
+	config  *config.Config
+	version string
+	started time.Time
+}
+
+// NewHandler creates a new health check handler reporting the given version
+func NewHandler(cfg *config.Config, version string) *Handler {
+	return &Handler{
+		config:  cfg,
+		version: version,
+		started: time.Now(),
+	}
+}
+
+// ServeHTTP reports that the server is running; /readyz also checks that the
+// served directory can be read and replies 503 when it cannot
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := http.StatusOK
+	response := map[string]interface{}{
+		"status":  "ok",
+		"uptime":  time.Since(h.started).Round(time.Second).String(),
+		"version": h.version,
+	}
+
+	if r.URL.Path == "/readyz" {
+		if err := checkDir(h.config.GetFileServerDir()); err != nil {
+			status = http.StatusServiceUnavailable
+			response["status"] = "unavailable"
+			response["error"] = "served directory is not accessible: " + err.Error()
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// checkDir returns an error unless dir is a directory that can be opened and read
+func checkDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Reading one entry proves the directory is listable; io.EOF means it is empty
+	if _, err := f.Readdirnames(1); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"simple.http.server/internal/config"
+)
+
+// newTestHandler returns a health handler for a temporary served directory
+func newTestHandler(t *testing.T) (*Handler, string) {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "served")
+	if err := os.Mkdir(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := json.Marshal(map[string]interface{}{"file_server_dir": root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(cfg, "1.2.3"), root
+}
+
+// check sends a request for path to h and returns the status and decoded body
+func check(t *testing.T, h *Handler, method, path string) (int, map[string]interface{}) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	var body map[string]interface{}
+	if rec.Code != http.StatusMethodNotAllowed {
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: decoding: %v", path, err)
+		}
+	}
+	return rec.Code, body
+}
+
+func TestHealthAndReady(t *testing.T) {
+	h, _ := newTestHandler(t)
+	for _, path := range []string{"/healthz", "/readyz"} {
+		code, body := check(t, h, http.MethodGet, path)
+		if code != http.StatusOK {
+			t.Errorf("%s: status = %d, want 200", path, code)
+		}
+		if body["status"] != "ok" || body["version"] != "1.2.3" || body["uptime"] == nil {
+			t.Errorf("%s: body = %v, want status ok, the version and an uptime", path, body)
+		}
+	}
+	if code, _ := check(t, h, http.MethodPost, "/healthz"); code != http.StatusMethodNotAllowed {
+		t.Errorf("POST: status = %d, want 405", code)
+	}
+}
+
+func TestReadyFailsWithoutServedDirectory(t *testing.T) {
+	h, root := newTestHandler(t)
+	if err := os.Remove(root); err != nil {
+		t.Fatal(err)
+	}
+
+	code, body := check(t, h, http.MethodGet, "/readyz")
+	if code != http.StatusServiceUnavailable || body["status"] != "unavailable" || body["error"] == nil {
+		t.Errorf("/readyz: status = %d, body = %v, want 503 with an error", code, body)
+	}
+	// Liveness doesn't depend on the directory
+	if code, _ := check(t, h, http.MethodGet, "/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz: status = %d, want 200", code)
+	}
+}
@@ -0,0 +1,39 @@
+package icon
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+//go:embed static/*
+var staticFiles embed.FS
+
+// Paths are the URL paths of the embedded files
+var Paths = []string{
+	"/favicon.ico",
+	"/apple-touch-icon.png",
+	"/icon-192.png",
+	"/icon-512.png",
+	"/manifest.webmanifest",
+}
+
+// GetHandler returns a handler for the embedded favicon, app icons and web app manifest.
+// Browsers request them from the site root, so they are served at Paths.
+func GetHandler() http.Handler {
+	// Get the static subdirectory
+	fsys, err := fs.Sub(staticFiles, "static")
+	if err != nil {
+		panic(err)
+	}
+
+	files := http.FileServer(http.FS(fsys))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Go does not know the manifest's extension and would send it as plain text
+		if strings.HasSuffix(r.URL.Path, ".webmanifest") {
+			w.Header().Set("Content-Type", "application/manifest+json")
+		}
+		files.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,55 @@
+package icon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServesIcons(t *testing.T) {
+	h := GetHandler()
+	for _, path := range Paths {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK || rec.Body.Len() == 0 {
+			t.Errorf("%s: status = %d with %d bytes", path, rec.Code, rec.Body.Len())
+			continue
+		}
+		contentType := rec.Header().Get("Content-Type")
+		if strings.HasSuffix(path, ".webmanifest") {
+			if contentType != "application/manifest+json" {
+				t.Errorf("%s: Content-Type = %q, want application/manifest+json", path, contentType)
+			}
+			var manifest struct {
+				Name  string `json:"name"`
+				Icons []struct {
+					Src string `json:"src"`
+				} `json:"icons"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &manifest); err != nil || manifest.Name == "" || len(manifest.Icons) == 0 {
+				t.Errorf("%s: manifest = %+v, %v, want a name and icons", path, manifest, err)
+			}
+			for _, icon := range manifest.Icons {
+				if !contains(Paths, icon.Src) {
+					t.Errorf("manifest icon %s is not served", icon.Src)
+				}
+			}
+			continue
+		}
+		if !strings.HasPrefix(contentType, "image/") {
+			t.Errorf("%s: Content-Type = %q, want an image", path, contentType)
+		}
+	}
+}
+
+// contains reports whether list holds s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
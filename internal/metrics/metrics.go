@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"simple.http.server/internal/netutil"
+)
+
+// statusClasses are the response classes always reported, so their series exist from the start
+var statusClasses = []string{"1xx", "2xx", "3xx", "4xx", "5xx"}
+
+// ClientCounter reports how many live update clients are connected
+type ClientCounter interface {
+	ClientCount() int
+}
+
+// Metrics counts what the server does and serves the counts in the Prometheus text format.
+// Its methods may be called on a nil *Metrics, which records nothing.
+type Metrics struct {
+	mu            sync.Mutex
+	requests      map[string]int64 // by status class, e.g. "2xx"
+	bytes         int64
+	uploads       int64
+	archives      int64
+	proxyRequests map[string]int64 // by proxy rule ID
+	clients       ClientCounter    // optional, see SetClientCounter
+}
+
+// New creates an empty set of metrics
+func New() *Metrics {
+	return &Metrics{
+		requests:      make(map[string]int64),
+		proxyRequests: make(map[string]int64),
+	}
+}
+
+// SetClientCounter sets where the number of connected live update clients is read from
+func (m *Metrics) SetClientCounter(c ClientCounter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clients = c
+}
+
+// UploadCompleted counts a file saved by an upload
+func (m *Metrics) UploadCompleted() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.uploads++
+}
+
+// ArchiveCreated counts a zip archive sent in full
+func (m *Metrics) ArchiveCreated() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.archives++
+}
+
+// ProxyRequest counts a request sent through the proxy rule with the given ID
+func (m *Metrics) ProxyRequest(ruleID string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.proxyRequests[ruleID]++
+}
+
+// Wrap returns a handler that counts every request by status class, and the bytes sent
+func (m *Metrics) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := netutil.NewStatusRecorder(w)
+		next.ServeHTTP(rec, r)
+
+		m.mu.Lock()
+		m.requests[fmt.Sprintf("%dxx", rec.Status()/100)]++
+		m.bytes += rec.Bytes()
+		m.mu.Unlock()
+	})
+}
+
+// ServeHTTP writes the current values in the Prometheus text exposition format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	m.write(w)
+}
+
+// write formats every metric, reading the client count outside the lock
+func (m *Metrics) write(w io.Writer) {
+	m.mu.Lock()
+	requests := make(map[string]int64, len(m.requests))
+	for class, n := range m.requests {
+		requests[class] = n
+	}
+	proxyRequests := make(map[string]int64, len(m.proxyRequests))
+	for id, n := range m.proxyRequests {
+		proxyRequests[id] = n
+	}
+	bytes, uploads, archives, clients := m.bytes, m.uploads, m.archives, m.clients
+	m.mu.Unlock()
+
+	header(w, "simple_http_requests_total", "counter", "HTTP requests served, by status class.")
+	for _, class := range statusClasses {
+		fmt.Fprintf(w, "simple_http_requests_total{class=%q} %d\n", class, requests[class])
+	}
+
+	header(w, "simple_http_response_bytes_total", "counter", "Response body bytes sent.")
+	fmt.Fprintf(w, "simple_http_response_bytes_total %d\n", bytes)
+
+	header(w, "simple_http_uploads_total", "counter", "Files saved by uploads.")
+	fmt.Fprintf(w, "simple_http_uploads_total %d\n", uploads)
+
+	header(w, "simple_http_archives_total", "counter", "Zip archives sent in full.")
+	fmt.Fprintf(w, "simple_http_archives_total %d\n", archives)
+
+	sseClients := 0
+	if clients != nil {
+		sseClients = clients.ClientCount()
+	}
+	header(w, "simple_http_sse_clients", "gauge", "Connected live update (SSE) clients.")
+	fmt.Fprintf(w, "simple_http_sse_clients %d\n", sseClients)
+
+	ids := make([]string, 0, len(proxyRequests))
+	for id := range proxyRequests {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	header(w, "simple_http_proxy_requests_total", "counter", "Requests sent through each proxy rule.")
+	for _, id := range ids {
+		fmt.Fprintf(w, "simple_http_proxy_requests_total{rule=\"%s\"} %d\n", labelEscaper.Replace(id), proxyRequests[id])
+	}
+}
+
+// header writes the HELP and TYPE lines that precede a metric
+func header(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// labelEscaper escapes a label value as the text format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fixedClients is a ClientCounter reporting a set number of clients
+type fixedClients int
+
+func (c fixedClients) ClientCount() int { return int(c) }
+
+// scrape returns the text served by m
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Fatalf("status = %d, Content-Type = %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	return rec.Body.String()
+}
+
+func TestMetricsExposition(t *testing.T) {
+	m := New()
+	m.SetClientCounter(fixedClients(2))
+	h := m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		io.WriteString(w, "0123456789")
+	}))
+	for _, path := range []string{"/a", "/b", "/missing"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	m.UploadCompleted()
+	m.UploadCompleted()
+	m.ArchiveCreated()
+	m.ProxyRequest("api")
+	m.ProxyRequest("api")
+	m.ProxyRequest(`we"ird`)
+
+	body := scrape(t, m)
+	for _, want := range []string{
+		"# TYPE simple_http_requests_total counter",
+		`simple_http_requests_total{class="2xx"} 2`,
+		`simple_http_requests_total{class="4xx"} 1`,
+		`simple_http_requests_total{class="5xx"} 0`,
+		"simple_http_response_bytes_total 20",
+		"simple_http_uploads_total 2",
+		"simple_http_archives_total 1",
+		"# TYPE simple_http_sse_clients gauge",
+		"simple_http_sse_clients 2",
+		`simple_http_proxy_requests_total{rule="api"} 2`,
+		`simple_http_proxy_requests_total{rule="we\"ird"} 1`,
+	} {
+		if !strings.Contains(body, want+"\n") {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestNilMetricsRecordNothing(t *testing.T) {
+	var m *Metrics
+	m.UploadCompleted()
+	m.ArchiveCreated()
+	m.ProxyRequest("api")
+}
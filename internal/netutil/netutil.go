@@ -0,0 +1,47 @@
+package netutil
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// LocalIP returns the first non-loopback IPv4 address of the machine,
+// or an empty string if none can be detected
+func LocalIP() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return ""
+	}
+
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok && !ipnet.IP.IsLoopback() {
+			if ipnet.IP.To4() != nil {
+				return ipnet.IP.String()
+			}
+		}
+	}
+
+	return ""
+}
+
+// ClientIP returns the IP address of the client that sent r. X-Forwarded-For is only
+// trusted when the request comes from a loopback address, i.e. a reverse proxy on this
+// machine, since any other client could set it to evade per-client limits.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			// The first address is the original client
+			first := strings.TrimSpace(strings.Split(forwarded, ",")[0])
+			if net.ParseIP(first) != nil {
+				return first
+			}
+		}
+	}
+	return host
+}
@@ -0,0 +1,56 @@
+package netutil
+
+import "net/http"
+
+// StatusRecorder captures the status code and body size written to a ResponseWriter
+type StatusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// NewStatusRecorder wraps w
+func NewStatusRecorder(w http.ResponseWriter) *StatusRecorder {
+	return &StatusRecorder{ResponseWriter: w}
+}
+
+// Status returns the status code sent, which is 200 if the handler wrote none
+func (s *StatusRecorder) Status() int {
+	if s.status == 0 {
+		return http.StatusOK
+	}
+	return s.status
+}
+
+// Bytes returns the number of body bytes written
+func (s *StatusRecorder) Bytes() int64 {
+	return s.bytes
+}
+
+func (s *StatusRecorder) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *StatusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += int64(n)
+	return n, err
+}
+
+// Flush passes flushes through, as Server-Sent Event handlers check for http.Flusher directly
+func (s *StatusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer for hijacking
+func (s *StatusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
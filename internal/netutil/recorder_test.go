@@ -0,0 +1,43 @@
+package netutil
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatusRecorder(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+		wantBytes  int64
+	}{
+		{"nothing written", func(w http.ResponseWriter, r *http.Request) {}, http.StatusOK, 0},
+		{"body only", func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, "hello") }, http.StatusOK, 5},
+		{"error", func(w http.ResponseWriter, r *http.Request) { http.Error(w, "gone", http.StatusNotFound) }, http.StatusNotFound, 5},
+		{"first status wins", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			w.WriteHeader(http.StatusInternalServerError)
+		}, http.StatusCreated, 0},
+	}
+	for _, tt := range tests {
+		rec := NewStatusRecorder(httptest.NewRecorder())
+		tt.handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Status() != tt.wantStatus || rec.Bytes() != tt.wantBytes {
+			t.Errorf("%s: got status %d, %d bytes; want %d, %d bytes", tt.name, rec.Status(), rec.Bytes(), tt.wantStatus, tt.wantBytes)
+		}
+	}
+}
+
+func TestStatusRecorderFlushes(t *testing.T) {
+	inner := httptest.NewRecorder()
+	rec := NewStatusRecorder(inner)
+	if err := http.NewResponseController(rec).Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if !inner.Flushed {
+		t.Error("flush did not reach the underlying writer")
+	}
+}
@@ -0,0 +1,82 @@
+package pathutil
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+// ErrOutsideRoot is returned when a requested path escapes the root directory
+var ErrOutsideRoot = errors.New("path is outside the served directory")
+
+// IsWithin reports whether path is root itself or located inside root. Symlinks are
+// followed, so a link inside root that points outside it is not within root.
+// Both arguments are expected to be absolute, cleaned paths; path need not exist yet.
+func IsWithin(root, path string) bool {
+	return isLexicallyWithin(root, path) && isLexicallyWithin(evalExisting(root), evalExisting(path))
+}
+
+// isLexicallyWithin compares the paths as written, without touching the file system
+func isLexicallyWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// evalExisting resolves the symlinks in the longest existing part of path. The rest, such
+// as the name of a file about to be created, cannot be a link and is appended unchanged.
+func evalExisting(path string) string {
+	var missing []string
+	for {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			return filepath.Join(append([]string{resolved}, missing...)...)
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return filepath.Join(append([]string{path}, missing...)...)
+		}
+		missing = append([]string{filepath.Base(path)}, missing...)
+		path = parent
+	}
+}
+
+// Resolve joins reqPath onto root and returns the absolute root and target paths.
+// It returns ErrOutsideRoot if the target does not lie within root.
+func Resolve(root, reqPath string) (absRoot, absPath string, err error) {
+	absRoot, err = filepath.Abs(root)
+	if err != nil {
+		return "", "", err
+	}
+
+	absPath, err = filepath.Abs(filepath.Join(absRoot, filepath.Clean(reqPath)))
+	if err != nil {
+		return "", "", err
+	}
+
+	if !IsWithin(absRoot, absPath) {
+		return "", "", ErrOutsideRoot
+	}
+	return absRoot, absPath, nil
+}
+
+// MatchesAny reports whether rel, a slash-separated relative path, matches one of the glob patterns.
+// Patterns without a slash match any single path element, so ".git" matches every
+// .git directory and everything below it; patterns with a slash match the whole path.
+func MatchesAny(rel string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.Contains(pattern, "/") {
+			if ok, _ := filepath.Match(pattern, rel); ok {
+				return true
+			}
+			continue
+		}
+		for _, elem := range strings.Split(rel, "/") {
+			if ok, _ := filepath.Match(pattern, elem); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package pathutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsWithin(t *testing.T) {
+	tests := []struct {
+		root, path string
+		want       bool
+	}{
+		{"/srv/data", "/srv/data", true},
+		{"/srv/data", "/srv/data/a/b.txt", true},
+		{"/srv/data", "/srv/data2", false},
+		{"/srv/data", "/srv/data2/secret", false},
+		{"/srv/data", "/srv", false},
+		{"/srv/data", "/etc/passwd", false},
+		{"/srv/data", "/srv/data/..file", true},
+	}
+	for _, tt := range tests {
+		if got := IsWithin(filepath.FromSlash(tt.root), filepath.FromSlash(tt.path)); got != tt.want {
+			t.Errorf("IsWithin(%q, %q) = %v, want %v", tt.root, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	root := t.TempDir()
+	tests := []struct {
+		reqPath string
+		wantErr error
+	}{
+		{"/", nil},
+		{"docs/a.txt", nil},
+		{"/docs/../a.txt", nil},
+		{"../secret", ErrOutsideRoot},
+		{"../../etc/passwd", ErrOutsideRoot},
+		{"/../../etc/passwd", nil}, // a rooted path cleans to /etc/passwd under root
+	}
+	for _, tt := range tests {
+		_, abs, err := Resolve(root, tt.reqPath)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Resolve(%q) error = %v, want %v", tt.reqPath, err, tt.wantErr)
+			continue
+		}
+		if err == nil && !IsWithin(root, abs) {
+			t.Errorf("Resolve(%q) = %q, outside %q", tt.reqPath, abs, root)
+		}
+	}
+}
+
+func TestIsWithinSymlinks(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	outside := filepath.Join(base, "outside")
+	for _, dir := range []string{filepath.Join(root, "sub"), outside} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(root, "sub"), filepath.Join(root, "inner")); err != nil {
+		t.Fatal(err)
+	}
+	linkedRoot := filepath.Join(base, "linked")
+	if err := os.Symlink(root, linkedRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		root, path string
+		want       bool
+	}{
+		{"link leaving root", root, filepath.Join(root, "escape"), false},
+		{"file behind link leaving root", root, filepath.Join(root, "escape", "secret.txt"), false},
+		{"new file behind link leaving root", root, filepath.Join(root, "escape", "new.txt"), false},
+		{"link staying in root", root, filepath.Join(root, "inner"), true},
+		{"new file in root", root, filepath.Join(root, "sub", "new", "file.txt"), true},
+		{"root reached through a link", linkedRoot, filepath.Join(linkedRoot, "sub"), true},
+		{"link leaving a linked root", linkedRoot, filepath.Join(linkedRoot, "escape", "secret.txt"), false},
+	}
+	for _, tt := range tests {
+		if got := IsWithin(tt.root, tt.path); got != tt.want {
+			t.Errorf("%s: IsWithin(%q, %q) = %v, want %v", tt.name, tt.root, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package preview
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strings"
+
+	"github.com/rwcarlsen/goexif/exif"
+)
+
+// metaField is a labelled value shown in the image info panel
+type metaField struct {
+	Label string
+	Value string
+}
+
+// imageDimensions returns the pixel size of formats the standard library can decode
+func imageDimensions(filePath string) (width, height int, ok bool) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, 0, false
+	}
+	defer file.Close()
+
+	cfg, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return 0, 0, false
+	}
+	return cfg.Width, cfg.Height, true
+}
+
+// readEXIF extracts capture details from JPEG and TIFF files.
+// Formats without EXIF data, or files that fail to parse, return nil.
+func readEXIF(filePath, ext string) []metaField {
+	switch ext {
+	case ".jpg", ".jpeg", ".tif", ".tiff":
+	default:
+		return nil
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	x, err := exif.Decode(file)
+	if err != nil {
+		return nil
+	}
+
+	var fields []metaField
+	add := func(label, value string) {
+		if value != "" {
+			fields = append(fields, metaField{Label: label, Value: value})
+		}
+	}
+
+	camera := strings.TrimSpace(exifString(x, exif.Make) + " " + exifString(x, exif.Model))
+	add("Camera", camera)
+	add("Exposure", exifExposure(x))
+	if f, ok := exifFloat(x, exif.FNumber); ok {
+		add("Aperture", fmt.Sprintf("f/%.1f", f))
+	}
+	if iso, err := x.Get(exif.ISOSpeedRatings); err == nil {
+		if v, err := iso.Int(0); err == nil {
+			add("ISO", fmt.Sprintf("%d", v))
+		}
+	}
+	if f, ok := exifFloat(x, exif.FocalLength); ok {
+		add("Focal length", fmt.Sprintf("%g mm", f))
+	}
+	if t, err := x.DateTime(); err == nil {
+		add("Captured", t.Format("2006-01-02 15:04:05"))
+	}
+	if lat, long, err := x.LatLong(); err == nil {
+		add("GPS", fmt.Sprintf("%.6f, %.6f", lat, long))
+	}
+
+	return fields
+}
+
+// exifString returns a string tag value, or "" if it is missing
+func exifString(x *exif.Exif, name exif.FieldName) string {
+	tag, err := x.Get(name)
+	if err != nil {
+		return ""
+	}
+	v, err := tag.StringVal()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(strings.TrimSpace(v), "\x00")
+}
+
+// exifFloat returns a rational tag value as a float
+func exifFloat(x *exif.Exif, name exif.FieldName) (float64, bool) {
+	tag, err := x.Get(name)
+	if err != nil {
+		return 0, false
+	}
+	r, err := tag.Rat(0)
+	if err != nil {
+		return 0, false
+	}
+	f, _ := r.Float64()
+	return f, true
+}
+
+// exifExposure formats the exposure time as a fraction of a second where appropriate
+func exifExposure(x *exif.Exif) string {
+	tag, err := x.Get(exif.ExposureTime)
+	if err != nil {
+		return ""
+	}
+	r, err := tag.Rat(0)
+	if err != nil || r.Sign() <= 0 {
+		return ""
+	}
+
+	f, _ := r.Float64()
+	if f >= 1 {
+		return fmt.Sprintf("%g s", f)
+	}
+	return fmt.Sprintf("1/%.0f s", 1/f)
+}
@@ -0,0 +1,139 @@
+package preview
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// ifdEntry is a tag of a TIFF image file directory, with its value already encoded
+type ifdEntry struct {
+	tag, typ uint16
+	count    uint32
+	value    []byte
+}
+
+const (
+	tiffShort    = 3
+	tiffASCII    = 2
+	tiffLong     = 4
+	tiffRational = 5
+)
+
+func asciiEntry(tag uint16, s string) ifdEntry {
+	return ifdEntry{tag, tiffASCII, uint32(len(s) + 1), append([]byte(s), 0)}
+}
+
+func rationalEntry(tag uint16, num, den uint32) ifdEntry {
+	return ifdEntry{tag, tiffRational, 1, binary.LittleEndian.AppendUint32(binary.LittleEndian.AppendUint32(nil, num), den)}
+}
+
+// encodeIFD encodes entries as a directory starting at offset in the TIFF data,
+// followed by the values too long to fit in their entries
+func encodeIFD(offset uint32, entries []ifdEntry) []byte {
+	le := binary.LittleEndian
+	dataOffset := offset + 2 + uint32(len(entries))*12 + 4
+	var dir, data []byte
+	dir = le.AppendUint16(dir, uint16(len(entries)))
+	for _, e := range entries {
+		dir = le.AppendUint16(dir, e.tag)
+		dir = le.AppendUint16(dir, e.typ)
+		dir = le.AppendUint32(dir, e.count)
+		if len(e.value) <= 4 {
+			dir = append(dir, append(e.value, make([]byte, 4-len(e.value))...)...)
+			continue
+		}
+		dir = le.AppendUint32(dir, dataOffset+uint32(len(data)))
+		data = append(data, e.value...)
+		if len(data)%2 == 1 {
+			data = append(data, 0)
+		}
+	}
+	dir = le.AppendUint32(dir, 0) // no next directory
+	return append(dir, data...)
+}
+
+// jpegWithEXIF returns a small JPEG carrying camera, exposure and capture time tags
+func jpegWithEXIF(t *testing.T) []byte {
+	t.Helper()
+	var img bytes.Buffer
+	if err := jpeg.Encode(&img, image.NewGray(image.Rect(0, 0, 2, 1)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	exifIFD := []ifdEntry{
+		rationalEntry(0x829A, 1, 250),                                      // ExposureTime
+		rationalEntry(0x829D, 28, 10),                                      // FNumber
+		{0x8827, tiffShort, 1, binary.LittleEndian.AppendUint16(nil, 200)}, // ISOSpeedRatings
+		asciiEntry(0x9003, "2023:04:05 06:07:08"),                          // DateTimeOriginal
+		rationalEntry(0x920A, 50, 1),                                       // FocalLength
+	}
+	ifd0 := func(exifOffset uint32) []ifdEntry {
+		return []ifdEntry{
+			asciiEntry(0x010F, "Acme"),  // Make
+			asciiEntry(0x0110, "Cam 1"), // Model
+			{0x8769, tiffLong, 1, binary.LittleEndian.AppendUint32(nil, exifOffset)},
+		}
+	}
+	exifOffset := 8 + uint32(len(encodeIFD(8, ifd0(0))))
+
+	tiff := append([]byte("II\x2a\x00\x08\x00\x00\x00"), encodeIFD(8, ifd0(exifOffset))...)
+	tiff = append(tiff, encodeIFD(exifOffset, exifIFD)...)
+	app1 := append([]byte("Exif\x00\x00"), tiff...)
+
+	// The APP1 segment goes right after the start of image marker
+	out := append([]byte{}, img.Bytes()[:2]...)
+	out = append(out, 0xFF, 0xE1)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(app1)+2))
+	out = append(out, app1...)
+	return append(out, img.Bytes()[2:]...)
+}
+
+func TestImagePreviewShowsEXIF(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, root, "photo.jpg", string(jpegWithEXIF(t)))
+
+	body := get(h, "/api/preview?path=photo.jpg").Body.String()
+	for _, want := range []string{
+		"<p>Dimensions: 2 × 1</p>",
+		"<tr><th>Camera</th><td>Acme Cam 1</td></tr>",
+		"<tr><th>Exposure</th><td>1/250 s</td></tr>",
+		"<tr><th>Aperture</th><td>f/2.8</td></tr>",
+		"<tr><th>ISO</th><td>200</td></tr>",
+		"<tr><th>Focal length</th><td>50 mm</td></tr>",
+		"<tr><th>Captured</th><td>2023-04-05 06:07:08</td></tr>",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("preview has no %s", want)
+		}
+	}
+}
+
+func TestImagePreviewWithoutEXIF(t *testing.T) {
+	h, root := newTestHandler(t)
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewGray(image.Rect(0, 0, 3, 2))); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, root, "plain.png", img.String())
+	writeFile(t, root, "logo.svg", `<svg xmlns="http://www.w3.org/2000/svg"/>`)
+	writeFile(t, root, "broken.jpg", "not a jpeg")
+
+	for _, name := range []string{"plain.png", "logo.svg", "broken.jpg"} {
+		rec := get(h, "/api/preview?path="+name)
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status = %d", name, rec.Code)
+		}
+		if strings.Contains(rec.Body.String(), `<table class="exif">`) {
+			t.Errorf("%s: preview shows an EXIF table", name)
+		}
+	}
+	if body := get(h, "/api/preview?path=plain.png").Body.String(); !strings.Contains(body, "<p>Dimensions: 3 × 2</p>") {
+		t.Error("plain.png: no dimensions")
+	}
+}
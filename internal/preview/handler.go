@@ -1,13 +1,31 @@
 package preview
 
 import (
+	"bytes"
+	"encoding/csv"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"simple.http.server/internal/config"
+	"simple.http.server/internal/etag"
+	"simple.http.server/internal/format"
+	"simple.http.server/internal/pathutil"
+)
+
+const (
+	// defaultCSVRowLimit is the number of data rows rendered in a CSV preview
+	defaultCSVRowLimit = 1000
+	// maxCSVRowLimit caps the row count a client can ask for
+	maxCSVRowLimit = 10000
+	// defaultPreviewMaxBytes is used when no preview size limit is configured
+	defaultPreviewMaxBytes = 2 << 20 // 2 MB
 )
 
 // Handler manages file preview
@@ -22,16 +40,56 @@ func NewHandler(cfg *config.Config) *Handler {
 
 // ServeHTTP handles preview requests
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/preview/raw" {
+		h.serveRaw(w, r)
+		return
+	}
+
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Get file path
+	// The file may also be named in the URL, as in /api/preview/docs/notes.md
 	filePath := r.URL.Query().Get("path")
+	if filePath == "" && strings.HasPrefix(r.URL.Path, "/api/preview/") {
+		filePath = strings.TrimPrefix(r.URL.Path, "/api/preview")
+	}
+	absBase, absFile, info, ok := h.resolveFile(w, filePath)
+	if !ok {
+		return
+	}
+
+	// Determine file type and serve preview
+	ext := strings.ToLower(filepath.Ext(absFile))
+	nav := siblingNavHTML(absBase, absFile)
+	
+	switch {
+	case isImage(ext):
+		h.serveImagePreview(w, r, absFile, filePath, info, nav)
+	case isVideo(ext):
+		h.serveVideoPreview(w, r, absFile, filePath, nav)
+	case isAudio(ext):
+		h.serveAudioPreview(w, r, absFile, filePath, nav)
+	case isCode(ext):
+		h.serveCodePreview(w, r, absFile, filePath, ext, info, nav)
+	case ext == ".pdf":
+		h.servePDFPreview(w, r, absFile, filePath, nav)
+	case ext == ".csv":
+		h.serveCSVPreview(w, r, absFile, nav)
+	case isText(ext):
+		h.serveTextPreview(w, r, absFile, filePath, info, nav)
+	default:
+		http.Error(w, "Preview not supported for this file type", http.StatusBadRequest)
+	}
+}
+
+// resolveFile checks that a preview path names a file inside the served
+// directory, writing an error response when it does not
+func (h *Handler) resolveFile(w http.ResponseWriter, filePath string) (absBase, absFile string, info os.FileInfo, ok bool) {
 	if filePath == "" {
 		http.Error(w, "Path parameter is required", http.StatusBadRequest)
-		return
+		return "", "", nil, false
 	}
 
 	// Get base directory
@@ -42,57 +100,87 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	absBase, err := filepath.Abs(baseDir)
 	if err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
+		return "", "", nil, false
 	}
 
-	absFile, err := filepath.Abs(fullPath)
+	absFile, err = filepath.Abs(fullPath)
 	if err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
+		return "", "", nil, false
 	}
 
-	if !strings.HasPrefix(absFile, absBase) {
+	if !pathutil.IsWithin(absBase, absFile) {
 		http.Error(w, "Forbidden", http.StatusForbidden)
-		return
+		return "", "", nil, false
 	}
 
 	// Check if file exists
-	info, err := os.Stat(absFile)
+	info, err = os.Stat(absFile)
 	if err != nil {
 		http.Error(w, "File not found", http.StatusNotFound)
-		return
+		return "", "", nil, false
 	}
 
 	if info.IsDir() {
 		http.Error(w, "Cannot preview directory", http.StatusBadRequest)
+		return "", "", nil, false
+	}
+	return absBase, absFile, info, true
+}
+
+// serveRaw streams a file's bytes for the media players on preview pages,
+// honouring Range requests so players can seek
+func (h *Handler) serveRaw(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Determine file type and serve preview
-	ext := strings.ToLower(filepath.Ext(absFile))
-	
-	switch {
-	case isImage(ext):
-		h.serveImagePreview(w, r, absFile, info)
-	case isVideo(ext):
-		h.serveVideoPreview(w, r, absFile, filePath)
-	case isAudio(ext):
-		h.serveAudioPreview(w, r, absFile, filePath)
-	case isCode(ext):
-		h.serveCodePreview(w, r, absFile, ext)
-	case ext == ".pdf":
-		h.servePDFPreview(w, r, absFile, filePath)
-	case isText(ext):
-		h.serveTextPreview(w, r, absFile)
-	default:
-		http.Error(w, "Preview not supported for this file type", http.StatusBadRequest)
+	_, absFile, _, ok := h.resolveFile(w, r.URL.Query().Get("path"))
+	if !ok {
+		return
 	}
+
+	file, err := os.Open(absFile)
+	if err != nil {
+		http.Error(w, "Failed to read file", http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(w, "Failed to read file", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+}
+
+// rawURL returns the absolute URL of the raw streaming endpoint for a preview path
+func rawURL(filePath string) string {
+	return "/api/preview/raw?path=" + url.QueryEscape(filePath)
 }
 
 // serveImagePreview serves image preview HTML
-func (h *Handler) serveImagePreview(w http.ResponseWriter, r *http.Request, filePath string, info os.FileInfo) {
-	fileName := filepath.Base(filePath)
-	fileSize := formatFileSize(info.Size())
+func (h *Handler) serveImagePreview(w http.ResponseWriter, r *http.Request, filePath, urlPath string, info os.FileInfo, nav string) {
+	fileName := escapeHTML(filepath.Base(filePath))
+	fileSize := format.FileSize(info.Size())
+	src := escapeHTML((&url.URL{Path: path.Join("/", urlPath)}).EscapedPath())
+	
+	// Dimensions and EXIF details for the info panel
+	var details strings.Builder
+	if width, height, ok := imageDimensions(filePath); ok {
+		fmt.Fprintf(&details, "<p>Dimensions: %d × %d</p>", width, height)
+	}
+	if fields := readEXIF(filePath, strings.ToLower(filepath.Ext(filePath))); len(fields) > 0 {
+		details.WriteString(`<table class="exif">`)
+		for _, f := range fields {
+			fmt.Fprintf(&details, "<tr><th>%s</th><td>%s</td></tr>", f.Label, escapeHTML(f.Value))
+		}
+		details.WriteString("</table>")
+	}
 	
 	html := fmt.Sprintf(`<!DOCTYPE html>
 <html>
@@ -101,6 +189,9 @@ func (h *Handler) serveImagePreview(w http.ResponseWriter, r *http.Request, file
     <style>
         body { margin: 0; padding: 20px; background: #1a1a1a; color: #fff; font-family: Arial, sans-serif; display: flex; flex-direction: column; align-items: center; }
         .info { margin-bottom: 20px; }
+        .exif { margin: 0 0 16px; font-size: 14px; border-collapse: collapse; }
+        .exif th { text-align: left; color: #999; font-weight: normal; padding: 2px 16px 2px 0; }
+        .exif td { padding: 2px 0; }
         img { max-width: 100%%; max-height: 80vh; box-shadow: 0 4px 6px rgba(0,0,0,0.3); }
         .back-btn { background: #3498db; color: white; padding: 10px 20px; text-decoration: none; border-radius: 4px; }
     </style>
@@ -109,19 +200,20 @@ func (h *Handler) serveImagePreview(w http.ResponseWriter, r *http.Request, file
     <div class="info">
         <h2>📷 %s</h2>
         <p>Size: %s</p>
-        <a href="javascript:history.back()" class="back-btn">← Back</a>
+        %s
+        <span><a href="javascript:history.back()" class="back-btn">← Back</a>%s</span>
     </div>
     <img src="%s" alt="%s">
 </body>
-</html>`, fileName, fileName, fileSize, r.URL.Query().Get("path"), fileName)
+</html>`, fileName, fileName, fileSize, details.String(), nav, src, fileName)
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Write([]byte(html))
 }
 
 // serveVideoPreview serves video preview HTML
-func (h *Handler) serveVideoPreview(w http.ResponseWriter, r *http.Request, filePath, urlPath string) {
-	fileName := filepath.Base(filePath)
+func (h *Handler) serveVideoPreview(w http.ResponseWriter, r *http.Request, filePath, urlPath, nav string) {
+	fileName := escapeHTML(filepath.Base(filePath))
 	
 	html := fmt.Sprintf(`<!DOCTYPE html>
 <html>
@@ -137,22 +229,22 @@ func (h *Handler) serveVideoPreview(w http.ResponseWriter, r *http.Request, file
 <body>
     <div class="info">
         <h2>🎬 %s</h2>
-        <a href="javascript:history.back()" class="back-btn">← Back</a>
+        <span><a href="javascript:history.back()" class="back-btn">← Back</a>%s</span>
     </div>
     <video controls autoplay>
-        <source src="%s" type="video/mp4">
+        %s
         Your browser does not support video playback.
     </video>
 </body>
-</html>`, fileName, fileName, urlPath)
+</html>`, fileName, fileName, nav, mediaSourcesHTML(urlPath, videoTypes[strings.ToLower(filepath.Ext(filePath))]))
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Write([]byte(html))
 }
 
 // serveAudioPreview serves audio preview HTML
-func (h *Handler) serveAudioPreview(w http.ResponseWriter, r *http.Request, filePath, urlPath string) {
-	fileName := filepath.Base(filePath)
+func (h *Handler) serveAudioPreview(w http.ResponseWriter, r *http.Request, filePath, urlPath, nav string) {
+	fileName := escapeHTML(filepath.Base(filePath))
 	
 	html := fmt.Sprintf(`<!DOCTYPE html>
 <html>
@@ -168,62 +260,104 @@ func (h *Handler) serveAudioPreview(w http.ResponseWriter, r *http.Request, file
 <body>
     <div class="info">
         <h2>🎵 %s</h2>
-        <p><a href="javascript:history.back()" class="back-btn">← Back</a></p>
+        <p><span><a href="javascript:history.back()" class="back-btn">← Back</a>%s</span></p>
     </div>
     <audio controls autoplay>
-        <source src="%s">
+        %s
         Your browser does not support audio playback.
     </audio>
 </body>
-</html>`, fileName, fileName, urlPath)
+</html>`, fileName, fileName, nav, mediaSourcesHTML(urlPath, audioTypes[strings.ToLower(filepath.Ext(filePath))]))
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Write([]byte(html))
 }
 
-// serveCodePreview serves code preview with syntax highlighting
-func (h *Handler) serveCodePreview(w http.ResponseWriter, r *http.Request, filePath, ext string) {
-	// Read file content
-	content, err := os.ReadFile(filePath)
+// serveCodePreview serves code preview with syntax highlighting and line numbers
+func (h *Handler) serveCodePreview(w http.ResponseWriter, r *http.Request, filePath, urlPath, ext string, info os.FileInfo, nav string) {
+	if etag.Check(w, r, h.previewETag(r, info)) {
+		return
+	}
+
+	// Read file content, up to the configured preview limit
+	content, banner, err := h.readPreviewContent(filePath, urlPath)
 	if err != nil {
 		http.Error(w, "Failed to read file", http.StatusInternalServerError)
 		return
 	}
 
-	fileName := filepath.Base(filePath)
+	fileName := escapeHTML(filepath.Base(filePath))
 	language := getLanguage(ext)
+	gutter := lineNumbersHTML(content)
+	
+	// Line to highlight on load; a #L<n> fragment takes precedence in the browser
+	line, _ := strconv.Atoi(r.URL.Query().Get("line"))
 	
 	html := fmt.Sprintf(`<!DOCTYPE html>
 <html>
 <head>
     <title>Preview: %s</title>
-    <link rel="stylesheet" href="https://cdnjs.cloudflare.com/ajax/libs/highlight.js/11.9.0/styles/github-dark.min.css">
-    <script src="https://cdnjs.cloudflare.com/ajax/libs/highlight.js/11.9.0/highlight.min.js"></script>
+    <link rel="stylesheet" href="/api/preview/assets/highlight.css">
+    <script src="/api/preview/assets/highlight.min.js"></script>
     <style>
         body { margin: 0; padding: 20px; background: #0d1117; color: #c9d1d9; font-family: Arial, sans-serif; }
         .header { display: flex; justify-content: space-between; align-items: center; margin-bottom: 20px; }
         .back-btn { background: #3498db; color: white; padding: 10px 20px; text-decoration: none; border-radius: 4px; }
-        pre { margin: 0; padding: 20px; background: #161b22; border-radius: 6px; overflow-x: auto; }
-        code { font-family: 'Monaco', 'Menlo', 'Courier New', monospace; font-size: 14px; }
+        .code-wrap { position: relative; display: flex; background: #161b22; border-radius: 6px; }
+        .gutter { padding: 20px 0; text-align: right; user-select: none; border-right: 1px solid #30363d; }
+        .gutter a { display: block; padding: 0 12px; color: #6e7681; text-decoration: none; }
+        .gutter a:hover, .gutter a.active { color: #c9d1d9; }
+        .gutter a, code { font-family: 'Monaco', 'Menlo', 'Courier New', monospace; font-size: 14px; line-height: 20px; }
+        pre { flex: 1; margin: 0; padding: 20px; overflow-x: auto; }
+        code { display: block; background: transparent; }
+        .notice { color: #f2cc60; }
+        .notice a { color: #58a6ff; }
+        #lineHighlight { display: none; position: absolute; left: 0; right: 0; height: 20px; background: rgba(187, 128, 9, 0.15); pointer-events: none; }
     </style>
 </head>
 <body>
     <div class="header">
         <h2>📝 %s</h2>
-        <a href="javascript:history.back()" class="back-btn">← Back</a>
+        <span><a href="javascript:history.back()" class="back-btn">← Back</a>%s</span>
+    </div>
+    %s
+    <div class="code-wrap">
+        <div class="gutter">%s</div>
+        <pre><code class="language-%s">%s</code></pre>
+        <div id="lineHighlight"></div>
     </div>
-    <pre><code class="language-%s">%s</code></pre>
-    <script>hljs.highlightAll();</script>
+    <script>
+        document.querySelectorAll('pre code').forEach(function(el) { hljs.highlightBlock(el); });
+
+        var initialLine = %d;
+        function highlightLine() {
+            var match = location.hash.match(/^#L(\d+)$/);
+            var line = match ? parseInt(match[1], 10) : initialLine;
+            var anchor = document.getElementById('L' + line);
+            var band = document.getElementById('lineHighlight');
+            document.querySelectorAll('.gutter a.active').forEach(function(a) { a.classList.remove('active'); });
+            if (!anchor) {
+                band.style.display = 'none';
+                return;
+            }
+            anchor.classList.add('active');
+            band.style.top = anchor.offsetTop + 'px';
+            band.style.display = 'block';
+            anchor.scrollIntoView({ block: 'center' });
+        }
+        window.addEventListener('hashchange', highlightLine);
+        highlightLine();
+    </script>
 </body>
-</html>`, fileName, fileName, language, escapeHTML(string(content)))
+</html>`, fileName, fileName, nav, banner, gutter, language, escapeHTML(content), line)
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Write([]byte(html))
 }
 
 // servePDFPreview serves PDF preview HTML
-func (h *Handler) servePDFPreview(w http.ResponseWriter, r *http.Request, filePath, urlPath string) {
-	fileName := filepath.Base(filePath)
+func (h *Handler) servePDFPreview(w http.ResponseWriter, r *http.Request, filePath, urlPath, nav string) {
+	fileName := escapeHTML(filepath.Base(filePath))
 	
 	html := fmt.Sprintf(`<!DOCTYPE html>
 <html>
@@ -238,26 +372,152 @@ func (h *Handler) servePDFPreview(w http.ResponseWriter, r *http.Request, filePa
 </head>
 <body>
     <div class="header">
-        <a href="javascript:history.back()" class="back-btn">← Back</a>
+        <span><a href="javascript:history.back()" class="back-btn">← Back</a>%s</span>
         <span style="margin-left: 20px;">📄 %s</span>
     </div>
     <iframe src="%s"></iframe>
 </body>
-</html>`, fileName, fileName, urlPath)
+</html>`, fileName, nav, fileName, escapeHTML((&url.URL{Path: urlPath}).EscapedPath()))
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(html))
+}
+
+// serveCSVPreview renders a CSV file as an HTML table
+func (h *Handler) serveCSVPreview(w http.ResponseWriter, r *http.Request, filePath, nav string) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		http.Error(w, "Failed to read file", http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(w, "Failed to read file", http.StatusInternalServerError)
+		return
+	}
+
+	// Row limit can be raised, up to maxCSVRowLimit, or lowered with ?rows=N
+	rowLimit := defaultCSVRowLimit
+	if n, err := strconv.Atoi(r.URL.Query().Get("rows")); err == nil && n > 0 {
+		rowLimit = min(n, maxCSVRowLimit)
+	}
+
+	// Only the first PreviewMaxBytes are parsed, dropping the row split by the cut
+	maxBytes := h.previewMaxBytes()
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes))
+	if err != nil {
+		http.Error(w, "Failed to read file", http.StatusInternalServerError)
+		return
+	}
+	cut := info.Size() > maxBytes
+	if cut {
+		data = data[:bytes.LastIndexByte(data, '\n')+1]
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1 // allow ragged rows
+	reader.LazyQuotes = true
+
+	var table strings.Builder
+	rows := 0
+	truncated := false
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			http.Error(w, "Failed to parse CSV: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// The first row is the header and does not count towards the limit
+		if rows > rowLimit {
+			truncated = true
+			break
+		}
+
+		cell := "td"
+		if rows == 0 {
+			cell = "th"
+			table.WriteString("<thead>")
+		}
+		table.WriteString("<tr>")
+		for _, field := range record {
+			fmt.Fprintf(&table, "<%s>%s</%s>", cell, escapeHTML(field), cell)
+		}
+		table.WriteString("</tr>")
+		if rows == 0 {
+			table.WriteString("</thead><tbody>")
+		}
+		rows++
+	}
+	if rows > 0 {
+		table.WriteString("</tbody>")
+	}
+
+	notice := ""
+	if truncated {
+		notice = fmt.Sprintf(`<p class="notice">Showing the first %d rows. The file has been truncated.</p>`, rowLimit)
+	} else if cut {
+		notice = fmt.Sprintf(`<p class="notice">Showing the first %d rows, from the first %s of %s. The file has been truncated.</p>`,
+			max(rows-1, 0), format.FileSize(maxBytes), format.FileSize(info.Size()))
+	}
+
+	fileName := escapeHTML(filepath.Base(filePath))
+
+	html := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+    <title>Preview: %s</title>
+    <style>
+        body { margin: 0; padding: 20px; background: #1a1a1a; color: #c9d1d9; font-family: Arial, sans-serif; }
+        .header { display: flex; justify-content: space-between; align-items: center; margin-bottom: 20px; }
+        .back-btn { background: #3498db; color: white; padding: 10px 20px; text-decoration: none; border-radius: 4px; }
+        .table-wrap { overflow-x: auto; background: #0d1117; border-radius: 6px; }
+        table { border-collapse: collapse; font-size: 14px; white-space: nowrap; }
+        th, td { padding: 8px 12px; border: 1px solid #30363d; text-align: left; }
+        th { background: #161b22; position: sticky; top: 0; }
+        tr:nth-child(even) td { background: #111820; }
+        .notice { color: #f2cc60; }
+    </style>
+</head>
+<body>
+    <div class="header">
+        <h2>📊 %s</h2>
+        <span><a href="javascript:history.back()" class="back-btn">← Back</a>%s</span>
+    </div>
+    %s
+    <div class="table-wrap"><table>%s</table></div>
+</body>
+</html>`, fileName, fileName, nav, notice, table.String())
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Write([]byte(html))
 }
 
 // serveTextPreview serves plain text preview
-func (h *Handler) serveTextPreview(w http.ResponseWriter, r *http.Request, filePath string) {
-	content, err := os.ReadFile(filePath)
+func (h *Handler) serveTextPreview(w http.ResponseWriter, r *http.Request, filePath, urlPath string, info os.FileInfo, nav string) {
+	if etag.Check(w, r, h.previewETag(r, info)) {
+		return
+	}
+
+	content, banner, err := h.readPreviewContent(filePath, urlPath)
 	if err != nil {
 		http.Error(w, "Failed to read file", http.StatusInternalServerError)
 		return
 	}
 
-	fileName := filepath.Base(filePath)
+	fileName := escapeHTML(filepath.Base(filePath))
+
+	// Log files can be followed as they grow
+	follow := ""
+	if strings.ToLower(filepath.Ext(filePath)) == ".log" {
+		follow = fmt.Sprintf(`<button class="back-btn follow-btn" data-path="%s" onclick="follow(this)">▶ Follow</button>`,
+			escapeHTML(urlPath))
+	}
 	
 	html := fmt.Sprintf(`<!DOCTYPE html>
 <html>
@@ -267,17 +527,35 @@ func (h *Handler) serveTextPreview(w http.ResponseWriter, r *http.Request, fileP
         body { margin: 0; padding: 20px; background: #1a1a1a; color: #c9d1d9; font-family: Arial, sans-serif; }
         .header { display: flex; justify-content: space-between; align-items: center; margin-bottom: 20px; }
         .back-btn { background: #3498db; color: white; padding: 10px 20px; text-decoration: none; border-radius: 4px; }
+        .follow-btn { border: none; font-size: inherit; cursor: pointer; margin-left: 8px; }
         pre { background: #0d1117; padding: 20px; border-radius: 6px; overflow-x: auto; white-space: pre-wrap; word-wrap: break-word; }
+        .notice { color: #f2cc60; }
+        .notice a { color: #58a6ff; }
     </style>
 </head>
 <body>
     <div class="header">
         <h2>📄 %s</h2>
-        <a href="javascript:history.back()" class="back-btn">← Back</a>
+        <span><a href="javascript:history.back()" class="back-btn">← Back</a>%s%s</span>
     </div>
+    %s
     <pre>%s</pre>
+    <script>
+        // Append lines as they are written to the file, keeping the newest in view
+        function follow(button) {
+            button.remove();
+            const pre = document.querySelector('pre');
+            const source = new EventSource('/api/tail?lines=0&path=' + encodeURIComponent(button.dataset.path));
+            source.addEventListener('line', event => {
+                pre.append(JSON.parse(event.data).text + '\n');
+                window.scrollTo(0, document.body.scrollHeight);
+            });
+            source.addEventListener('reset', () => { pre.textContent = ''; });
+            source.addEventListener('removed', () => source.close());
+        }
+    </script>
 </body>
-</html>`, fileName, fileName, escapeHTML(string(content)))
+</html>`, fileName, fileName, follow, nav, banner, escapeHTML(content))
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Write([]byte(html))
@@ -285,6 +563,128 @@ func (h *Handler) serveTextPreview(w http.ResponseWriter, r *http.Request, fileP
 
 // Helper functions
 
+// previewETag identifies a generated preview by the request query, the preview limit
+// and the file's size and modification time
+func (h *Handler) previewETag(r *http.Request, info os.FileInfo) string {
+	return etag.New(r.URL.RawQuery, strconv.FormatInt(h.config.GetPreviewMaxBytes(), 10),
+		strconv.FormatInt(info.Size(), 10), info.ModTime().String())
+}
+
+// PreferPreview reports whether a file is better opened in the preview than raw: code,
+// CSV and text files, which browsers show unstyled or download. HTML pages are left to
+// the browser so sites still work.
+func PreferPreview(name string) bool {
+	switch ext := strings.ToLower(filepath.Ext(name)); previewCategory(ext) {
+	case "code":
+		return ext != ".html"
+	case "csv", "text":
+		return true
+	}
+	return false
+}
+
+// previewCategory groups extensions so navigation only steps between similar files
+func previewCategory(ext string) string {
+	switch {
+	case isImage(ext):
+		return "image"
+	case isVideo(ext):
+		return "video"
+	case isAudio(ext):
+		return "audio"
+	case isCode(ext):
+		return "code"
+	case ext == ".pdf":
+		return "pdf"
+	case ext == ".csv":
+		return "csv"
+	case isText(ext):
+		return "text"
+	}
+	return ""
+}
+
+// siblingNavHTML renders Prev/Next links to the neighbouring files of the same
+// category in the file's directory, wrapping around at either end
+func siblingNavHTML(absBase, absFile string) string {
+	category := previewCategory(strings.ToLower(filepath.Ext(absFile)))
+	if category == "" {
+		return ""
+	}
+
+	dir := filepath.Dir(absFile)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	var siblings []string
+	current := -1
+	for _, entry := range entries {
+		if entry.IsDir() || previewCategory(strings.ToLower(filepath.Ext(entry.Name()))) != category {
+			continue
+		}
+		if entry.Name() == filepath.Base(absFile) {
+			current = len(siblings)
+		}
+		siblings = append(siblings, entry.Name())
+	}
+	if current < 0 || len(siblings) < 2 {
+		return ""
+	}
+
+	relDir, err := filepath.Rel(absBase, dir)
+	if err != nil {
+		return ""
+	}
+	link := func(name string) string {
+		p := path.Join("/", filepath.ToSlash(relDir), name)
+		return escapeHTML("/api/preview?path=" + url.QueryEscape(p))
+	}
+
+	prev := siblings[(current-1+len(siblings))%len(siblings)]
+	next := siblings[(current+1)%len(siblings)]
+	style := "background: #555; color: white; padding: 10px 16px; text-decoration: none; border-radius: 4px; margin-left: 8px;"
+	return fmt.Sprintf(`<a href="%s" style="%s" title="%s">← Prev</a><a href="%s" style="%s" title="%s">Next →</a>`,
+		link(prev), style, escapeHTML(prev), link(next), style, escapeHTML(next))
+}
+
+// videoTypes maps video extensions to the MIME types offered as <source> elements.
+// Later entries are fallbacks for browsers that reject the first type.
+var videoTypes = map[string][]string{
+	".mp4":  {"video/mp4"},
+	".webm": {"video/webm"},
+	".ogg":  {"video/ogg"},
+	".mov":  {"video/quicktime", "video/mp4"},
+	".avi":  {"video/x-msvideo"},
+	".mkv":  {"video/x-matroska", "video/webm"},
+}
+
+// audioTypes maps audio extensions to the MIME types offered as <source> elements
+var audioTypes = map[string][]string{
+	".mp3":  {"audio/mpeg"},
+	".wav":  {"audio/wav"},
+	".ogg":  {"audio/ogg"},
+	".m4a":  {"audio/mp4"},
+	".flac": {"audio/flac"},
+	".aac":  {"audio/aac"},
+}
+
+// mediaSourcesHTML renders one <source> per MIME type pointing at the raw endpoint, or a single untyped
+// source so the browser can sniff the format when the type is unknown
+func mediaSourcesHTML(filePath string, types []string) string {
+	src := escapeHTML(rawURL(filePath))
+	if len(types) == 0 {
+		return fmt.Sprintf(`<source src="%s">`, src)
+	}
+
+	var b strings.Builder
+	for _, t := range types {
+		fmt.Fprintf(&b, `<source src="%s" type="%s">`, src, t)
+	}
+	return b.String()
+}
+
 func isImage(ext string) bool {
 	images := []string{".jpg", ".jpeg", ".png", ".gif", ".bmp", ".webp", ".svg", ".ico"}
 	for _, img := range images {
@@ -363,6 +763,59 @@ func getLanguage(ext string) string {
 	return "plaintext"
 }
 
+// readPreviewContent reads a text file up to the configured preview limit.
+// When the file is larger, banner holds a notice linking to the full download.
+func (h *Handler) readPreviewContent(filePath, urlPath string) (content, banner string, err error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", "", err
+	}
+
+	maxBytes := h.previewMaxBytes()
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes))
+	if err != nil {
+		return "", "", err
+	}
+
+	if info.Size() > maxBytes {
+		// Drop any multi-byte character split by the cut
+		content = strings.ToValidUTF8(string(data), "")
+		downloadHref := escapeHTML((&url.URL{Path: path.Join("/", urlPath), RawQuery: "download=1"}).String())
+		banner = fmt.Sprintf(`<p class="notice">Showing first %s of %s — <a href="%s">download</a> for full file.</p>`,
+			format.FileSize(maxBytes), format.FileSize(info.Size()), downloadHref)
+		return content, banner, nil
+	}
+	return string(data), "", nil
+}
+
+// previewMaxBytes returns how much of a file previews read
+func (h *Handler) previewMaxBytes() int64 {
+	if maxBytes := h.config.GetPreviewMaxBytes(); maxBytes > 0 {
+		return maxBytes
+	}
+	return defaultPreviewMaxBytes
+}
+
+// lineNumbersHTML renders one linkable line number per line of content
+func lineNumbersHTML(content string) string {
+	lines := strings.Count(content, "\n")
+	if content == "" || !strings.HasSuffix(content, "\n") {
+		lines++
+	}
+
+	var b strings.Builder
+	for i := 1; i <= lines; i++ {
+		fmt.Fprintf(&b, `<a id="L%d" href="#L%d">%d</a>`, i, i, i)
+	}
+	return b.String()
+}
+
 func escapeHTML(s string) string {
 	s = strings.ReplaceAll(s, "&", "&amp;")
 	s = strings.ReplaceAll(s, "<", "&lt;")
@@ -371,16 +824,3 @@ func escapeHTML(s string) string {
 	s = strings.ReplaceAll(s, "'", "&#39;")
 	return s
 }
-
-func formatFileSize(size int64) string {
-	const unit = 1024
-	if size < unit {
-		return fmt.Sprintf("%d B", size)
-	}
-	div, exp := int64(unit), 0
-	for n := size / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
-	}
-	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
-}
@@ -0,0 +1,431 @@
+package preview
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"simple.http.server/internal/config"
+)
+
+// newTestHandler returns a handler previewing a temporary directory holding notes.txt,
+// next to a secret.txt outside it, and the directory's path
+func newTestHandler(t *testing.T) (*Handler, string) {
+	t.Helper()
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	if err := os.Mkdir(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(root, "notes.txt"), []byte("notes"), 0644)
+	os.WriteFile(filepath.Join(base, "secret.txt"), []byte("secret"), 0644)
+
+	settings, err := json.Marshal(map[string]interface{}{"file_server_dir": root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(cfg), root
+}
+
+// get requests target from h and returns the recorded response
+func get(h *Handler, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestPreviewRejectsPathsOutsideRoot(t *testing.T) {
+	h, _ := newTestHandler(t)
+	for _, target := range []string{
+		"/api/preview?path=" + url.QueryEscape("../secret.txt"),
+		"/api/preview?path=" + url.QueryEscape("docs/../../secret.txt"),
+		"/api/preview/raw?path=" + url.QueryEscape("../secret.txt"),
+	} {
+		if rec := get(h, target); rec.Code != http.StatusForbidden {
+			t.Errorf("GET %s: status = %d, want 403", target, rec.Code)
+		}
+	}
+}
+
+func TestPreviewPaths(t *testing.T) {
+	h, _ := newTestHandler(t)
+	tests := []struct {
+		target string
+		want   int
+	}{
+		{"/api/preview?path=notes.txt", http.StatusOK},
+		{"/api/preview?path=/notes.txt", http.StatusOK},
+		{"/api/preview/notes.txt", http.StatusOK},
+		{"/api/preview?path=missing.txt", http.StatusNotFound},
+		{"/api/preview", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if rec := get(h, tt.target); rec.Code != tt.want {
+			t.Errorf("GET %s: status = %d, want %d", tt.target, rec.Code, tt.want)
+		}
+	}
+}
+
+// writeFile creates the file at the slash-separated name under root, and its folders
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCodePreviewUsesLocalAssets(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, root, "main.go", "package main\n")
+
+	body := get(h, "/api/preview?path=main.go").Body.String()
+	for _, want := range []string{
+		`href="/api/preview/assets/highlight.css"`,
+		`src="/api/preview/assets/highlight.min.js"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("code preview does not reference %s", want)
+		}
+	}
+	if strings.Contains(body, "cdnjs") || strings.Contains(body, "https://") {
+		t.Error("code preview still loads assets from a CDN")
+	}
+}
+
+func TestCSVPreview(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, root, "plain.csv", "name,qty\napple,3\npear,5\n")
+	writeFile(t, root, "quoted.csv", "name,note\n\"Smith, John\",\"said \"\"hi\"\"\"\n")
+	var big strings.Builder
+	big.WriteString("n\n")
+	for i := 1; i <= 1005; i++ {
+		fmt.Fprintf(&big, "row%d\n", i)
+	}
+	writeFile(t, root, "big.csv", big.String())
+
+	body := get(h, "/api/preview?path=plain.csv").Body.String()
+	for _, want := range []string{"<th>name</th><th>qty</th>", "<td>apple</td><td>3</td>", "<td>pear</td><td>5</td>"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("plain.csv: table has no %s", want)
+		}
+	}
+	if strings.Contains(body, "truncated") {
+		t.Error("plain.csv: small file marked as truncated")
+	}
+
+	body = get(h, "/api/preview?path=quoted.csv").Body.String()
+	if want := "<td>Smith, John</td><td>said &quot;hi&quot;</td>"; !strings.Contains(body, want) {
+		t.Errorf("quoted.csv: table has no %s", want)
+	}
+
+	body = get(h, "/api/preview?path=big.csv").Body.String()
+	if !strings.Contains(body, "<td>row1000</td>") || strings.Contains(body, "<td>row1001</td>") {
+		t.Error("big.csv: want the first 1000 rows only")
+	}
+	if !strings.Contains(body, "Showing the first 1000 rows. The file has been truncated.") {
+		t.Error("big.csv: no truncated notice")
+	}
+
+	body = get(h, "/api/preview?path=big.csv&rows=2").Body.String()
+	if !strings.Contains(body, "<td>row2</td>") || strings.Contains(body, "<td>row3</td>") {
+		t.Error("big.csv with rows=2: want the first 2 rows only")
+	}
+}
+
+func TestCSVPreviewLimits(t *testing.T) {
+	h, root := newTestHandler(t)
+	var many strings.Builder
+	many.WriteString("n\n")
+	for i := 1; i <= maxCSVRowLimit+5; i++ {
+		fmt.Fprintf(&many, "row%d\n", i)
+	}
+	writeFile(t, root, "many.csv", many.String())
+
+	// Asking for more rows than the cap gets the cap
+	body := get(h, "/api/preview?path=many.csv&rows=1000000000").Body.String()
+	if !strings.Contains(body, fmt.Sprintf("<td>row%d</td>", maxCSVRowLimit)) || strings.Contains(body, fmt.Sprintf("<td>row%d</td>", maxCSVRowLimit+1)) {
+		t.Errorf("many.csv: want the first %d rows only", maxCSVRowLimit)
+	}
+
+	// 3000 rows of 1 KB are more than the 2 MB read, so only whole rows from it are shown
+	var wide strings.Builder
+	wide.WriteString("n,text\n")
+	for i := 1; i <= 3000; i++ {
+		fmt.Fprintf(&wide, "%d,%s\n", i, strings.Repeat("x", 1018))
+	}
+	writeFile(t, root, "wide.csv", wide.String())
+
+	body = get(h, "/api/preview?path=wide.csv&rows=10000").Body.String()
+	if n := strings.Count(body, "<tr>") - 1; n == 0 || n >= 3000 {
+		t.Fatalf("wide.csv: %d rows shown, want those in the first 2 MB", n)
+	}
+	if strings.Contains(body, "<td>3000</td>") {
+		t.Error("wide.csv: rows past the preview limit were parsed")
+	}
+	if strings.Count(body, "<td>"+strings.Repeat("x", 1018)+"</td>") != strings.Count(body, "<tr>")-1 {
+		t.Error("wide.csv: the row split by the limit was shown")
+	}
+	if !strings.Contains(body, "from the first 2.0 MB of 2.9 MB. The file has been truncated.") {
+		t.Error("wide.csv: no truncated notice")
+	}
+}
+
+func TestCodePreviewLineNumbers(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, root, "main.go", "package main\n\nfunc main() {}\n")
+
+	body := get(h, "/api/preview?path=main.go&line=2").Body.String()
+	for i := 1; i <= 3; i++ {
+		if want := fmt.Sprintf(`<a id="L%d" href="#L%d">%d</a>`, i, i, i); !strings.Contains(body, want) {
+			t.Errorf("preview has no markup for line %d", i)
+		}
+	}
+	if strings.Contains(body, `id="L4"`) {
+		t.Error("preview numbers a line past the end")
+	}
+	if !strings.Contains(body, "var initialLine = 2;") {
+		t.Error("?line=2 is not highlighted on load")
+	}
+}
+
+func TestLineNumbersHTML(t *testing.T) {
+	tests := []struct {
+		content string
+		lines   int
+	}{
+		{"", 1},
+		{"one", 1},
+		{"one\n", 1},
+		{"one\ntwo", 2},
+		{"one\ntwo\n\n", 3},
+	}
+	for _, tt := range tests {
+		got := strings.Count(lineNumbersHTML(tt.content), "<a ")
+		if got != tt.lines {
+			t.Errorf("lineNumbersHTML(%q) numbers %d lines, want %d", tt.content, got, tt.lines)
+		}
+	}
+}
+
+func TestVideoPreviewSourceTypes(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, root, "clip.webm", "webm")
+	writeFile(t, root, "clip.mkv", "mkv")
+	writeFile(t, root, "song.mp3", "mp3")
+
+	tests := []struct {
+		name  string
+		types []string
+	}{
+		{"clip.webm", []string{"video/webm"}},
+		{"clip.mkv", []string{"video/x-matroska", "video/webm"}},
+		{"song.mp3", []string{"audio/mpeg"}},
+	}
+	for _, tt := range tests {
+		body := get(h, "/api/preview?path="+tt.name).Body.String()
+		src := `<source src="/api/preview/raw?path=` + tt.name + `"`
+		for _, typ := range tt.types {
+			if want := src + ` type="` + typ + `">`; !strings.Contains(body, want) {
+				t.Errorf("%s: preview has no %s", tt.name, want)
+			}
+		}
+		if got := strings.Count(body, "<source "); got != len(tt.types) {
+			t.Errorf("%s: %d sources, want %d", tt.name, got, len(tt.types))
+		}
+	}
+}
+
+func TestRawServesRanges(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, root, "clip.webm", "0123456789")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/preview/raw?path=clip.webm", nil)
+	req.Header.Set("Range", "bytes=2-5")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusPartialContent {
+		t.Fatalf("status = %d, want 206", rec.Code)
+	}
+	if rec.Body.String() != "2345" {
+		t.Errorf("body = %q, want 2345", rec.Body)
+	}
+	if got := rec.Header().Get("Content-Range"); got != "bytes 2-5/10" {
+		t.Errorf("Content-Range = %q", got)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "video/webm" {
+		t.Errorf("Content-Type = %q, want video/webm", got)
+	}
+}
+
+func TestRawURLIsAbsoluteForNestedFiles(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, root, "media/2024/trip clip.mp4", "0123456789")
+
+	body := get(h, "/api/preview?path=/media/2024/trip%20clip.mp4").Body.String()
+	src := "/api/preview/raw?path=%2Fmedia%2F2024%2Ftrip+clip.mp4"
+	if !strings.Contains(body, `<source src="`+src+`"`) {
+		t.Fatalf("preview does not point its source at %s", src)
+	}
+
+	// The source resolves the same wherever the preview page is
+	req := httptest.NewRequest(http.MethodGet, src, nil)
+	req.Header.Set("Range", "bytes=7-")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "789" {
+		t.Errorf("range from the source URL: status = %d, body = %q, want 206 with 789", rec.Code, rec.Body)
+	}
+}
+
+func TestRawRejections(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, root, "media/clip.mp4", "clip")
+
+	tests := []struct {
+		method, target string
+		want           int
+	}{
+		{http.MethodGet, "/api/preview/raw?path=../secret.mp4", http.StatusForbidden},
+		{http.MethodGet, "/api/preview/raw?path=missing.mp4", http.StatusNotFound},
+		{http.MethodGet, "/api/preview/raw?path=media", http.StatusBadRequest},
+		{http.MethodPost, "/api/preview/raw?path=media/clip.mp4", http.StatusMethodNotAllowed},
+		{http.MethodHead, "/api/preview/raw?path=media/clip.mp4", http.StatusOK},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+		if rec.Code != tt.want {
+			t.Errorf("%s %s: status = %d, want %d", tt.method, tt.target, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestPreviewSiblingNavigation(t *testing.T) {
+	h, root := newTestHandler(t)
+	for _, name := range []string{"a.png", "b.png", "c.png"} {
+		writeFile(t, root, "photos/"+name, "png")
+	}
+	writeFile(t, root, "photos/notes.txt", "not an image")
+
+	link := func(name string) string {
+		return `href="/api/preview?path=` + url.QueryEscape("/photos/"+name) + `"`
+	}
+	tests := []struct {
+		name, prev, next string
+	}{
+		{"b.png", "a.png", "c.png"},
+		// The ends wrap around
+		{"a.png", "c.png", "b.png"},
+		{"c.png", "b.png", "a.png"},
+	}
+	for _, tt := range tests {
+		body := get(h, "/api/preview?path=photos/"+tt.name).Body.String()
+		if want := link(tt.prev); !strings.Contains(body, want) || !strings.Contains(body, `title="`+tt.prev+`">← Prev`) {
+			t.Errorf("%s: no previous link to %s", tt.name, tt.prev)
+		}
+		if want := link(tt.next); !strings.Contains(body, want) || !strings.Contains(body, `title="`+tt.next+`">Next →`) {
+			t.Errorf("%s: no next link to %s", tt.name, tt.next)
+		}
+		if strings.Contains(body, "notes.txt") {
+			t.Errorf("%s: navigation steps to a file of another kind", tt.name)
+		}
+	}
+
+	// A lone file of its kind has nothing to step to
+	if body := get(h, "/api/preview?path=photos/notes.txt").Body.String(); strings.Contains(body, "← Prev") {
+		t.Error("notes.txt: navigation shown without siblings")
+	}
+}
+
+func TestLargeTextPreviewIsTruncated(t *testing.T) {
+	h, root := newTestHandler(t)
+	const limit = 2 << 20
+	writeFile(t, root, "big.log", strings.Repeat("a", limit)+strings.Repeat("b", 1<<20))
+	writeFile(t, root, "big.go", strings.Repeat("a", limit)+strings.Repeat("b", 1<<20))
+
+	for _, name := range []string{"big.log", "big.go"} {
+		rec := get(h, "/api/preview?path="+name)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d", name, rec.Code)
+		}
+		body := rec.Body.String()
+		if strings.Contains(body, "bbbb") {
+			t.Errorf("%s: preview includes content past the limit", name)
+		}
+		if !strings.Contains(body, strings.Repeat("a", limit)) {
+			t.Errorf("%s: preview lacks the first %d bytes", name, limit)
+		}
+		want := `Showing first 2.0 MB of 3.0 MB — <a href="/` + name + `?download=1">download</a> for full file.`
+		if !strings.Contains(body, want) {
+			t.Errorf("%s: no banner %q", name, want)
+		}
+	}
+
+	// Small files have no banner
+	if body := get(h, "/api/preview?path=notes.txt").Body.String(); strings.Contains(body, "Showing first") {
+		t.Error("notes.txt: banner shown for a small file")
+	}
+}
+
+func TestPreviewLinksUseFilePath(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, root, "photos/a.png", "png")
+	writeFile(t, root, "logs/app.log", "started\n")
+
+	// The file may be named in the query, with or without a leading slash, or in the URL
+	for _, target := range []string{"/api/preview?path=photos/a.png", "/api/preview?path=/photos/a.png", "/api/preview/photos/a.png"} {
+		if body := get(h, target).Body.String(); !strings.Contains(body, `<img src="/photos/a.png"`) {
+			t.Errorf("GET %s: image does not load /photos/a.png", target)
+		}
+	}
+	if body := get(h, "/api/preview/logs/app.log").Body.String(); !strings.Contains(body, `data-path="/logs/app.log"`) {
+		t.Error("GET /api/preview/logs/app.log: follow button does not name the file")
+	}
+}
+
+func TestPreviewETag(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, root, "main.go", "package main\n")
+
+	for _, target := range []string{"/api/preview?path=main.go", "/api/preview?path=notes.txt"} {
+		tag := get(h, target).Header().Get("ETag")
+		if tag == "" {
+			t.Fatalf("%s: no ETag", target)
+		}
+		conditional := func() *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			req.Header.Set("If-None-Match", tag)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			return rec
+		}
+		if rec := conditional(); rec.Code != http.StatusNotModified {
+			t.Errorf("%s unchanged: status = %d, want 304", target, rec.Code)
+		}
+
+		// Editing the file changes its modification time
+		path := filepath.Join(root, strings.TrimPrefix(target, "/api/preview?path="))
+		later := time.Now().Add(time.Minute)
+		if err := os.Chtimes(path, later, later); err != nil {
+			t.Fatal(err)
+		}
+		if rec := conditional(); rec.Code != http.StatusOK || rec.Header().Get("ETag") == tag {
+			t.Errorf("%s modified: status = %d, want 200 with a new ETag", target, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package preview
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed assets/*
+var assetFiles embed.FS
+
+// GetAssetHandler returns a handler for serving embedded preview assets
+func GetAssetHandler() http.Handler {
+	// Get the assets subdirectory
+	fsys, err := fs.Sub(assetFiles, "assets")
+	if err != nil {
+		panic(err)
+	}
+	
+	return http.FileServer(http.FS(fsys))
+}
@@ -0,0 +1,28 @@
+package preview
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAssetHandlerServesScript(t *testing.T) {
+	rec := httptest.NewRecorder()
+	GetAssetHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/highlight.min.js", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.Contains(ct, "javascript") {
+		t.Errorf("Content-Type = %q, want JavaScript", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "hljs") {
+		t.Error("body is not highlight.js")
+	}
+
+	rec = httptest.NewRecorder()
+	GetAssetHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/highlight.css", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/css") {
+		t.Errorf("stylesheet Content-Type = %q, want text/css", ct)
+	}
+}
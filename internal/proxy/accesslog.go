@@ -0,0 +1,99 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"simple.http.server/internal/config"
+	"simple.http.server/internal/netutil"
+)
+
+// AccessLogEntry describes one proxied request
+type AccessLogEntry struct {
+	Time       time.Time `json:"time"`
+	RuleID     string    `json:"rule_id"`
+	Method     string    `json:"method"`
+	Host       string    `json:"host"`
+	Path       string    `json:"path"`
+	Target     string    `json:"target"`
+	Status     int       `json:"status"`
+	Bytes      int64     `json:"bytes"`
+	DurationMs float64   `json:"duration_ms"`
+}
+
+// accessLogger writes access log entries as JSON lines
+type accessLogger struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// write appends an entry to the log
+func (l *accessLogger) write(entry *AccessLogEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.enc.Encode(entry); err != nil {
+		log.Printf("Failed to write access log: %v", err)
+	}
+}
+
+// entryKey is the context key under which the in-flight access log entry is stored
+type entryKey struct{}
+
+// recordTarget notes the target a request was sent to, if it is being logged
+func recordTarget(req *http.Request) {
+	if entry, ok := req.Context().Value(entryKey{}).(*AccessLogEntry); ok {
+		entry.Target = req.URL.Scheme + "://" + req.URL.Host
+	}
+}
+
+// SetAccessLog enables JSON-lines access logging of proxied requests to w; nil disables it
+func (pm *ProxyManager) SetAccessLog(w io.Writer) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	if w == nil {
+		pm.accessLog = nil
+		return
+	}
+	pm.accessLog = &accessLogger{enc: json.NewEncoder(w)}
+}
+
+// serveLogged proxies the request, writing an access log entry when logging is enabled
+// and capturing bodies when the rule asks for it
+func (pm *ProxyManager) serveLogged(proxy http.Handler, w http.ResponseWriter, r *http.Request, rule config.ProxyRule, originalPath string) {
+	pm.mu.RLock()
+	logger := pm.accessLog
+	counter := pm.metrics
+	pm.mu.RUnlock()
+
+	counter.ProxyRequest(rule.ID)
+	setForwardedProto(r)
+	if rule.DebugCapture {
+		proxy = withCapture(proxy, rule.ID)
+	}
+
+	if logger == nil {
+		proxy.ServeHTTP(w, r)
+		return
+	}
+
+	entry := &AccessLogEntry{
+		Time:   time.Now(),
+		RuleID: rule.ID,
+		Method: r.Method,
+		Host:   r.Host,
+		Path:   originalPath,
+	}
+	rec := netutil.NewStatusRecorder(w)
+	start := time.Now()
+	proxy.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), entryKey{}, entry)))
+
+	entry.Status = rec.Status()
+	entry.Bytes = rec.Bytes()
+	entry.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+	logger.write(entry)
+}
@@ -0,0 +1,79 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"simple.http.server/internal/config"
+)
+
+// logEntries decodes the JSON lines written to an access log
+func logEntries(t *testing.T, buf *bytes.Buffer) []AccessLogEntry {
+	t.Helper()
+	var entries []AccessLogEntry
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var entry AccessLogEntry
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAccessLogEntry(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("hello"))
+	}))
+	t.Cleanup(backend.Close)
+	rule := config.ProxyRule{ID: "api", PathPrefix: "/api", StripPrefix: true, TargetURL: backend.URL, Enabled: true, Port: 9100}
+	pm, _ := newTestManager(t, rule)
+	var buf bytes.Buffer
+	pm.SetAccessLog(&buf)
+
+	proxiedBody(t, pm.ServeHTTP, "/api/hello")
+	rec := httptest.NewRecorder()
+	pm.ServePortProxy(rec, httptest.NewRequest(http.MethodPost, "/missing", nil), rule)
+
+	entries := logEntries(t, &buf)
+	if len(entries) != 2 {
+		t.Fatalf("got %d log entries, want 2", len(entries))
+	}
+	want := []AccessLogEntry{
+		{RuleID: "api", Method: http.MethodGet, Host: "example.com", Path: "/api/hello", Target: backend.URL, Status: http.StatusOK, Bytes: 5},
+		{RuleID: "api", Method: http.MethodPost, Host: "example.com", Path: "/missing", Target: backend.URL, Status: http.StatusNotFound},
+	}
+	for i, got := range entries {
+		if got.Time.IsZero() || got.DurationMs < 0 {
+			t.Errorf("entry %d: time %s, duration %v", i, got.Time, got.DurationMs)
+		}
+		got.Time, got.DurationMs = want[i].Time, 0
+		if i == 1 {
+			got.Bytes = 0 // the size of the error page is up to the backend
+		}
+		if got != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestAccessLogDisabled(t *testing.T) {
+	backend := namedBackend(t, "ok")
+	pm, _ := newTestManager(t, config.ProxyRule{ID: "api", PathPrefix: "/api", TargetURL: backend.URL, Enabled: true})
+	var buf bytes.Buffer
+	pm.SetAccessLog(&buf)
+	pm.SetAccessLog(nil)
+
+	proxiedBody(t, pm.ServeHTTP, "/api/x")
+	if buf.Len() != 0 {
+		t.Errorf("disabled access log was written: %q", buf.String())
+	}
+}
@@ -0,0 +1,73 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// failedTargetCooldown is how long a target that failed is skipped by the balancer
+const failedTargetCooldown = 10 * time.Second
+
+// balancer rotates requests round-robin across the targets of a proxy rule
+type balancer struct {
+	mu          sync.Mutex
+	targets     []*url.URL
+	directors   []func(*http.Request)
+	failedUntil []time.Time
+	next        int
+}
+
+// newBalancer creates a balancer for the given target URLs
+func newBalancer(targets []*url.URL) *balancer {
+	b := &balancer{
+		targets:     targets,
+		directors:   make([]func(*http.Request), len(targets)),
+		failedUntil: make([]time.Time, len(targets)),
+	}
+	for i, target := range targets {
+		b.directors[i] = httputil.NewSingleHostReverseProxy(target).Director
+	}
+	return b
+}
+
+// pick returns the index of the next target, skipping targets that recently failed.
+// If every target has failed recently, the next one in turn is used anyway.
+func (b *balancer) pick() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	for i := 0; i < len(b.targets); i++ {
+		idx := (b.next + i) % len(b.targets)
+		if now.After(b.failedUntil[idx]) {
+			b.next = idx + 1
+			return idx
+		}
+	}
+
+	idx := b.next % len(b.targets)
+	b.next = idx + 1
+	return idx
+}
+
+// direct rewrites req to point at the next target and returns that target
+func (b *balancer) direct(req *http.Request) *url.URL {
+	idx := b.pick()
+	b.directors[idx](req)
+	return b.targets[idx]
+}
+
+// markFailed puts the target that req was sent to on cooldown
+func (b *balancer) markFailed(req *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, target := range b.targets {
+		if target.Scheme == req.URL.Scheme && target.Host == req.URL.Host {
+			b.failedUntil[i] = time.Now().Add(failedTargetCooldown)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"simple.http.server/internal/config"
+)
+
+func TestRoundRobinAcrossTargets(t *testing.T) {
+	a, b := namedBackend(t, "a"), namedBackend(t, "b")
+	pm, _ := newTestManager(t, config.ProxyRule{
+		ID: "api", PathPrefix: "/api", TargetURL: a.URL, TargetURLs: []string{b.URL}, Enabled: true,
+	})
+
+	counts := map[string]int{}
+	for i := 0; i < 10; i++ {
+		counts[proxiedBody(t, pm.ServeHTTP, "/api/x")]++
+	}
+	if counts["a"] != 5 || counts["b"] != 5 {
+		t.Errorf("requests per target = %v, want 5 each", counts)
+	}
+}
+
+func TestDeadTargetIsSkipped(t *testing.T) {
+	live := namedBackend(t, "live")
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+	pm, _ := newTestManager(t, config.ProxyRule{
+		ID: "api", PathPrefix: "/api", TargetURL: dead.URL, TargetURLs: []string{live.URL}, Enabled: true,
+	})
+
+	// The first request reaches the dead target and fails, putting it on cooldown
+	rec := httptest.NewRecorder()
+	pm.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/x", nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("first request: status = %d, want 502 from the dead target", rec.Code)
+	}
+	for i := 0; i < 4; i++ {
+		if got := proxiedBody(t, pm.ServeHTTP, "/api/x"); got != "live" {
+			t.Errorf("request %d: body = %q, want live", i, got)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package proxy
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"simple.http.server/internal/requestlog"
+)
+
+// captureLimit is the number of bytes of each body kept by a debug capture
+const captureLimit = 16 << 10 // 16 KB
+
+// captureRequestBody reads the start of the request body for a capture and puts it
+// back in front of the rest, so the target still receives the body unchanged
+func captureRequestBody(r *http.Request, capture *requestlog.Capture) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return
+	}
+
+	head, err := io.ReadAll(io.LimitReader(r.Body, captureLimit+1))
+	if len(head) > captureLimit {
+		capture.RequestTruncated = true
+		capture.RequestBody = string(head[:captureLimit])
+	} else {
+		capture.RequestBody = string(head)
+	}
+
+	rest := io.Reader(r.Body)
+	if err != nil {
+		// Let the proxy see the same read error the capture did
+		rest = &errorReader{err: err}
+	}
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), rest), r.Body}
+}
+
+// errorReader returns err from every read
+type errorReader struct {
+	err error
+}
+
+func (e *errorReader) Read([]byte) (int, error) {
+	return 0, e.err
+}
+
+// captureWriter keeps the start of a response body while passing it through
+type captureWriter struct {
+	http.ResponseWriter
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (c *captureWriter) Write(b []byte) (int, error) {
+	if room := captureLimit - c.body.Len(); room > 0 {
+		if len(b) > room {
+			c.body.Write(b[:room])
+			c.truncated = true
+		} else {
+			c.body.Write(b)
+		}
+	} else if len(b) > 0 {
+		c.truncated = true
+	}
+	return c.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer for flushing and hijacking
+func (c *captureWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// withCapture wraps a proxy so the start of both bodies is added to each request's log entry
+func withCapture(proxy http.Handler, ruleID string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capture := &requestlog.Capture{RuleID: ruleID}
+		captureRequestBody(r, capture)
+
+		cw := &captureWriter{ResponseWriter: w}
+		proxy.ServeHTTP(cw, r)
+
+		capture.ResponseBody = cw.body.String()
+		capture.ResponseTruncated = cw.truncated
+		requestlog.Attach(r, capture)
+	})
+}
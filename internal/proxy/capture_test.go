@@ -0,0 +1,73 @@
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"simple.http.server/internal/config"
+	"simple.http.server/internal/requestlog"
+)
+
+func TestDebugCaptureRecordsBodies(t *testing.T) {
+	received := make(chan string, 1)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- string(body)
+		io.WriteString(w, "echo:"+string(body))
+	}))
+	t.Cleanup(backend.Close)
+	pm, _ := newTestManager(t,
+		config.ProxyRule{ID: "api", PathPrefix: "/api", TargetURL: backend.URL, Enabled: true, DebugCapture: true},
+		config.ProxyRule{ID: "quiet", PathPrefix: "/quiet", TargetURL: backend.URL, Enabled: true},
+	)
+	log := requestlog.New(10)
+	h := log.Wrap(pm)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return rec
+	}
+
+	const payload = `{"name":"widget","qty":3}`
+	rec := post("/api/items", payload)
+	if got := <-received; got != payload {
+		t.Errorf("upstream received %q, want %q", got, payload)
+	}
+	if rec.Body.String() != "echo:"+payload {
+		t.Errorf("client received %q", rec.Body)
+	}
+	capture := log.Recent(1)[0].Capture
+	if capture == nil {
+		t.Fatal("no capture recorded")
+	}
+	want := requestlog.Capture{RuleID: "api", RequestBody: payload, ResponseBody: "echo:" + payload}
+	if *capture != want {
+		t.Errorf("capture = %+v, want %+v", *capture, want)
+	}
+
+	// Bodies past the cap are truncated in the record but still sent in full
+	large := strings.Repeat("x", captureLimit+100)
+	post("/api/items", large)
+	if got := <-received; got != large {
+		t.Errorf("upstream received %d bytes, want %d", len(got), len(large))
+	}
+	capture = log.Recent(1)[0].Capture
+	if capture == nil {
+		t.Fatal("no capture recorded for the large body")
+	}
+	if len(capture.RequestBody) != captureLimit || !capture.RequestTruncated || !capture.ResponseTruncated {
+		t.Errorf("large capture = request %d bytes truncated %v, response truncated %v; want the first %d bytes",
+			len(capture.RequestBody), capture.RequestTruncated, capture.ResponseTruncated, captureLimit)
+	}
+
+	// Rules without debug capture record nothing
+	post("/quiet/items", payload)
+	<-received
+	if capture := log.Recent(1)[0].Capture; capture != nil {
+		t.Errorf("capture = %+v for a rule without debug_capture", capture)
+	}
+}
@@ -0,0 +1,110 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"simple.http.server/internal/config"
+)
+
+const (
+	dryRunTimeout  = 10 * time.Second
+	dryRunMaxBytes = 4 << 10 // 4 KB of the response body is returned
+)
+
+// DryRunResult is the upstream response to a request sent through a candidate rule
+type DryRunResult struct {
+	Status       int                 `json:"status"`
+	Headers      map[string][]string `json:"headers"`
+	Body         string              `json:"body"`
+	Truncated    bool                `json:"truncated"`     // the body was longer than returned
+	UpstreamPath string              `json:"upstream_path"` // path after StripPrefix and rewriting
+	DurationMs   float64             `json:"duration_ms"`
+}
+
+// DryRun sends a method request for path through a temporary proxy built from rule and
+// returns the response. Nothing is cached, so the rule need not be saved.
+func DryRun(rule config.ProxyRule, method, path string) (*DryRunResult, error) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, errors.New("path must start with /")
+	}
+	target, err := url.ParseRequestURI(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dryRunTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Host = "localhost"
+	if rule.Host != "" {
+		req.Host = rule.Host
+	}
+
+	pm := NewProxyManager(nil)
+	proxy := pm.getOrCreateProxy(rule)
+	if proxy == nil {
+		return nil, errors.New("invalid target URL or CA file")
+	}
+	if rule.Port == 0 {
+		pm.stripPrefix(req, rule)
+	}
+	pm.rewritePath(req, rule)
+
+	rec := &limitedRecorder{header: make(http.Header)}
+	start := time.Now()
+	proxy.ServeHTTP(rec, req)
+
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return &DryRunResult{
+		Status:       rec.status,
+		Headers:      rec.header,
+		Body:         string(rec.body),
+		Truncated:    rec.truncated,
+		UpstreamPath: req.URL.Path,
+		DurationMs:   float64(time.Since(start).Microseconds()) / 1000,
+	}, nil
+}
+
+// limitedRecorder is a ResponseWriter keeping the status, headers and the start of the body
+type limitedRecorder struct {
+	header    http.Header
+	status    int
+	body      []byte
+	truncated bool
+}
+
+func (l *limitedRecorder) Header() http.Header {
+	return l.header
+}
+
+func (l *limitedRecorder) WriteHeader(status int) {
+	if l.status == 0 {
+		l.status = status
+	}
+}
+
+// Write keeps up to dryRunMaxBytes and discards the rest, reporting success so the proxy keeps copying
+func (l *limitedRecorder) Write(b []byte) (int, error) {
+	if l.status == 0 {
+		l.status = http.StatusOK
+	}
+	room := dryRunMaxBytes - len(l.body)
+	if len(b) > room {
+		l.body = append(l.body, b[:room]...)
+		l.truncated = true
+	} else {
+		l.body = append(l.body, b...)
+	}
+	return len(b), nil
+}
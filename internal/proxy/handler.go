@@ -2,103 +2,201 @@ package proxy
 
 import (
 	"log"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
 
 	"simple.http.server/internal/config"
+	"simple.http.server/internal/metrics"
 )
 
 // ProxyManager manages dynamic reverse proxies
 type ProxyManager struct {
-	mu      sync.RWMutex
-	proxies map[string]*httputil.ReverseProxy
-	config  *config.Config
+	mu       sync.RWMutex
+	proxies   map[string]cachedProxy
+	rewrites  map[string]*regexp.Regexp // by pattern
+	config    *config.Config
+	accessLog *accessLogger
+	metrics   *metrics.Metrics // optional, see SetMetrics
+}
+
+// cachedProxy is the proxy built for a rule, kept until the rule changes
+type cachedProxy struct {
+	rule  config.ProxyRule
+	proxy *httputil.ReverseProxy
 }
 
 // NewProxyManager creates a new proxy manager
 func NewProxyManager(cfg *config.Config) *ProxyManager {
 	return &ProxyManager{
-		proxies: make(map[string]*httputil.ReverseProxy),
-		config:  cfg,
+		proxies:  make(map[string]cachedProxy),
+		rewrites: make(map[string]*regexp.Regexp),
+		config:   cfg,
 	}
 }
 
+// SetMetrics sets where proxied requests are counted
+func (pm *ProxyManager) SetMetrics(m *metrics.Metrics) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.metrics = m
+}
+
 // ServeHTTP handles reverse proxy requests
 func (pm *ProxyManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	rules := pm.config.GetProxyRules()
-	
 	// Find matching proxy rule
+	rule, ok := pm.Match(r)
+	if !ok {
+		http.Error(w, "No proxy rule matches this path", http.StatusNotFound)
+		return
+	}
+
+	// Get or create proxy for this rule
+	proxy := pm.getOrCreateProxy(rule)
+	
+	if proxy == nil {
+		http.Error(w, "Proxy configuration error", http.StatusInternalServerError)
+		return
+	}
+	
+	// Modify request path if needed
+	originalPath := r.URL.Path
+	pm.stripPrefix(r, rule)
+	pm.rewritePath(r, rule)
+	
+	log.Printf("Proxying %s%s -> %s%s", r.Host, originalPath, strings.Join(rule.Targets(), ", "), r.URL.Path)
+	
+	// Proxy the request
+	pm.serveLogged(proxy, w, r, rule, originalPath)
+}
+
+// Match returns the enabled path or host rule that handles r.
+// Rules with a Host take priority; among them an empty PathPrefix matches every path.
+// Within each group rules are tried in the order given by sortedRules.
+func (pm *ProxyManager) Match(r *http.Request) (config.ProxyRule, bool) {
+	rules := sortedRules(pm.config.GetProxyRules())
+	host := requestHost(r)
+
 	for _, rule := range rules {
-		if strings.HasPrefix(r.URL.Path, rule.PathPrefix) {
-			// Get or create proxy for this rule
-			proxy := pm.getOrCreateProxy(rule)
-			
-			if proxy == nil {
-				http.Error(w, "Proxy configuration error", http.StatusInternalServerError)
-				return
-			}
-			
-			// Modify request path if needed
-			originalPath := r.URL.Path
-			if rule.StripPrefix {
-				r.URL.Path = strings.TrimPrefix(r.URL.Path, rule.PathPrefix)
-				if r.URL.Path == "" {
-					r.URL.Path = "/"
-				}
-			}
-			
-			log.Printf("Proxying %s -> %s%s", originalPath, rule.TargetURL, r.URL.Path)
-			
-			// Proxy the request
-			proxy.ServeHTTP(w, r)
-			return
+		if rule.Enabled && rule.Host != "" && strings.EqualFold(rule.Host, host) &&
+			strings.HasPrefix(r.URL.Path, rule.PathPrefix) {
+			return rule, true
 		}
 	}
-	
-	// No matching rule found
-	http.Error(w, "No proxy rule matches this path", http.StatusNotFound)
+
+	for _, rule := range rules {
+		if rule.Enabled && rule.Host == "" && rule.PathPrefix != "" &&
+			strings.HasPrefix(r.URL.Path, rule.PathPrefix) {
+			return rule, true
+		}
+	}
+	return config.ProxyRule{}, false
+}
+
+// sortedRules orders rules for matching: by descending Priority, then by descending
+// PathPrefix length so specific prefixes win over broad ones, then in storage order
+func sortedRules(rules []config.ProxyRule) []config.ProxyRule {
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority > rules[j].Priority
+		}
+		return len(rules[i].PathPrefix) > len(rules[j].PathPrefix)
+	})
+	return rules
 }
 
-// getOrCreateProxy gets an existing proxy or creates a new one
+// requestHost returns the request's host name without the port
+func requestHost(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.Host); err == nil {
+		return host
+	}
+	return r.Host
+}
+
+// setForwardedProto tells the target whether the client connected over TLS. It is set on
+// the incoming request, as the director works on a copy made for the target; per-rule
+// headers are applied after it and may still override it.
+func setForwardedProto(r *http.Request) {
+	proto := "http"
+	if r.TLS != nil {
+		proto = "https"
+	}
+	r.Header.Set("X-Forwarded-Proto", proto)
+}
+
+// getOrCreateProxy gets an existing proxy or creates a new one; a proxy built for an
+// older version of the rule is replaced
 func (pm *ProxyManager) getOrCreateProxy(rule config.ProxyRule) *httputil.ReverseProxy {
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
 	
 	// Check if proxy already exists
-	if proxy, exists := pm.proxies[rule.ID]; exists {
-		return proxy
+	if cached, exists := pm.proxies[rule.ID]; exists && reflect.DeepEqual(cached.rule, rule) {
+		return cached.proxy
 	}
 	
-	// Parse target URL
-	targetURL, err := url.Parse(rule.TargetURL)
-	if err != nil {
-		log.Printf("Error parsing target URL %s: %v", rule.TargetURL, err)
+	// Parse target URLs
+	targets := make([]*url.URL, 0, len(rule.Targets()))
+	for _, target := range rule.Targets() {
+		targetURL, err := url.Parse(target)
+		if err != nil {
+			log.Printf("Error parsing target URL %s: %v", target, err)
+			return nil
+		}
+		targets = append(targets, targetURL)
+	}
+	if len(targets) == 0 {
+		log.Printf("Proxy rule %s has no target URL", rule.ID)
 		return nil
 	}
+	lb := newBalancer(targets)
 	
-	// Create new reverse proxy
-	proxy := httputil.NewSingleHostReverseProxy(targetURL)
+	// Create new reverse proxy; the balancer picks the target for each request
+	transport, err := newTransport(rule)
+	if err != nil {
+		log.Printf("Proxy rule %s: %v", rule.ID, err)
+		return nil
+	}
+	proxy := &httputil.ReverseProxy{}
+	proxy.Transport = transport
 	
 	// Customize the director to handle headers
-	originalDirector := proxy.Director
 	proxy.Director = func(req *http.Request) {
-		originalDirector(req)
-		req.Host = targetURL.Host
+		targetURL := lb.direct(req)
+		recordTarget(req)
+		// Tell the target which host the client asked for before it is replaced
 		req.Header.Set("X-Forwarded-Host", req.Host)
-		req.Header.Set("X-Forwarded-Proto", "http")
+		req.Host = targetURL.Host
+
+		// Apply per-rule headers last so they can override the defaults above
+		for name, value := range rule.Headers {
+			if value == "" {
+				req.Header.Del(name)
+			} else {
+				req.Header.Set(name, value)
+			}
+		}
 	}
 	
-	// Custom error handler
+	// Custom error handler; r is the outgoing request, so r.URL names the failed target
 	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
-		log.Printf("Proxy error for %s: %v", rule.TargetURL, err)
-		http.Error(w, "Proxy error: "+err.Error(), http.StatusBadGateway)
+		log.Printf("Proxy error for %s://%s: %v", r.URL.Scheme, r.URL.Host, err)
+		lb.markFailed(r)
+		status := http.StatusBadGateway
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			status = http.StatusGatewayTimeout
+		}
+		http.Error(w, "Proxy error: "+err.Error(), status)
 	}
 	
-	pm.proxies[rule.ID] = proxy
-	log.Printf("Created proxy for %s -> %s", rule.PathPrefix, rule.TargetURL)
+	pm.proxies[rule.ID] = cachedProxy{rule: rule, proxy: proxy}
+	log.Printf("Created proxy for %s -> %s", rule.PathPrefix, strings.Join(rule.Targets(), ", "))
 	
 	return proxy
 }
@@ -109,11 +207,60 @@ func (pm *ProxyManager) RefreshProxies() {
 	defer pm.mu.Unlock()
 	
 	log.Println("Refreshing all proxies")
-	pm.proxies = make(map[string]*httputil.ReverseProxy)
+	pm.proxies = make(map[string]cachedProxy)
+	pm.rewrites = make(map[string]*regexp.Regexp)
+}
+
+// stripPrefix removes the rule's path prefix from the request path when StripPrefix is set
+func (pm *ProxyManager) stripPrefix(r *http.Request, rule config.ProxyRule) {
+	if !rule.StripPrefix {
+		return
+	}
+	// A prefix ending in "/" would leave the path without its leading slash
+	r.URL.Path = strings.TrimPrefix(r.URL.Path, rule.PathPrefix)
+	if !strings.HasPrefix(r.URL.Path, "/") {
+		r.URL.Path = "/" + r.URL.Path
+	}
+}
+
+// rewritePath applies the rule's RewriteFrom/RewriteTo to the request path
+func (pm *ProxyManager) rewritePath(r *http.Request, rule config.ProxyRule) {
+	if rule.RewriteFrom == "" {
+		return
+	}
+
+	pm.mu.Lock()
+	re, exists := pm.rewrites[rule.RewriteFrom]
+	if !exists {
+		var err error
+		re, err = regexp.Compile(rule.RewriteFrom)
+		if err != nil {
+			log.Printf("Invalid rewrite pattern %q for rule %s: %v", rule.RewriteFrom, rule.ID, err)
+		}
+		pm.rewrites[rule.RewriteFrom] = re
+	}
+	pm.mu.Unlock()
+
+	if re == nil {
+		return
+	}
+
+	r.URL.Path = re.ReplaceAllString(r.URL.Path, rule.RewriteTo)
+	r.URL.RawPath = ""
+	if !strings.HasPrefix(r.URL.Path, "/") {
+		r.URL.Path = "/" + r.URL.Path
+	}
 }
 
 // ServePortProxy handles port-based reverse proxy requests
 func (pm *ProxyManager) ServePortProxy(w http.ResponseWriter, r *http.Request, rule config.ProxyRule) {
+	// The listener outlives config changes, so use the rule as it is now
+	rule, ok := pm.config.GetProxyRule(rule.ID)
+	if !ok || !rule.Enabled {
+		http.Error(w, "Proxy rule is disabled", http.StatusServiceUnavailable)
+		return
+	}
+
 	proxy := pm.getOrCreateProxy(rule)
 	
 	if proxy == nil {
@@ -121,8 +268,11 @@ func (pm *ProxyManager) ServePortProxy(w http.ResponseWriter, r *http.Request, r
 		return
 	}
 	
-	log.Printf("Port proxy: localhost:%d%s -> %s%s", rule.Port, r.URL.Path, rule.TargetURL, r.URL.Path)
+	originalPath := r.URL.Path
+	pm.rewritePath(r, rule)
+
+	log.Printf("Port proxy: localhost:%d%s -> %s%s", rule.Port, originalPath, strings.Join(rule.Targets(), ", "), r.URL.Path)
 	
 	// Proxy the request
-	proxy.ServeHTTP(w, r)
+	pm.serveLogged(proxy, w, r, rule, originalPath)
 }
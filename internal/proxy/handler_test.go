@@ -0,0 +1,280 @@
+package proxy
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"simple.http.server/internal/config"
+)
+
+// newTestManager returns a proxy manager whose config holds rules
+func newTestManager(t *testing.T, rules ...config.ProxyRule) (*ProxyManager, *config.Config) {
+	t.Helper()
+	settings, err := json.Marshal(map[string]interface{}{"proxy_rules": rules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	return NewProxyManager(cfg), cfg
+}
+
+// namedBackend starts a server that answers every request with name
+func namedBackend(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, name)
+	}))
+	t.Cleanup(backend.Close)
+	return backend
+}
+
+// proxiedBody sends a request through serve and returns the response body
+func proxiedBody(t *testing.T, serve http.HandlerFunc, path string) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	serve(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s: status = %d: %s", path, rec.Code, rec.Body)
+	}
+	return rec.Body.String()
+}
+
+func TestServePortProxyFollowsRuleChanges(t *testing.T) {
+	first, second := namedBackend(t, "first"), namedBackend(t, "second")
+	started := config.ProxyRule{ID: "p", Port: 9000, TargetURL: first.URL, Enabled: true}
+	pm, cfg := newTestManager(t, started)
+
+	// The listener keeps the rule it was started with
+	serve := func(w http.ResponseWriter, r *http.Request) { pm.ServePortProxy(w, r, started) }
+	if got := proxiedBody(t, serve, "/"); got != "first" {
+		t.Fatalf("body = %q, want first", got)
+	}
+
+	updated := started
+	updated.TargetURL = second.URL
+	cfg.UpdateProxyRule("p", updated)
+	if got := proxiedBody(t, serve, "/"); got != "second" {
+		t.Errorf("body = %q after the target changed, want second", got)
+	}
+
+	updated.Enabled = false
+	cfg.UpdateProxyRule("p", updated)
+	rec := httptest.NewRecorder()
+	serve(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d for a disabled rule, want 503", rec.Code)
+	}
+}
+
+func TestServeHTTPRebuildsProxyWhenRuleChanges(t *testing.T) {
+	first, second := namedBackend(t, "first"), namedBackend(t, "second")
+	rule := config.ProxyRule{ID: "api", PathPrefix: "/api", TargetURL: first.URL, Enabled: true}
+	pm, cfg := newTestManager(t, rule)
+
+	if got := proxiedBody(t, pm.ServeHTTP, "/api/x"); got != "first" {
+		t.Fatalf("body = %q, want first", got)
+	}
+	rule.TargetURL = second.URL
+	cfg.UpdateProxyRule("api", rule)
+	if got := proxiedBody(t, pm.ServeHTTP, "/api/x"); got != "second" {
+		t.Errorf("body = %q after the target changed without a refresh, want second", got)
+	}
+}
+
+func TestForwardedProtoFollowsClientConnection(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Header.Get("X-Forwarded-Proto"))
+	}))
+	t.Cleanup(backend.Close)
+	pm, _ := newTestManager(t, config.ProxyRule{ID: "api", PathPrefix: "/api", TargetURL: backend.URL, Enabled: true})
+
+	for _, front := range []*httptest.Server{httptest.NewServer(pm), httptest.NewTLSServer(pm)} {
+		t.Cleanup(front.Close)
+		resp, err := front.Client().Get(front.URL + "/api/x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		want := "http"
+		if front.TLS != nil {
+			want = "https"
+		}
+		if string(body) != want {
+			t.Errorf("X-Forwarded-Proto = %q through %s, want %q", body, front.URL, want)
+		}
+	}
+}
+
+func TestRuleHeadersOverrideForwardedProto(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Header.Get("X-Forwarded-Proto"))
+	}))
+	t.Cleanup(backend.Close)
+	pm, _ := newTestManager(t, config.ProxyRule{
+		ID: "api", PathPrefix: "/api", TargetURL: backend.URL, Enabled: true,
+		Headers: map[string]string{"X-Forwarded-Proto": "https"},
+	})
+	if got := proxiedBody(t, pm.ServeHTTP, "/api/x"); got != "https" {
+		t.Errorf("X-Forwarded-Proto = %q, want the rule's https", got)
+	}
+}
+
+func TestRuleHeadersReachTarget(t *testing.T) {
+	received := make(chan http.Header, 1)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+	}))
+	t.Cleanup(backend.Close)
+	pm, _ := newTestManager(t, config.ProxyRule{
+		ID: "api", PathPrefix: "/api", TargetURL: backend.URL, Enabled: true,
+		Headers: map[string]string{
+			"X-Api-Key":  "secret",
+			"User-Agent": "simple-http-server",
+			"Cookie":     "", // removed
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/x", nil)
+	req.Header.Set("User-Agent", "browser")
+	req.Header.Set("Cookie", "session=1")
+	req.Header.Set("Accept", "text/plain")
+	pm.ServeHTTP(httptest.NewRecorder(), req)
+
+	got := <-received
+	for name, want := range map[string]string{
+		"X-Api-Key":  "secret",
+		"User-Agent": "simple-http-server",
+		"Accept":     "text/plain",
+	} {
+		if got.Get(name) != want {
+			t.Errorf("%s = %q, want %q", name, got.Get(name), want)
+		}
+	}
+	if _, ok := got["Cookie"]; ok {
+		t.Errorf("Cookie = %q, want it removed", got.Get("Cookie"))
+	}
+	if got.Get("X-Forwarded-For") == "" {
+		t.Error("X-Forwarded-For is missing")
+	}
+	if got.Get("X-Forwarded-Host") != req.Host {
+		t.Errorf("X-Forwarded-Host = %q, want the client's %q", got.Get("X-Forwarded-Host"), req.Host)
+	}
+}
+
+// pathBackend starts a server that answers every request with the path it received
+func pathBackend(t *testing.T) *httptest.Server {
+	t.Helper()
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.URL.Path)
+	}))
+	t.Cleanup(backend.Close)
+	return backend
+}
+
+func TestRewriteWithCaptureGroups(t *testing.T) {
+	backend := pathBackend(t)
+	pm, _ := newTestManager(t, config.ProxyRule{
+		ID: "users", PathPrefix: "/api/", TargetURL: backend.URL, Enabled: true, StripPrefix: true,
+		RewriteFrom: `^/users/(\d+)/posts/(\w+)$`, RewriteTo: "/v2/authors/$1/posts/$2",
+	})
+
+	tests := []struct{ path, want string }{
+		{"/api/users/42/posts/hello", "/v2/authors/42/posts/hello"},
+		// Paths the pattern does not match are only stripped
+		{"/api/users/me", "/users/me"},
+	}
+	for _, tt := range tests {
+		if got := proxiedBody(t, pm.ServeHTTP, tt.path); got != tt.want {
+			t.Errorf("GET %s reached %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestInvalidRewriteLeavesPathAlone(t *testing.T) {
+	backend := pathBackend(t)
+	pm, _ := newTestManager(t, config.ProxyRule{
+		ID: "bad", PathPrefix: "/api/", TargetURL: backend.URL, Enabled: true,
+		RewriteFrom: `(unclosed`, RewriteTo: "/x",
+	})
+	if got := proxiedBody(t, pm.ServeHTTP, "/api/a"); got != "/api/a" {
+		t.Errorf("path = %q, want /api/a unchanged", got)
+	}
+}
+
+// bodyForHost sends a request for path with the given Host header through pm
+func bodyForHost(t *testing.T, pm *ProxyManager, host, path string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Host = host
+	rec := httptest.NewRecorder()
+	pm.ServeHTTP(rec, req)
+	return rec.Code, rec.Body.String()
+}
+
+func TestHostRouting(t *testing.T) {
+	blog, shop, paths := namedBackend(t, "blog"), namedBackend(t, "shop"), namedBackend(t, "paths")
+	pm, _ := newTestManager(t,
+		config.ProxyRule{ID: "path", PathPrefix: "/app", TargetURL: paths.URL, Enabled: true},
+		config.ProxyRule{ID: "blog", Host: "blog.lan", TargetURL: blog.URL, Enabled: true},
+		config.ProxyRule{ID: "shop", Host: "shop.lan", PathPrefix: "/app", TargetURL: shop.URL, Enabled: true},
+	)
+
+	tests := []struct {
+		host, path string
+		want       string
+	}{
+		{"blog.lan", "/", "blog"},
+		{"BLOG.lan:8080", "/app/x", "blog"}, // host rules win over path rules; case and port are ignored
+		{"shop.lan", "/app/cart", "shop"},
+		{"shop.lan", "/other", ""},
+		{"other.lan", "/app/x", "paths"},
+		{"other.lan", "/", ""},
+	}
+	for _, tt := range tests {
+		code, body := bodyForHost(t, pm, tt.host, tt.path)
+		if tt.want == "" {
+			if code != http.StatusNotFound {
+				t.Errorf("%s%s: status = %d, want 404", tt.host, tt.path, code)
+			}
+			continue
+		}
+		if body != tt.want {
+			t.Errorf("%s%s: reached %q, want %q", tt.host, tt.path, body, tt.want)
+		}
+	}
+}
+
+func TestRulePriority(t *testing.T) {
+	broad, specific := namedBackend(t, "broad"), namedBackend(t, "specific")
+
+	tests := []struct {
+		name                    string
+		broadPrio, specificPrio int
+		want                    string
+	}{
+		{"equal priorities prefer the longer prefix", 0, 0, "specific"},
+		{"higher priority specific rule", 0, 10, "specific"},
+		{"higher priority broad rule", 10, 0, "broad"},
+	}
+	for _, tt := range tests {
+		// The broad rule is stored first, so storage order alone would pick it
+		pm, _ := newTestManager(t,
+			config.ProxyRule{ID: "broad", PathPrefix: "/", TargetURL: broad.URL, Enabled: true, Priority: tt.broadPrio},
+			config.ProxyRule{ID: "specific", PathPrefix: "/api", TargetURL: specific.URL, Enabled: true, Priority: tt.specificPrio},
+		)
+		if got := proxiedBody(t, pm.ServeHTTP, "/api/x"); got != tt.want {
+			t.Errorf("%s: /api/x reached %q, want %q", tt.name, got, tt.want)
+		}
+		if got := proxiedBody(t, pm.ServeHTTP, "/other"); got != "broad" {
+			t.Errorf("%s: /other reached %q, want broad", tt.name, got)
+		}
+	}
+}
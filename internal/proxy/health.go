@@ -0,0 +1,90 @@
+package proxy
+
+import (
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"simple.http.server/internal/config"
+)
+
+const (
+	healthCheckTimeout = 2 * time.Second
+	healthCheckWorkers = 8
+)
+
+// HealthStatus reports whether a proxy target accepted a connection
+type HealthStatus struct {
+	ID        string  `json:"id"`
+	TargetURL string  `json:"target_url"`
+	Healthy   bool    `json:"healthy"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// CheckHealth dials every target of every rule concurrently and reports the results
+// in rule and target order
+func CheckHealth(rules []config.ProxyRule) []HealthStatus {
+	results := []HealthStatus{}
+	for _, rule := range rules {
+		for _, target := range rule.Targets() {
+			results = append(results, HealthStatus{ID: rule.ID, TargetURL: target})
+		}
+	}
+
+	jobs := make(chan *HealthStatus)
+	var wg sync.WaitGroup
+	for i := 0; i < healthCheckWorkers && i < len(results); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for status := range jobs {
+				checkTarget(status)
+			}
+		}()
+	}
+	for i := range results {
+		jobs <- &results[i]
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// checkTarget opens a TCP connection to the target's host and records the outcome
+func checkTarget(status *HealthStatus) {
+	addr, err := dialAddress(status.TargetURL)
+	if err != nil {
+		status.Error = err.Error()
+		return
+	}
+
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", addr, healthCheckTimeout)
+	status.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		status.Error = err.Error()
+		return
+	}
+	conn.Close()
+	status.Healthy = true
+}
+
+// dialAddress returns the host:port to dial for a target URL, filling in the scheme's default port
+func dialAddress(target string) (string, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", err
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
@@ -0,0 +1,105 @@
+package proxy
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"simple.http.server/internal/config"
+)
+
+// retryBackoff is the delay before the first retry; later retries wait proportionally longer
+const retryBackoff = 100 * time.Millisecond
+
+// newTransport builds the transport for a proxy rule, applying its timeouts, certificate
+// checks and retry policy
+func newTransport(rule config.ProxyRule) (http.RoundTripper, error) {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if rule.DialTimeout > 0 {
+		dialer.Timeout = time.Duration(rule.DialTimeout) * time.Second
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	if rule.ResponseTimeout > 0 {
+		transport.ResponseHeaderTimeout = time.Duration(rule.ResponseTimeout) * time.Second
+	}
+	if rule.InsecureSkipVerify || rule.CAFile != "" {
+		tlsConfig := &tls.Config{InsecureSkipVerify: rule.InsecureSkipVerify}
+		if rule.CAFile != "" {
+			roots, err := LoadCAFile(rule.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = roots
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	if rule.MaxRetries <= 0 {
+		return transport, nil
+	}
+	return &retryTransport{base: transport, maxRetries: rule.MaxRetries}, nil
+}
+
+// LoadCAFile returns the system roots together with the CA certificates in a PEM file
+func LoadCAFile(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM(data) {
+		return nil, errors.New("no PEM certificates found in CA file " + path)
+	}
+	return roots, nil
+}
+
+// retryTransport retries bodiless GET and HEAD requests that fail before a response arrives
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+}
+
+// RoundTrip sends the request, retrying idempotent requests on connection failures
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err == nil || !isRetryable(req) {
+		return resp, err
+	}
+
+	for attempt := 1; attempt <= t.maxRetries; attempt++ {
+		select {
+		case <-req.Context().Done():
+			return nil, err
+		case <-time.After(time.Duration(attempt) * retryBackoff):
+		}
+
+		log.Printf("Retrying %s %s (attempt %d/%d) after error: %v", req.Method, req.URL, attempt, t.maxRetries, err)
+		resp, err = t.base.RoundTrip(req)
+		if err == nil {
+			return resp, nil
+		}
+	}
+	return nil, err
+}
+
+// isRetryable reports whether a request can safely be sent again
+func isRetryable(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody
+}
@@ -0,0 +1,140 @@
+package proxy
+
+import (
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"simple.http.server/internal/config"
+)
+
+// flakyBackend drops the connection of the first failures requests, then answers "ok".
+// It returns the server and the number of requests it has seen.
+func flakyBackend(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var seen atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if seen.Add(1) <= failures {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	t.Cleanup(backend.Close)
+	return backend, &seen
+}
+
+func TestRetryAfterConnectionFailure(t *testing.T) {
+	backend, seen := flakyBackend(t, 1)
+	pm, _ := newTestManager(t, config.ProxyRule{ID: "api", PathPrefix: "/api", TargetURL: backend.URL, Enabled: true, MaxRetries: 2})
+
+	if got := proxiedBody(t, pm.ServeHTTP, "/api/x"); got != "ok" {
+		t.Errorf("body = %q, want ok", got)
+	}
+	if n := seen.Load(); n != 2 {
+		t.Errorf("backend saw %d requests, want 2", n)
+	}
+}
+
+func TestNoRetryWithoutMaxRetries(t *testing.T) {
+	backend, seen := flakyBackend(t, 1)
+	pm, _ := newTestManager(t, config.ProxyRule{ID: "api", PathPrefix: "/api", TargetURL: backend.URL, Enabled: true})
+
+	rec := httptest.NewRecorder()
+	pm.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/x", nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", rec.Code)
+	}
+	if n := seen.Load(); n != 1 {
+		t.Errorf("backend saw %d requests, want 1", n)
+	}
+}
+
+func TestNoRetryForRequestsWithBodies(t *testing.T) {
+	backend, seen := flakyBackend(t, 1)
+	pm, _ := newTestManager(t, config.ProxyRule{ID: "api", PathPrefix: "/api", TargetURL: backend.URL, Enabled: true, MaxRetries: 2})
+
+	rec := httptest.NewRecorder()
+	pm.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/x", strings.NewReader("payload")))
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", rec.Code)
+	}
+	if n := seen.Load(); n != 1 {
+		t.Errorf("backend saw %d requests, want the POST sent once", n)
+	}
+}
+
+func TestResponseTimeout(t *testing.T) {
+	release := make(chan struct{})
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	t.Cleanup(backend.Close)
+	t.Cleanup(func() { close(release) })
+	pm, _ := newTestManager(t, config.ProxyRule{ID: "api", PathPrefix: "/api", TargetURL: backend.URL, Enabled: true, ResponseTimeout: 1})
+
+	rec := httptest.NewRecorder()
+	pm.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/slow", nil))
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want 504", rec.Code)
+	}
+}
+
+func TestTLSTargetVerification(t *testing.T) {
+	backend := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "secure")
+	}))
+	t.Cleanup(backend.Close)
+
+	// The test server's self-signed certificate, as a CA file
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: backend.Certificate().Raw})
+	if err := os.WriteFile(caFile, cert, 0644); err != nil {
+		t.Fatal(err)
+	}
+	notPEM := filepath.Join(t.TempDir(), "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		insecure bool
+		caFile   string
+		wantOK   bool
+	}{
+		{"verified by default", false, "", false},
+		{"verification skipped", true, "", true},
+		{"trusted through the CA file", false, caFile, true},
+		{"unreadable CA file", false, filepath.Join(t.TempDir(), "missing.pem"), false},
+		{"CA file without certificates", false, notPEM, false},
+	}
+	for _, tt := range tests {
+		pm, _ := newTestManager(t, config.ProxyRule{
+			ID: "api", PathPrefix: "/api", TargetURL: backend.URL, Enabled: true,
+			InsecureSkipVerify: tt.insecure, CAFile: tt.caFile,
+		})
+		rec := httptest.NewRecorder()
+		pm.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/x", nil))
+		if tt.wantOK {
+			if rec.Code != http.StatusOK || rec.Body.String() != "secure" {
+				t.Errorf("%s: status = %d, body = %q, want the backend's answer", tt.name, rec.Code, rec.Body)
+			}
+		} else if rec.Code == http.StatusOK {
+			t.Errorf("%s: the untrusted backend was reached", tt.name)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package qr
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	defaultSize = 256  // image width and height in pixels when no size is given
+	minSize     = 64   // smallest accepted size
+	maxSize     = 1024 // largest accepted size
+)
+
+// Handler renders QR codes for arbitrary text
+type Handler struct{}
+
+// NewHandler creates a new QR code handler
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// ServeHTTP renders the data query parameter as a QR code PNG
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data := r.URL.Query().Get("data")
+	if data == "" {
+		http.Error(w, "Query parameter 'data' is required", http.StatusBadRequest)
+		return
+	}
+
+	WritePNG(w, r, data)
+}
+
+// WritePNG writes data as a QR code PNG sized by the request's size query parameter
+func WritePNG(w http.ResponseWriter, r *http.Request, data string) {
+	size := defaultSize
+	if value := r.URL.Query().Get("size"); value != "" {
+		var err error
+		size, err = strconv.Atoi(value)
+		if err != nil || size < minSize || size > maxSize {
+			http.Error(w, "Query parameter 'size' must be between 64 and 1024", http.StatusBadRequest)
+			return
+		}
+	}
+
+	png, err := qrcode.Encode(data, qrcode.Medium, size)
+	if err != nil {
+		// Most likely more data than a QR code can hold
+		http.Error(w, "Cannot encode data as a QR code: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(png)
+}
+
+// Terminal renders data as a QR code drawn with Unicode half blocks, two modules per
+// character row, for printing to a terminal with a dark background
+func Terminal(data string) (string, error) {
+	code, err := qrcode.New(data, qrcode.Low)
+	if err != nil {
+		return "", err
+	}
+	return code.ToSmallString(true), nil
+}
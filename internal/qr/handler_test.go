@@ -0,0 +1,86 @@
+package qr
+
+import (
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// get sends a GET for target to a new handler and returns the recorded response
+func get(target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestServesPNG(t *testing.T) {
+	tests := []struct {
+		target string
+		size   int
+	}{
+		{"/api/qr?data=http%3A%2F%2F192.168.1.50%3A8080%2Fdocs%2F", defaultSize},
+		{"/api/qr?data=hello&size=128", 128},
+	}
+	for _, tt := range tests {
+		rec := get(tt.target)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d: %s", tt.target, rec.Code, rec.Body)
+		}
+		if got := rec.Header().Get("Content-Type"); got != "image/png" {
+			t.Errorf("%s: Content-Type = %q, want image/png", tt.target, got)
+		}
+		img, err := png.Decode(rec.Body)
+		if err != nil {
+			t.Fatalf("%s: invalid PNG: %v", tt.target, err)
+		}
+		if b := img.Bounds(); b.Dx() != tt.size || b.Dy() != tt.size {
+			t.Errorf("%s: image is %dx%d, want %dx%d", tt.target, b.Dx(), b.Dy(), tt.size, tt.size)
+		}
+	}
+}
+
+func TestRejectsInvalidRequests(t *testing.T) {
+	targets := []string{
+		"/api/qr",
+		"/api/qr?data=x&size=10",
+		"/api/qr?data=x&size=big",
+		"/api/qr?data=" + strings.Repeat("x", 5000), // more than a QR code holds
+	}
+	for _, target := range targets {
+		if rec := get(target); rec.Code != http.StatusBadRequest {
+			t.Errorf("%.40s: status = %d, want 400", target, rec.Code)
+		}
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	code, err := Terminal("http://192.168.1.50:8080/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(code, "\n"), "\n")
+	if len(lines) < 10 {
+		t.Fatalf("QR code has %d lines, want a full symbol:\n%s", len(lines), code)
+	}
+	// The symbol is square, drawn two modules per row, so every row is equally wide
+	width := utf8.RuneCountInString(lines[0])
+	for i, line := range lines {
+		if n := utf8.RuneCountInString(line); n != width {
+			t.Fatalf("line %d is %d runes wide, want %d", i, n, width)
+		}
+	}
+	if !strings.ContainsAny(code, "▀▄█") {
+		t.Errorf("QR code has no half blocks:\n%s", code)
+	}
+
+	other, err := Terminal("http://192.168.1.51:8080/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == code {
+		t.Error("different URLs render the same QR code")
+	}
+}
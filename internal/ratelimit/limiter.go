@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"simple.http.server/internal/config"
+	"simple.http.server/internal/netutil"
+)
+
+// idleTimeout is how long a client's buckets are kept after its last request
+const idleTimeout = 10 * time.Minute
+
+// expensivePaths are limited by the expensive rate instead of the general one. Chunked
+// uploads (/api/upload/chunk) send many small requests, so they use the general rate.
+var expensivePaths = map[string]bool{
+	"/api/upload":     true,
+	"/api/upload/url": true,
+	"/api/archive":    true,
+}
+
+// bucket is a token bucket holding up to a minute's worth of requests
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take refills the bucket at perMinute tokens a minute and takes a token if one is left.
+// Otherwise it returns how long until the next token arrives.
+func (b *bucket) take(now time.Time, perMinute int) (bool, time.Duration) {
+	capacity := float64(perMinute)
+	rate := capacity / 60 // tokens per second
+
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / rate * float64(time.Second))
+}
+
+// Limiter limits the request rate of each client IP
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket // keyed by client IP and limit class
+	config  *config.Config
+}
+
+// New creates a limiter using the rates configured in cfg
+func New(cfg *config.Config) *Limiter {
+	l := &Limiter{
+		buckets: make(map[string]*bucket),
+		config:  cfg,
+	}
+
+	// Start cleanup goroutine
+	go l.cleanupIdle()
+
+	return l
+}
+
+// Wrap returns a handler that rejects requests over the client's limit with 429 Too Many Requests
+func (l *Limiter) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		general, expensive := l.config.GetRateLimits()
+
+		class, limit := "general", general
+		if expensivePaths[r.URL.Path] {
+			class, limit = "expensive", expensive
+		}
+		if limit <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ok, retryAfter := l.allow(netutil.ClientIP(r)+" "+class, limit)
+		if !ok {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allow takes a token from the bucket for key, creating a full bucket for new clients
+func (l *Limiter) allow(key string, perMinute int) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &bucket{tokens: float64(perMinute), last: now}
+		l.buckets[key] = b
+	}
+	return b.take(now, perMinute)
+}
+
+// cleanupIdle removes the buckets of clients that have not made a request recently
+func (l *Limiter) cleanupIdle() {
+	ticker := time.NewTicker(idleTimeout)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		l.mu.Lock()
+		now := time.Now()
+		for key, b := range l.buckets {
+			if now.Sub(b.last) > idleTimeout {
+				delete(l.buckets, key)
+			}
+		}
+		l.mu.Unlock()
+	}
+}
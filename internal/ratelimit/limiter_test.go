@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"simple.http.server/internal/config"
+)
+
+// newTestHandler returns a handler answering 200 behind a limiter with the given JSON settings
+func newTestHandler(t *testing.T, settings string) http.Handler {
+	t.Helper()
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings([]byte(settings)); err != nil {
+		t.Fatal(err)
+	}
+	return New(cfg).Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+}
+
+// send makes a request for path from remoteAddr, forwarded for forwardedFor when it is set
+func send(h http.Handler, path, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// burst sends n requests and returns how many were allowed
+func burst(h http.Handler, n int, path, remoteAddr, forwardedFor string) int {
+	allowed := 0
+	for i := 0; i < n; i++ {
+		if send(h, path, remoteAddr, forwardedFor).Code == http.StatusOK {
+			allowed++
+		}
+	}
+	return allowed
+}
+
+func TestBurstBeyondLimit(t *testing.T) {
+	h := newTestHandler(t, `{"rate_limit": 5, "rate_limit_expensive": 2}`)
+
+	if got := burst(h, 5, "/files/a.txt", "192.0.2.1:1000", ""); got != 5 {
+		t.Fatalf("%d of a burst of 5 allowed, want all", got)
+	}
+	rec := send(h, "/files/a.txt", "192.0.2.1:1001", "")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("request over the limit: status = %d, want 429", rec.Code)
+	}
+	// One token arrives every 12 seconds at 5 a minute
+	if seconds, err := strconv.Atoi(rec.Header().Get("Retry-After")); err != nil || seconds < 1 || seconds > 12 {
+		t.Errorf("Retry-After = %q, want 1 to 12 seconds", rec.Header().Get("Retry-After"))
+	}
+
+	// Other clients have buckets of their own
+	if got := burst(h, 5, "/files/a.txt", "192.0.2.2:1000", ""); got != 5 {
+		t.Errorf("another client: %d of 5 allowed, want all", got)
+	}
+
+	// Expensive endpoints have their own, lower limit
+	if got := burst(h, 3, "/api/archive", "192.0.2.3:1000", ""); got != 2 {
+		t.Errorf("archives: %d of 3 allowed, want 2", got)
+	}
+	if send(h, "/api/list", "192.0.2.3:1000", "").Code != http.StatusOK {
+		t.Error("exhausting the expensive limit also blocked cheap requests")
+	}
+}
+
+func TestForwardedClients(t *testing.T) {
+	h := newTestHandler(t, `{"rate_limit": 2}`)
+
+	// Behind a local proxy each forwarded client is limited on its own
+	if got := burst(h, 3, "/", "127.0.0.1:1000", "198.51.100.1"); got != 2 {
+		t.Errorf("first forwarded client: %d of 3 allowed, want 2", got)
+	}
+	if got := burst(h, 2, "/", "127.0.0.1:1000", "198.51.100.2, 127.0.0.1"); got != 2 {
+		t.Errorf("second forwarded client: %d of 2 allowed, want 2", got)
+	}
+
+	// Remote clients cannot escape their limit by claiming to be forwarded
+	if got := burst(h, 3, "/", "192.0.2.9:1000", "198.51.100.3"); got != 2 {
+		t.Errorf("spoofed header: %d of 3 allowed, want 2", got)
+	}
+	if got := burst(h, 1, "/", "192.0.2.9:1000", "198.51.100.4"); got != 0 {
+		t.Errorf("spoofed header from the same client: %d allowed, want 0", got)
+	}
+}
+
+func TestZeroLimitDisables(t *testing.T) {
+	h := newTestHandler(t, `{"rate_limit": 0, "rate_limit_expensive": 0}`)
+	if got := burst(h, 50, "/api/upload", "192.0.2.1:1000", ""); got != 50 {
+		t.Errorf("%d of 50 allowed with limits off, want all", got)
+	}
+}
@@ -0,0 +1,128 @@
+package requestlog
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"simple.http.server/internal/netutil"
+)
+
+// DefaultCapacity is the number of requests kept when no other size is given
+const DefaultCapacity = 1000
+
+// Entry describes one handled request
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Status     int       `json:"status"`
+	RemoteAddr string    `json:"remote_addr"`
+	DurationMs float64   `json:"duration_ms"`
+	Bytes      int64     `json:"bytes"`
+	Capture    *Capture  `json:"capture,omitempty"`
+}
+
+// Capture holds the start of a request's and response's bodies, recorded for debugging proxied APIs
+type Capture struct {
+	RuleID            string `json:"rule_id"`
+	RequestBody       string `json:"request_body"`
+	RequestTruncated  bool   `json:"request_truncated,omitempty"`
+	ResponseBody      string `json:"response_body"`
+	ResponseTruncated bool   `json:"response_truncated,omitempty"`
+}
+
+// entryKey is the context key under which the in-flight entry is stored
+type entryKey struct{}
+
+// Attach adds captured bodies to the entry of a request that is being logged
+func Attach(r *http.Request, capture *Capture) {
+	if entry, ok := r.Context().Value(entryKey{}).(*Entry); ok {
+		entry.Capture = capture
+	}
+}
+
+// Log keeps the most recent requests in a ring buffer, overwriting the oldest once it is full
+type Log struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int // index the next entry is written to
+	count   int // number of entries stored, at most len(entries)
+}
+
+// New creates a log holding at most capacity requests
+func New(capacity int) *Log {
+	if capacity < 1 {
+		capacity = DefaultCapacity
+	}
+	return &Log{entries: make([]Entry, capacity)}
+}
+
+// Add records a request, evicting the oldest one when the log is full
+func (l *Log) Add(entry Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % len(l.entries)
+	if l.count < len(l.entries) {
+		l.count++
+	}
+}
+
+// Recent returns up to limit requests, newest first; limit <= 0 returns all of them
+func (l *Log) Recent(limit int) []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limit <= 0 || limit > l.count {
+		limit = l.count
+	}
+	recent := make([]Entry, 0, limit)
+	for i := 1; i <= limit; i++ {
+		recent = append(recent, l.entries[(l.next-i+len(l.entries))%len(l.entries)])
+	}
+	return recent
+}
+
+// Wrap returns a handler that records every request once it has been served
+func (l *Log) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entry := &Entry{
+			Time:       time.Now(),
+			Method:     r.Method,
+			Path:       redact(r.URL),
+			RemoteAddr: r.RemoteAddr,
+		}
+		rec := netutil.NewStatusRecorder(w)
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), entryKey{}, entry)))
+
+		entry.Status = rec.Status()
+		entry.DurationMs = float64(time.Since(entry.Time).Microseconds()) / 1000
+		entry.Bytes = rec.Bytes()
+		l.Add(*entry)
+	})
+}
+
+// secretParams are query parameters whose values must not be kept, such as archive passwords
+var secretParams = []string{"password"}
+
+// redact returns the request URI of u with the values of secret parameters hidden
+func redact(u *url.URL) string {
+	query := u.Query()
+	redacted := false
+	for _, name := range secretParams {
+		if query.Has(name) {
+			query.Set(name, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.RequestURI()
+	}
+	clean := *u
+	clean.RawQuery = query.Encode()
+	return clean.RequestURI()
+}
@@ -0,0 +1,65 @@
+package requestlog
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestWrapRecordsRequests(t *testing.T) {
+	l := New(10)
+	h := l.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, "hello")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/docs/a.txt?x=1", nil)
+	req.RemoteAddr = "192.0.2.7:5000"
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/missing", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/archive?password=secret", nil))
+
+	entries := l.Recent(0)
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	// Newest first
+	if got := entries[0].Path; got != "/api/archive?password=REDACTED" {
+		t.Errorf("path = %q, want the password redacted", got)
+	}
+	if e := entries[1]; e.Method != http.MethodPost || e.Path != "/missing" || e.Status != http.StatusNotFound {
+		t.Errorf("second entry = %+v, want a POST /missing answered 404", e)
+	}
+	e := entries[2]
+	if e.Method != http.MethodGet || e.Path != "/docs/a.txt?x=1" || e.Status != http.StatusOK ||
+		e.RemoteAddr != "192.0.2.7:5000" || e.Bytes != 5 || e.DurationMs < 0 || e.Time.IsZero() {
+		t.Errorf("first entry = %+v", e)
+	}
+}
+
+func TestLogEvictsOldest(t *testing.T) {
+	l := New(3)
+	for i := 1; i <= 5; i++ {
+		l.Add(Entry{Path: "/" + strconv.Itoa(i)})
+	}
+
+	entries := l.Recent(0)
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Path)
+	}
+	if len(got) != 3 || got[0] != "/5" || got[1] != "/4" || got[2] != "/3" {
+		t.Errorf("entries = %v, want [/5 /4 /3]", got)
+	}
+	if recent := l.Recent(2); len(recent) != 2 || recent[0].Path != "/5" {
+		t.Errorf("Recent(2) = %+v, want the two newest", recent)
+	}
+	if empty := New(3).Recent(10); len(empty) != 0 {
+		t.Errorf("empty log returned %d entries", len(empty))
+	}
+}
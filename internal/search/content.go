@@ -0,0 +1,87 @@
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	maxContentFileSize = 10 << 20 // files larger than this are skipped in content mode
+	maxMatchesPerFile  = 10
+	maxSnippetLength   = 200
+	sniffLength        = 512 // bytes inspected for NUL to detect binary files
+)
+
+// ContentMatch is a line of a file that contains the query
+type ContentMatch struct {
+	Line    int    `json:"line"`
+	Snippet string `json:"snippet"`
+}
+
+// searchContent returns the lines of the file at path accepted by match.
+// Binary files, detected by a NUL byte near the start, yield no matches.
+func searchContent(path string, match matcher) ([]ContentMatch, error) {
+	var matches []ContentMatch
+	err := scanContent(path, match, func(m ContentMatch) bool {
+		matches = append(matches, m)
+		return true
+	})
+	return matches, err
+}
+
+// scanContent calls emit for each line of the file at path accepted by match, up to
+// maxMatchesPerFile lines, stopping early when emit returns false. Binary files are skipped.
+func scanContent(path string, match matcher, emit func(ContentMatch) bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	head, err := reader.Peek(sniffLength)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return err
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil
+	}
+
+	found := 0
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		idx := match(text)
+		if idx < 0 {
+			continue
+		}
+		found++
+		if !emit(ContentMatch{Line: line, Snippet: snippet(text, idx)}) || found >= maxMatchesPerFile {
+			break
+		}
+	}
+	// A line longer than the scanner buffer ends the scan; keep what was found
+	return nil
+}
+
+// snippet trims line to at most maxSnippetLength bytes around the match at idx
+func snippet(line string, idx int) string {
+	if len(line) <= maxSnippetLength {
+		return strings.TrimSpace(line)
+	}
+
+	start := idx - maxSnippetLength/4
+	if start < 0 {
+		start = 0
+	}
+	end := start + maxSnippetLength
+	if end > len(line) {
+		end = len(line)
+		start = end - maxSnippetLength
+	}
+	return strings.ToValidUTF8(strings.TrimSpace(line[start:end]), "")
+}
@@ -0,0 +1,68 @@
+package search
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// filters narrows search results by modification time and size; zero values disable a bound
+type filters struct {
+	modifiedAfter  time.Time
+	modifiedBefore time.Time
+	minSize        int64 // bytes, -1 when unset
+	maxSize        int64 // bytes, -1 when unset
+}
+
+// parseFilters reads the modified_after, modified_before, min_size and max_size query parameters
+func parseFilters(r *http.Request) (filters, error) {
+	f := filters{minSize: -1, maxSize: -1}
+	q := r.URL.Query()
+
+	var err error
+	if v := q.Get("modified_after"); v != "" {
+		if f.modifiedAfter, err = time.Parse(time.RFC3339, v); err != nil {
+			return f, errors.New("Query parameter 'modified_after' must be an RFC3339 time")
+		}
+	}
+	if v := q.Get("modified_before"); v != "" {
+		if f.modifiedBefore, err = time.Parse(time.RFC3339, v); err != nil {
+			return f, errors.New("Query parameter 'modified_before' must be an RFC3339 time")
+		}
+	}
+	if v := q.Get("min_size"); v != "" {
+		if f.minSize, err = strconv.ParseInt(v, 10, 64); err != nil || f.minSize < 0 {
+			return f, errors.New("Query parameter 'min_size' must be a non-negative number of bytes")
+		}
+	}
+	if v := q.Get("max_size"); v != "" {
+		if f.maxSize, err = strconv.ParseInt(v, 10, 64); err != nil || f.maxSize < 0 {
+			return f, errors.New("Query parameter 'max_size' must be a non-negative number of bytes")
+		}
+	}
+	return f, nil
+}
+
+// accept reports whether info passes every filter. Directories never pass a size filter.
+func (f filters) accept(info os.FileInfo) bool {
+	if !f.modifiedAfter.IsZero() && !info.ModTime().After(f.modifiedAfter) {
+		return false
+	}
+	if !f.modifiedBefore.IsZero() && !info.ModTime().Before(f.modifiedBefore) {
+		return false
+	}
+	if f.minSize >= 0 || f.maxSize >= 0 {
+		if info.IsDir() {
+			return false
+		}
+		if f.minSize >= 0 && info.Size() < f.minSize {
+			return false
+		}
+		if f.maxSize >= 0 && info.Size() > f.maxSize {
+			return false
+		}
+	}
+	return true
+}
@@ -2,13 +2,22 @@ package search
 
 import (
 	"encoding/json"
+	"fmt"
+	"io/fs"
 	"net/http"
-	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"simple.http.server/internal/config"
+	"simple.http.server/internal/pathutil"
+)
+
+const (
+	defaultLimit = 100   // results per page when no limit is given
+	maxLimit     = 1000  // largest accepted limit
+	maxMatches   = 10000 // the walk stops after this many matches
 )
 
 // FileInfo represents search result
@@ -18,6 +27,9 @@ type FileInfo struct {
 	Size     int64  `json:"size"`
 	IsDir    bool   `json:"is_dir"`
 	Modified string `json:"modified"`
+
+	// Matches lists the matching lines when searching file contents
+	Matches []ContentMatch `json:"matches,omitempty"`
 }
 
 // Handler manages file search
@@ -47,20 +59,53 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	switch r.URL.Path {
+	case "/api/search/stream":
+		h.serveStream(w, r)
+		return
+	case "/api/recent":
+		h.serveRecent(w, r)
+		return
+	}
+
 	// Get query parameters
-	query := strings.ToLower(r.URL.Query().Get("q"))
+	query := r.URL.Query().Get("q")
 	if query == "" {
 		http.Error(w, "Query parameter 'q' is required", http.StatusBadRequest)
 		return
 	}
 
+	// Compile the pattern once before walking
+	match, err := newMatcher(strings.ToLower(r.URL.Query().Get("mode")), query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	searchPath := r.URL.Query().Get("path")
 	if searchPath == "" {
 		searchPath = "/"
 	}
 
 	fileType := strings.ToLower(r.URL.Query().Get("type")) // "file", "dir", or empty for all
-	maxResults := 100
+	contentMode := r.URL.Query().Get("content") == "1"      // match file contents instead of names
+
+	filter, err := parseFilters(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limit, err := intParam(r, "limit", defaultLimit)
+	if err != nil || limit < 1 || limit > maxLimit {
+		http.Error(w, fmt.Sprintf("Query parameter 'limit' must be between 1 and %d", maxLimit), http.StatusBadRequest)
+		return
+	}
+	offset, err := intParam(r, "offset", 0)
+	if err != nil || offset < 0 {
+		http.Error(w, "Query parameter 'offset' must be a non-negative integer", http.StatusBadRequest)
+		return
+	}
 
 	// Get base directory
 	baseDir := h.config.GetFileServerDir()
@@ -79,66 +124,93 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !strings.HasPrefix(absSearch, absBase) {
+	if !pathutil.IsWithin(absBase, absSearch) {
 		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
 
 	// Search files
-	results := []FileInfo{}
-	err = filepath.Walk(absSearch, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // Skip errors, continue walking
+	matches := walkParallel(absSearch, maxMatches, func(path string, d fs.DirEntry) (FileInfo, bool) {
+		// Filter by type
+		if fileType == "file" && d.IsDir() {
+			return FileInfo{}, false
+		}
+		if fileType == "dir" && !d.IsDir() {
+			return FileInfo{}, false
 		}
 
-		if len(results) >= maxResults {
-			return filepath.SkipDir
+		// Content searches never match directories, and name searches can reject
+		// an entry before paying for a stat
+		if contentMode && d.IsDir() {
+			return FileInfo{}, false
+		}
+		if !contentMode && match(d.Name()) < 0 {
+			return FileInfo{}, false
+		}
+
+		info, err := d.Info()
+		if err != nil || !filter.accept(info) {
+			return FileInfo{}, false
 		}
 
 		// Get relative path
 		relPath, err := filepath.Rel(absBase, path)
 		if err != nil {
-			return nil
+			return FileInfo{}, false
 		}
 
-		// Skip the root itself
-		if path == absSearch {
-			return nil
+		result := FileInfo{
+			Name:     info.Name(),
+			Path:     "/" + filepath.ToSlash(relPath),
+			Size:     info.Size(),
+			IsDir:    info.IsDir(),
+			Modified: info.ModTime().Format(time.RFC3339),
 		}
 
-		// Filter by type
-		if fileType == "file" && info.IsDir() {
-			return nil
-		}
-		if fileType == "dir" && !info.IsDir() {
-			return nil
+		if contentMode {
+			// Only regular files of a reasonable size are scanned
+			if !info.Mode().IsRegular() || info.Size() > maxContentFileSize {
+				return FileInfo{}, false
+			}
+			matches, err := searchContent(path, match)
+			if err != nil || len(matches) == 0 {
+				return FileInfo{}, false
+			}
+			result.Matches = matches
 		}
 
-		// Check if name matches query
-		fileName := strings.ToLower(info.Name())
-		if strings.Contains(fileName, query) {
-			results = append(results, FileInfo{
-				Name:     info.Name(),
-				Path:     "/" + filepath.ToSlash(relPath),
-				Size:     info.Size(),
-				IsDir:    info.IsDir(),
-				Modified: info.ModTime().Format(time.RFC3339),
-			})
-		}
-
-		return nil
+		return result, true
 	})
 
-	if err != nil {
-		http.Error(w, "Search failed", http.StatusInternalServerError)
-		return
+	// Page through the sorted matches; hitting maxMatches means the total is a lower bound
+	results := []FileInfo{}
+	if offset < len(matches) {
+		end := offset + limit
+		if end > len(matches) {
+			end = len(matches)
+		}
+		results = matches[offset:end]
 	}
+	truncated := offset+len(results) < len(matches) || len(matches) >= maxMatches
 
 	// Return results
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"query":   query,
-		"results": results,
-		"count":   len(results),
+		"query":     query,
+		"results":   results,
+		"count":     len(results),
+		"total":     len(matches),
+		"offset":    offset,
+		"limit":     limit,
+		"truncated": truncated,
 	})
 }
+
+// intParam parses an integer query parameter, returning def when it is absent
+func intParam(r *http.Request, name string, def int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,228 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"simple.http.server/internal/config"
+)
+
+// newTestHandler returns a search handler for a temporary served directory
+func newTestHandler(t *testing.T) (*Handler, string) {
+	t.Helper()
+	return newTestHandlerWith(t, nil)
+}
+
+// newTestHandlerWith is newTestHandler with extra settings applied
+func newTestHandlerWith(t *testing.T, extra map[string]interface{}) (*Handler, string) {
+	t.Helper()
+	root := t.TempDir()
+	values := map[string]interface{}{"file_server_dir": root}
+	for k, v := range extra {
+		values[k] = v
+	}
+	settings, err := json.Marshal(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(cfg), root
+}
+
+// writeFile creates the file name under root, and its folders, holding content
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// searchResponse is the JSON body of a search
+type searchResponse struct {
+	Results   []FileInfo `json:"results"`
+	Count     int        `json:"count"`
+	Total     int        `json:"total"`
+	Truncated bool       `json:"truncated"`
+}
+
+// search sends target to h, failing the test unless it succeeds
+func search(t *testing.T, h *Handler, target string) searchResponse {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s: status = %d: %s", target, rec.Code, rec.Body)
+	}
+	var resp searchResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+// paths returns the paths of results
+func paths(results []FileInfo) []string {
+	out := make([]string, len(results))
+	for i, r := range results {
+		out[i] = r.Path
+	}
+	return out
+}
+
+func TestContentSearch(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, root, "notes/todo.txt", "buy milk\nfix the Needle bug\nsleep\n")
+	writeFile(t, root, "needle.txt", "nothing to see")
+	writeFile(t, root, "binary.dat", "needle\x00\x01\x02")
+
+	resp := search(t, h, "/api/search?q=needle&content=1")
+	if len(resp.Results) != 1 {
+		t.Fatalf("results = %v, want only the file containing the word", paths(resp.Results))
+	}
+	got := resp.Results[0]
+	if got.Path != "/notes/todo.txt" {
+		t.Errorf("path = %q, want /notes/todo.txt", got.Path)
+	}
+	if len(got.Matches) != 1 || got.Matches[0].Line != 2 || got.Matches[0].Snippet != "fix the Needle bug" {
+		t.Errorf("matches = %+v, want line 2", got.Matches)
+	}
+
+	// Without content=1 only names are matched
+	if resp := search(t, h, "/api/search?q=needle"); len(resp.Results) != 1 || resp.Results[0].Path != "/needle.txt" {
+		t.Errorf("name search = %v, want /needle.txt", paths(resp.Results))
+	}
+}
+
+func TestSearchModes(t *testing.T) {
+	h, root := newTestHandler(t)
+	for _, name := range []string{"app.log", "logs/old.LOG", "config.yaml", "config.dev.yaml", "myconfig.yaml", "catalog.txt"} {
+		writeFile(t, root, name, "")
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"q=log", []string{"/app.log", "/catalog.txt", "/logs", "/logs/old.LOG"}},
+		{"q=log&mode=substring", []string{"/app.log", "/catalog.txt", "/logs", "/logs/old.LOG"}},
+		{"q=*.log&mode=glob", []string{"/app.log", "/logs/old.LOG"}},
+		{"q=config.*.yaml&mode=glob", []string{"/config.dev.yaml"}},
+		{`q=^config.*\.yaml$&mode=regex`, []string{"/config.dev.yaml", "/config.yaml"}},
+		{`q=\.LOG$&mode=regex`, []string{"/logs/old.LOG"}}, // regex is case-sensitive
+	}
+	for _, tt := range tests {
+		got := paths(search(t, h, "/api/search?"+tt.query).Results)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: results = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchRejectsInvalidPatterns(t *testing.T) {
+	h, _ := newTestHandler(t)
+	for _, query := range []string{"q=(unclosed&mode=regex", "q=[a-&mode=glob", "q=x&mode=fuzzy"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/search?"+url.PathEscape(query), nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, rec.Code)
+		}
+	}
+}
+
+func TestSearchPaging(t *testing.T) {
+	h, root := newTestHandler(t)
+	for i := 0; i < 5; i++ {
+		writeFile(t, root, fmt.Sprintf("report%d.txt", i), "")
+	}
+
+	tests := []struct {
+		query     string
+		want      []string
+		truncated bool
+	}{
+		{"", []string{"/report0.txt", "/report1.txt", "/report2.txt", "/report3.txt", "/report4.txt"}, false},
+		{"&limit=2", []string{"/report0.txt", "/report1.txt"}, true},
+		{"&limit=2&offset=2", []string{"/report2.txt", "/report3.txt"}, true},
+		{"&limit=2&offset=4", []string{"/report4.txt"}, false},
+		{"&offset=9", []string{}, false},
+	}
+	for _, tt := range tests {
+		resp := search(t, h, "/api/search?q=report"+tt.query)
+		got := paths(resp.Results)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: results = %v, want %v", tt.query, got, tt.want)
+		}
+		if resp.Count != len(tt.want) || resp.Total != 5 || resp.Truncated != tt.truncated {
+			t.Errorf("%s: count %d, total %d, truncated %v; want %d, 5, %v", tt.query, resp.Count, resp.Total, resp.Truncated, len(tt.want), tt.truncated)
+		}
+	}
+
+	for _, query := range []string{"&limit=0", "&limit=1001", "&limit=x", "&offset=-1"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/search?q=report"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, rec.Code)
+		}
+	}
+}
+
+func TestSearchFilters(t *testing.T) {
+	h, root := newTestHandler(t)
+	files := []struct {
+		name     string
+		size     int
+		modified time.Time
+	}{
+		{"old-small.txt", 10, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"old-large.txt", 5000, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"new-small.txt", 10, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"new-large.txt", 5000, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"future.txt", 5000, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, f := range files {
+		writeFile(t, root, f.name, strings.Repeat("x", f.size))
+		if err := os.Chtimes(filepath.Join(root, f.name), f.modified, f.modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"&modified_after=2024-01-01T00:00:00Z&modified_before=2025-01-01T00:00:00Z", []string{"/new-large.txt", "/new-small.txt"}},
+		{"&min_size=1024", []string{"/future.txt", "/new-large.txt", "/old-large.txt"}},
+		{"&max_size=100", []string{"/new-small.txt", "/old-small.txt"}},
+		// Filters combine
+		{"&modified_after=2024-01-01T00:00:00Z&min_size=1024", []string{"/future.txt", "/new-large.txt"}},
+	}
+	for _, tt := range tests {
+		got := paths(search(t, h, "/api/search?q=.txt"+tt.query).Results)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: results = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	for _, query := range []string{"&modified_after=yesterday", "&min_size=-1", "&max_size=big"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/search?q=.txt"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package search
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// matcher returns the index of the first match of the search pattern in s, or -1
+type matcher func(s string) int
+
+// newMatcher compiles query for the given mode: "substring" (the default, case-insensitive),
+// "glob" (filepath.Match syntax, case-insensitive) or "regex" (Go regexp syntax)
+func newMatcher(mode, query string) (matcher, error) {
+	switch mode {
+	case "", "substring":
+		lower := strings.ToLower(query)
+		return func(s string) int {
+			return strings.Index(strings.ToLower(s), lower)
+		}, nil
+
+	case "glob":
+		pattern := strings.ToLower(query)
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern: %v", err)
+		}
+		return func(s string) int {
+			if ok, _ := filepath.Match(pattern, strings.ToLower(s)); ok {
+				return 0
+			}
+			return -1
+		}, nil
+
+	case "regex":
+		re, err := regexp.Compile(query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %v", err)
+		}
+		return func(s string) int {
+			if loc := re.FindStringIndex(s); loc != nil {
+				return loc[0]
+			}
+			return -1
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown mode %q (use substring, glob or regex)", mode)
+	}
+}
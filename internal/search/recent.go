@@ -0,0 +1,103 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"simple.http.server/internal/pathutil"
+)
+
+const (
+	defaultRecentLimit = 20              // files returned when no limit is given
+	maxRecentWalk      = 2 * time.Second // the walk stops after this long and uses what it found
+)
+
+// serveRecent returns the most recently modified files below the served directory,
+// newest first. Paths ignored by the file watcher are skipped.
+func (h *Handler) serveRecent(w http.ResponseWriter, r *http.Request) {
+	limit, err := intParam(r, "limit", defaultRecentLimit)
+	if err != nil || limit < 1 || limit > maxLimit {
+		http.Error(w, fmt.Sprintf("Query parameter 'limit' must be between 1 and %d", maxLimit), http.StatusBadRequest)
+		return
+	}
+
+	absBase, err := filepath.Abs(h.config.GetFileServerDir())
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	ignore := h.config.GetWatchIgnore()
+
+	// The full-precision time is kept for sorting; FileInfo only has seconds
+	type recentFile struct {
+		info    FileInfo
+		modTime time.Time
+	}
+	var files []recentFile
+	deadline := time.Now().Add(maxRecentWalk)
+	partial := false
+
+	filepath.WalkDir(absBase, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == absBase {
+			return nil // Skip errors, continue walking
+		}
+		if time.Now().After(deadline) {
+			partial = true
+			return filepath.SkipAll
+		}
+
+		relPath, err := filepath.Rel(absBase, path)
+		if err != nil {
+			return nil
+		}
+		relPath = filepath.ToSlash(relPath)
+		if pathutil.MatchesAny(relPath, ignore) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+		files = append(files, recentFile{
+			info: FileInfo{
+				Name:     info.Name(),
+				Path:     "/" + relPath,
+				Size:     info.Size(),
+				Modified: info.ModTime().Format(time.RFC3339),
+			},
+			modTime: info.ModTime(),
+		})
+		return nil
+	})
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+
+	results := make([]FileInfo, 0, limit)
+	for _, f := range files {
+		if len(results) == limit {
+			break
+		}
+		results = append(results, f.info)
+	}
+
+	if partial {
+		// The directory is too large to walk in time, so newer files may be missing
+		w.Header().Set("X-Search-Partial", "1")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
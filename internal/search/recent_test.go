@@ -0,0 +1,65 @@
+package search
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// recent sends target to h, failing the test unless it succeeds
+func recent(t *testing.T, h *Handler, target string) []FileInfo {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s: status = %d: %s", target, rec.Code, rec.Body)
+	}
+	var files []FileInfo
+	if err := json.NewDecoder(rec.Body).Decode(&files); err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestRecentFiles(t *testing.T) {
+	h, root := newTestHandlerWith(t, map[string]interface{}{"watch_ignore": []string{".git", "*.tmp"}})
+
+	// Each file is an hour newer than the one before, so sub-second precision doesn't matter
+	now := time.Now()
+	for i, name := range []string{"old.txt", "docs/middle.txt", "docs/deep/newer.txt", "newest.txt"} {
+		writeFile(t, root, name, name)
+		modTime := now.Add(time.Duration(i-4) * time.Hour)
+		if err := os.Chtimes(filepath.Join(root, filepath.FromSlash(name)), modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Ignored paths are left out even though they are the most recent
+	writeFile(t, root, ".git/HEAD", "ref")
+	writeFile(t, root, "scratch.tmp", "tmp")
+
+	want := []string{"/newest.txt", "/docs/deep/newer.txt", "/docs/middle.txt", "/old.txt"}
+	files := recent(t, h, "/api/recent")
+	if got := paths(files); !reflect.DeepEqual(got, want) {
+		t.Errorf("recent = %v, want %v", got, want)
+	}
+	if len(files) > 0 && (files[0].Name != "newest.txt" || files[0].Size != int64(len("newest.txt"))) {
+		t.Errorf("first file = %+v, want newest.txt with its size", files[0])
+	}
+
+	if got := paths(recent(t, h, "/api/recent?limit=2")); !reflect.DeepEqual(got, want[:2]) {
+		t.Errorf("recent with limit 2 = %v, want %v", got, want[:2])
+	}
+
+	for _, limit := range []string{"0", "-1", "x"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/recent?limit="+limit, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("limit %q: status = %d, want 400", limit, rec.Code)
+		}
+	}
+}
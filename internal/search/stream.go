@@ -0,0 +1,118 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"simple.http.server/internal/pathutil"
+)
+
+// StreamMatch is a matching line sent by the streaming content search
+type StreamMatch struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	Snippet string `json:"snippet"`
+}
+
+// serveStream searches file contents and sends each matching line as a Server-Sent
+// Event as soon as it is found. A final "done" event reports the number of matches.
+func (h *Handler) serveStream(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		http.Error(w, "Query parameter 'q' is required", http.StatusBadRequest)
+		return
+	}
+
+	match, err := newMatcher(strings.ToLower(r.URL.Query().Get("mode")), query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	searchPath := r.URL.Query().Get("path")
+	if searchPath == "" {
+		searchPath = "/"
+	}
+	absBase, absSearch, err := pathutil.Resolve(h.config.GetFileServerDir(), searchPath)
+	if err != nil {
+		if errors.Is(err, pathutil.ErrOutsideRoot) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+		} else {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	count := 0
+	truncated := false
+	ctx := r.Context()
+
+	// Walk in order rather than in parallel so matches arrive sorted by path
+	filepath.WalkDir(absSearch, func(path string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return filepath.SkipAll
+		}
+		if err != nil || d.IsDir() {
+			return nil // Skip errors, continue walking
+		}
+
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxContentFileSize {
+			return nil
+		}
+		relPath, err := filepath.Rel(absBase, path)
+		if err != nil {
+			return nil
+		}
+		urlPath := "/" + filepath.ToSlash(relPath)
+
+		scanContent(path, match, func(m ContentMatch) bool {
+			if count >= maxMatches {
+				truncated = true
+				return false
+			}
+			count++
+			writeEvent(w, "match", StreamMatch{Path: urlPath, Line: m.Line, Snippet: m.Snippet})
+			flusher.Flush()
+			return ctx.Err() == nil
+		})
+
+		if truncated {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+
+	if ctx.Err() != nil {
+		return
+	}
+	writeEvent(w, "done", map[string]interface{}{
+		"count":     count,
+		"truncated": truncated,
+	})
+	flusher.Flush()
+}
+
+// writeEvent writes v as the JSON data of a named Server-Sent Event
+func writeEvent(w http.ResponseWriter, name string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+}
@@ -0,0 +1,104 @@
+package search
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readStream sends target to a server running h and collects the matches and the final
+// "done" event from the stream
+func readStream(t *testing.T, h *Handler, target string) ([]StreamMatch, map[string]interface{}) {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL + target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: status = %d", target, resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", got)
+	}
+
+	var matches []StreamMatch
+	var done map[string]interface{}
+	event := ""
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data := []byte(strings.TrimPrefix(line, "data: "))
+			if event == "done" {
+				if err := json.Unmarshal(data, &done); err != nil {
+					t.Fatal(err)
+				}
+				return matches, done
+			}
+			var m StreamMatch
+			if err := json.Unmarshal(data, &m); err != nil {
+				t.Fatal(err)
+			}
+			matches = append(matches, m)
+		}
+	}
+	t.Fatalf("stream ended without a done event: %v", scanner.Err())
+	return nil, nil
+}
+
+func TestStreamContentSearch(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeFile(t, root, "a.txt", "needle first\nhay\nanother needle\n")
+	writeFile(t, root, "docs/b.md", "hay\nhay\nNEEDLE in caps\n")
+	writeFile(t, root, "docs/c.txt", "only hay")
+	writeFile(t, root, "image.bin", "needle\x00\x01\x02")
+
+	matches, done := readStream(t, h, "/api/search/stream?q=needle")
+	want := []StreamMatch{
+		{Path: "/a.txt", Line: 1, Snippet: "needle first"},
+		{Path: "/a.txt", Line: 3, Snippet: "another needle"},
+		{Path: "/docs/b.md", Line: 3, Snippet: "NEEDLE in caps"},
+	}
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("matches = %+v, want %+v", matches, want)
+	}
+	if done["count"] != float64(len(want)) || done["truncated"] != false {
+		t.Errorf("done = %v, want count %d and not truncated", done, len(want))
+	}
+
+	// A path narrows the walk and a regex mode is honored
+	matches, _ = readStream(t, h, "/api/search/stream?path=/docs&mode=regex&q=^NEEDLE")
+	if len(matches) != 1 || matches[0].Path != "/docs/b.md" {
+		t.Errorf("regex matches under /docs = %+v, want only /docs/b.md", matches)
+	}
+}
+
+func TestStreamRejectsBadRequests(t *testing.T) {
+	h, _ := newTestHandler(t)
+	tests := []struct {
+		target string
+		want   int
+	}{
+		{"/api/search/stream", http.StatusBadRequest},
+		{"/api/search/stream?q=(&mode=regex", http.StatusBadRequest},
+		{"/api/search/stream?q=x&path=../..", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.target, rec.Code, tt.want)
+		}
+	}
+}
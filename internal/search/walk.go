@@ -0,0 +1,98 @@
+package search
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// searchWorkers is the number of goroutines that stat and match entries while the tree is walked
+const searchWorkers = 8
+
+// entryCheck inspects a walked entry and returns its result if it matches the search
+type entryCheck func(path string, d fs.DirEntry) (FileInfo, bool)
+
+// walkParallel walks root, checking entries concurrently, and returns at most limit
+// matches sorted by path. The walk stops early once limit matches have been found; the
+// matches kept are the first ones in walk order, the same a serial walk would find.
+func walkParallel(root string, limit int, check entryCheck) []FileInfo {
+	type job struct {
+		seq  int // position in walk order
+		path string
+		d    fs.DirEntry
+	}
+	type match struct {
+		seq    int
+		result FileInfo
+	}
+
+	var (
+		mu      sync.Mutex
+		matches []match
+		done    atomic.Bool
+		wg      sync.WaitGroup
+	)
+
+	jobs := make(chan job, searchWorkers*4)
+	for i := 0; i < searchWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Jobs already queued are still checked after the limit is reached, since
+			// they come before any entry the walk has not reached yet
+			for j := range jobs {
+				result, ok := check(j.path, j.d)
+				if !ok {
+					continue
+				}
+
+				mu.Lock()
+				matches = append(matches, match{seq: j.seq, result: result})
+				if len(matches) >= limit {
+					done.Store(true)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	seq := 0
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip errors, continue walking
+		}
+		if done.Load() {
+			return filepath.SkipAll
+		}
+
+		// Skip the root itself
+		if path == root {
+			return nil
+		}
+
+		jobs <- job{seq: seq, path: path, d: d}
+		seq++
+		return nil
+	})
+	close(jobs)
+	wg.Wait()
+
+	// Workers finish in any order: keep the first matches in walk order, then sort
+	// them by path for a stable response
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].seq < matches[j].seq
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	results := make([]FileInfo, len(matches))
+	for i, m := range matches {
+		results[i] = m.result
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Path < results[j].Path
+	})
+	return results
+}
@@ -0,0 +1,117 @@
+package search
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// makeTree creates dirs directories of files files each under a temporary root
+func makeTree(tb testing.TB, dirs, files int) string {
+	tb.Helper()
+	root := tb.TempDir()
+	for d := 0; d < dirs; d++ {
+		dir := filepath.Join(root, fmt.Sprintf("dir%03d", d))
+		if err := os.Mkdir(dir, 0755); err != nil {
+			tb.Fatal(err)
+		}
+		for f := 0; f < files; f++ {
+			name := filepath.Join(dir, fmt.Sprintf("file%03d.txt", f))
+			if err := os.WriteFile(name, nil, 0644); err != nil {
+				tb.Fatal(err)
+			}
+		}
+	}
+	return root
+}
+
+// nameCheck matches entries whose name contains substr, statting them as a search does
+func nameCheck(root, substr string) entryCheck {
+	return func(path string, d fs.DirEntry) (FileInfo, bool) {
+		if !strings.Contains(d.Name(), substr) {
+			return FileInfo{}, false
+		}
+		info, err := d.Info()
+		if err != nil {
+			return FileInfo{}, false
+		}
+		rel, _ := filepath.Rel(root, path)
+		return FileInfo{Name: info.Name(), Path: "/" + filepath.ToSlash(rel), Size: info.Size()}, true
+	}
+}
+
+// walkSerial is the plain walk walkParallel must agree with: the first limit matches in
+// walk order, sorted by path
+func walkSerial(root string, limit int, check entryCheck) []FileInfo {
+	results := []FileInfo{}
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == root {
+			return nil
+		}
+		if len(results) >= limit {
+			return filepath.SkipAll
+		}
+		if result, ok := check(path, d); ok {
+			results = append(results, result)
+		}
+		return nil
+	})
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Path < results[j].Path
+	})
+	return results
+}
+
+func TestWalkParallelMatchesSerialWalk(t *testing.T) {
+	root := makeTree(t, 20, 50)
+	tests := []struct {
+		substr string
+		limit  int
+	}{
+		{"file", 10000}, // every file, no truncation
+		{"file", 37},    // truncated part way through a directory
+		{"file01", 5},
+		{"dir01", 100}, // directories only
+		{"missing", 100},
+	}
+	for _, tt := range tests {
+		check := nameCheck(root, tt.substr)
+		want := walkSerial(root, tt.limit, check)
+
+		// Some entries take longer to check, so workers finish out of walk order
+		slow := func(path string, d fs.DirEntry) (FileInfo, bool) {
+			if strings.HasSuffix(d.Name(), "0.txt") {
+				time.Sleep(time.Millisecond)
+			}
+			return check(path, d)
+		}
+		// Repeat, since a wrong result would depend on scheduling
+		for i := 0; i < 5; i++ {
+			if got := walkParallel(root, tt.limit, slow); !reflect.DeepEqual(got, want) {
+				t.Fatalf("%q limit %d: parallel walk returned %d results differing from the serial walk's %d",
+					tt.substr, tt.limit, len(got), len(want))
+			}
+		}
+	}
+}
+
+func BenchmarkWalk(b *testing.B) {
+	root := makeTree(b, 100, 100) // 10k files
+	check := nameCheck(root, "file")
+	b.Run("serial", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			walkSerial(root, maxMatches, check)
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			walkParallel(root, maxMatches, check)
+		}
+	})
+}
@@ -0,0 +1,284 @@
+package tail
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"simple.http.server/internal/config"
+	"simple.http.server/internal/pathutil"
+)
+
+const (
+	defaultLines = 10                     // lines sent on connect when no count is given
+	maxLines     = 1000                   // largest accepted count
+	pollInterval = 500 * time.Millisecond // how often the file is checked for new data
+	maxReadBytes = 1 << 20                // most bytes read at once, on connect or per poll
+	maxLineBytes = 64 << 10               // longer lines are split
+)
+
+// errNotText is returned for files that cannot be tailed, such as binaries and devices
+var errNotText = errors.New("not a text file")
+
+// Line is the data of a "line" event
+type Line struct {
+	Text string `json:"text"`
+}
+
+// Handler streams lines appended to text files
+type Handler struct {
+	config *config.Config
+}
+
+// NewHandler creates a new tail handler
+func NewHandler(cfg *config.Config) *Handler {
+	return &Handler{config: cfg}
+}
+
+// ServeHTTP sends the last lines of a text file as Server-Sent Events, then each line
+// appended to it. A "reset" event is sent when the file is truncated or replaced, and a
+// "removed" event ends the stream when it is deleted.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// CORS headers
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	lines := defaultLines
+	if value := r.URL.Query().Get("lines"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			http.Error(w, "lines must be a number of at least 0", http.StatusBadRequest)
+			return
+		}
+		lines = min(n, maxLines)
+	}
+
+	filePath := r.URL.Query().Get("path")
+	if filePath == "" {
+		http.Error(w, "Path parameter is required", http.StatusBadRequest)
+		return
+	}
+	_, absPath, err := pathutil.Resolve(h.config.GetFileServerDir(), filePath)
+	if err != nil {
+		if errors.Is(err, pathutil.ErrOutsideRoot) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+		} else {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	file, info, err := openText(absPath)
+	if err != nil {
+		switch {
+		case os.IsNotExist(err):
+			http.Error(w, "File not found", http.StatusNotFound)
+		case errors.Is(err, errNotText):
+			http.Error(w, "Only text files can be tailed", http.StatusBadRequest)
+		default:
+			http.Error(w, "Failed to read file", http.StatusInternalServerError)
+		}
+		return
+	}
+	defer func() { file.Close() }()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	f := &follower{w: w, file: file, info: info}
+	if err := f.sendLast(lines); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	keepAlive := time.NewTicker(h.config.GetSSEKeepAlive())
+	defer keepAlive.Stop()
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprintf(w, ": keep-alive\n\n")
+		case <-poll.C:
+			current, err := os.Stat(absPath)
+			if err != nil {
+				writeEvent(w, "removed", map[string]string{"path": filePath})
+				flusher.Flush()
+				return
+			}
+
+			// A log rotated by renaming has a new file at the same path
+			if !os.SameFile(current, f.info) {
+				replacement, info, err := openText(absPath)
+				if err != nil {
+					continue
+				}
+				file.Close()
+				file = replacement
+				f.restart(replacement, info)
+			} else if current.Size() < f.offset {
+				f.restart(file, current)
+			}
+
+			if err := f.sendNew(current.Size()); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// openText opens a regular file whose contents look like text
+func openText(absPath string) (*os.File, os.FileInfo, error) {
+	file, err := os.Open(absPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	if !info.Mode().IsRegular() {
+		file.Close()
+		return nil, nil, errNotText
+	}
+
+	head := make([]byte, 512)
+	n, err := file.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		file.Close()
+		return nil, nil, err
+	}
+	if !strings.HasPrefix(http.DetectContentType(head[:n]), "text/") {
+		file.Close()
+		return nil, nil, errNotText
+	}
+	return file, info, nil
+}
+
+// follower tracks how much of a file has been sent. A line is sent once it ends with
+// a newline; until then its start is kept in pending.
+type follower struct {
+	w       http.ResponseWriter
+	file    *os.File
+	info    os.FileInfo
+	offset  int64 // bytes of the file read so far
+	pending []byte
+}
+
+// sendLast sends up to n complete lines from the end of the file
+func (f *follower) sendLast(n int) error {
+	size := f.info.Size()
+	start := max(size-maxReadBytes, 0)
+	data := make([]byte, size-start)
+	if _, err := f.file.ReadAt(data, start); err != nil && err != io.EOF {
+		return err
+	}
+	f.offset = size
+
+	// Keep an unfinished last line until the rest of it is written
+	if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+		f.pending = append(f.pending[:0], data[i+1:]...)
+		data = data[:i]
+	} else {
+		f.pending = append(f.pending[:0], data...)
+		return nil
+	}
+	// The first line may have been cut off by the read limit
+	if start > 0 {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+
+	if n == 0 {
+		return nil
+	}
+	lines := strings.Split(string(data), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	for _, line := range lines {
+		writeEvent(f.w, "line", Line{Text: strings.TrimSuffix(line, "\r")})
+	}
+	return nil
+}
+
+// sendNew sends the lines completed by data appended since the last read
+func (f *follower) sendNew(size int64) error {
+	if size <= f.offset {
+		return nil
+	}
+
+	data := make([]byte, min(size-f.offset, maxReadBytes))
+	n, err := f.file.ReadAt(data, f.offset)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	f.offset += int64(n)
+
+	f.pending = append(f.pending, data[:n]...)
+	for {
+		i := bytes.IndexByte(f.pending, '\n')
+		if i < 0 {
+			break
+		}
+		writeEvent(f.w, "line", Line{Text: strings.TrimSuffix(string(f.pending[:i]), "\r")})
+		f.pending = f.pending[i+1:]
+	}
+	for len(f.pending) >= maxLineBytes {
+		writeEvent(f.w, "line", Line{Text: string(f.pending[:maxLineBytes])})
+		f.pending = f.pending[maxLineBytes:]
+	}
+	// Copy what is left so the buffer of a large read is not kept alive
+	f.pending = append([]byte(nil), f.pending...)
+	return nil
+}
+
+// restart reads file again from the start after it was truncated or replaced
+func (f *follower) restart(file *os.File, info os.FileInfo) {
+	f.file = file
+	f.info = info
+	f.offset = 0
+	f.pending = nil
+	writeEvent(f.w, "reset", map[string]string{})
+}
+
+// writeEvent writes v as the JSON data of a named Server-Sent Event
+func writeEvent(w http.ResponseWriter, name string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+}
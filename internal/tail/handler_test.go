@@ -0,0 +1,160 @@
+package tail
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"simple.http.server/internal/config"
+)
+
+// newTestHandler returns a tail handler for a temporary served directory
+func newTestHandler(t *testing.T) (*Handler, string) {
+	t.Helper()
+	root := t.TempDir()
+	settings, err := json.Marshal(map[string]interface{}{"file_server_dir": root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(cfg), root
+}
+
+// event is a Server-Sent Event received from the tail stream
+type event struct {
+	name, data string
+}
+
+// follow connects to target on a server running h and returns the events as they arrive
+func follow(t *testing.T, h *Handler, target string) <-chan event {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	resp, err := http.Get(srv.URL + target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: status = %d", target, resp.StatusCode)
+	}
+
+	events := make(chan event, 100)
+	go func() {
+		defer close(events)
+		name := ""
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if n, ok := strings.CutPrefix(line, "event: "); ok {
+				name = n
+			} else if d, ok := strings.CutPrefix(line, "data: "); ok {
+				events <- event{name, d}
+			}
+		}
+	}()
+	return events
+}
+
+// nextLine waits for the next event, failing unless it is a line with the given text
+func nextLine(t *testing.T, events <-chan event, want string) {
+	t.Helper()
+	select {
+	case e := <-events:
+		var line Line
+		json.Unmarshal([]byte(e.data), &line)
+		if e.name != "line" || line.Text != want {
+			t.Fatalf("got %s event %s, want line %q", e.name, e.data, want)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("no line %q received", want)
+	}
+}
+
+// appendTo appends text to the file at path
+func appendTo(t *testing.T, path, text string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTailDeliversAppendedLines(t *testing.T) {
+	h, root := newTestHandler(t)
+	path := filepath.Join(root, "logs", "app.log")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	var initial strings.Builder
+	for i := 1; i <= 15; i++ {
+		fmt.Fprintf(&initial, "line %d\n", i)
+	}
+	if err := os.WriteFile(path, []byte(initial.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	events := follow(t, h, "/api/tail?path=/logs/app.log&lines=3")
+	for _, want := range []string{"line 13", "line 14", "line 15"} {
+		nextLine(t, events, want)
+	}
+
+	appendTo(t, path, "new entry\n")
+	nextLine(t, events, "new entry")
+
+	// A line is only sent once it is complete
+	appendTo(t, path, "part")
+	time.Sleep(2 * pollInterval)
+	appendTo(t, path, "ial\r\n")
+	nextLine(t, events, "partial")
+
+	// Truncating the file starts it over
+	if err := os.WriteFile(path, []byte("fresh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case e := <-events:
+		if e.name != "reset" {
+			t.Fatalf("got %s event after truncation, want reset", e.name)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("no reset after truncation")
+	}
+	nextLine(t, events, "fresh")
+}
+
+func TestTailRejections(t *testing.T) {
+	h, root := newTestHandler(t)
+	os.WriteFile(filepath.Join(root, "app.log"), []byte("text\n"), 0644)
+	os.WriteFile(filepath.Join(root, "image.bin"), []byte("\x89PNG\r\n\x1a\n\x00\x00"), 0644)
+
+	tests := []struct {
+		target string
+		want   int
+	}{
+		{"/api/tail", http.StatusBadRequest},
+		{"/api/tail?path=app.log&lines=-1", http.StatusBadRequest},
+		{"/api/tail?path=image.bin", http.StatusBadRequest},
+		{"/api/tail?path=missing.log", http.StatusNotFound},
+		{"/api/tail?path=../outside.log", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.target, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package thumbnail
+
+import "sync"
+
+// maxCacheEntries is the number of thumbnails kept in memory
+const maxCacheEntries = 512
+
+// entry is an encoded thumbnail
+type entry struct {
+	data        []byte
+	contentType string
+}
+
+// cache keeps recently rendered thumbnails, evicting the oldest once it is full.
+// Keys include the file's modification time, so edited files get fresh entries.
+type cache struct {
+	mu      sync.Mutex
+	max     int
+	entries map[string]entry
+	order   []string // keys from oldest to newest
+}
+
+// newCache creates a cache holding at most limit thumbnails
+func newCache(limit int) *cache {
+	return &cache{
+		max:     limit,
+		entries: make(map[string]entry),
+	}
+}
+
+// get returns the thumbnail stored under key
+func (c *cache) get(key string) (entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	return e, ok
+}
+
+// put stores a thumbnail under key, evicting the oldest entries beyond the limit
+func (c *cache) put(key string, e entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.entries[key]; !exists {
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = e
+
+	for len(c.order) > c.max {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+}
@@ -0,0 +1,152 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"simple.http.server/internal/config"
+	"simple.http.server/internal/etag"
+	"simple.http.server/internal/pathutil"
+)
+
+const (
+	defaultSize = 128 // longest side in pixels when no size is given
+	minSize     = 16  // smallest accepted size
+	maxSize     = 512 // largest accepted size
+
+	// maxPixels bounds the images decoded, since decoding needs memory for every pixel
+	maxPixels = 40_000_000
+)
+
+// Handler serves scaled-down previews of image files
+type Handler struct {
+	config *config.Config
+	cache  *cache
+}
+
+// NewHandler creates a new thumbnail handler
+func NewHandler(cfg *config.Config) *Handler {
+	return &Handler{
+		config: cfg,
+		cache:  newCache(maxCacheEntries),
+	}
+}
+
+// Supported reports whether a thumbnail can be made for a file with this name
+func Supported(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".jpg", ".jpeg", ".png", ".gif":
+		return true
+	}
+	return false
+}
+
+// ServeHTTP handles thumbnail requests
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filePath := r.URL.Query().Get("path")
+	if filePath == "" {
+		http.Error(w, "Path parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	size := defaultSize
+	if value := r.URL.Query().Get("size"); value != "" {
+		var err error
+		size, err = strconv.Atoi(value)
+		if err != nil || size < minSize || size > maxSize {
+			http.Error(w, "Query parameter 'size' must be between 16 and 512", http.StatusBadRequest)
+			return
+		}
+	}
+
+	_, absFile, err := pathutil.Resolve(h.config.GetFileServerDir(), filePath)
+	if err != nil {
+		if errors.Is(err, pathutil.ErrOutsideRoot) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+		} else {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	info, err := os.Stat(absFile)
+	if err != nil || info.IsDir() {
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	}
+	if !Supported(absFile) {
+		http.Error(w, "Thumbnails are not supported for this file type", http.StatusBadRequest)
+		return
+	}
+
+	// The modification time and size identify the file's version, for both the cache and the ETag
+	version := info.ModTime().String() + "|" + strconv.FormatInt(info.Size(), 10)
+	if etag.Check(w, r, etag.New(absFile, version, strconv.Itoa(size))) {
+		return
+	}
+
+	key := absFile + "|" + version + "|" + strconv.Itoa(size)
+	thumb, ok := h.cache.get(key)
+	if !ok {
+		thumb, err = render(absFile, size)
+		if err != nil {
+			log.Printf("Failed to create thumbnail for %s: %v", absFile, err)
+			http.Error(w, "Cannot create thumbnail: "+err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		h.cache.put(key, thumb)
+	}
+
+	w.Header().Set("Content-Type", thumb.contentType)
+	w.Write(thumb.data)
+}
+
+// render decodes the image at filePath and encodes a copy whose longest side is at most size.
+// PNG and GIF sources become PNG to keep their transparency; everything else becomes JPEG.
+func render(filePath string, size int) (entry, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return entry{}, err
+	}
+	defer file.Close()
+
+	cfg, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return entry{}, err
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return entry{}, errors.New("image is too large")
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		return entry{}, err
+	}
+
+	src, format, err := image.Decode(file)
+	if err != nil {
+		return entry{}, err
+	}
+	scaled := scale(src, size)
+
+	var buf bytes.Buffer
+	if format == "png" || format == "gif" {
+		err = png.Encode(&buf, scaled)
+		return entry{data: buf.Bytes(), contentType: "image/png"}, err
+	}
+	err = jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: 80})
+	return entry{data: buf.Bytes(), contentType: "image/jpeg"}, err
+}
@@ -0,0 +1,130 @@
+package thumbnail
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"simple.http.server/internal/config"
+)
+
+// newTestHandler returns a handler for a temporary served directory
+func newTestHandler(t *testing.T) (*Handler, string) {
+	t.Helper()
+	root := t.TempDir()
+	settings, err := json.Marshal(map[string]interface{}{"file_server_dir": root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(cfg), root
+}
+
+// writeImage saves a width by height gradient under root as name, encoded by its extension
+func writeImage(t *testing.T, root, name string, width, height int) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: uint8(x * y), A: 255})
+		}
+	}
+	f, err := os.Create(filepath.Join(root, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if filepath.Ext(name) == ".png" {
+		err = png.Encode(f, img)
+	} else {
+		err = jpeg.Encode(f, img, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// get requests target from h and returns the recorded response
+func get(h *Handler, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestThumbnailSizes(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeImage(t, root, "wide.png", 400, 200)
+	writeImage(t, root, "tall.jpg", 300, 600)
+	writeImage(t, root, "tiny.png", 20, 10)
+
+	tests := []struct {
+		source, query string
+		contentType   string
+		width, height int
+	}{
+		{"wide.png", "", "image/png", 128, 64},
+		{"wide.png", "&size=50", "image/png", 50, 25},
+		{"tall.jpg", "&size=100", "image/jpeg", 50, 100},
+		{"tiny.png", "", "image/png", 20, 10}, // never enlarged
+	}
+	for _, tt := range tests {
+		target := "/api/thumbnail?path=" + tt.source + tt.query
+		rec := get(h, target)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d: %s", target, rec.Code, rec.Body)
+		}
+		if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+			t.Errorf("%s: Content-Type = %q, want %q", target, got, tt.contentType)
+		}
+		size := rec.Body.Len()
+		img, _, err := image.Decode(rec.Body)
+		if err != nil {
+			t.Fatalf("%s: invalid image: %v", target, err)
+		}
+		if b := img.Bounds(); b.Dx() != tt.width || b.Dy() != tt.height {
+			t.Errorf("%s: thumbnail is %dx%d, want %dx%d", target, b.Dx(), b.Dy(), tt.width, tt.height)
+		}
+		source, err := os.Stat(filepath.Join(root, tt.source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.source != "tiny.png" && int64(size) >= source.Size() {
+			t.Errorf("%s: thumbnail of %d bytes is not smaller than the %d byte source", target, size, source.Size())
+		}
+	}
+}
+
+func TestThumbnailRejections(t *testing.T) {
+	h, root := newTestHandler(t)
+	writeImage(t, root, "photo.png", 40, 40)
+	os.WriteFile(filepath.Join(root, "notes.txt"), []byte("text"), 0644)
+	os.WriteFile(filepath.Join(root, "broken.png"), []byte("not a png"), 0644)
+
+	tests := []struct {
+		target string
+		want   int
+	}{
+		{"/api/thumbnail", http.StatusBadRequest},
+		{"/api/thumbnail?path=photo.png&size=8", http.StatusBadRequest},
+		{"/api/thumbnail?path=photo.png&size=big", http.StatusBadRequest},
+		{"/api/thumbnail?path=notes.txt", http.StatusBadRequest},
+		{"/api/thumbnail?path=missing.png", http.StatusNotFound},
+		{"/api/thumbnail?path=../outside.png", http.StatusForbidden},
+		{"/api/thumbnail?path=broken.png", http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		if rec := get(h, tt.target); rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.target, rec.Code, tt.want)
+		}
+	}
+}
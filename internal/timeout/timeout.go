@@ -0,0 +1,119 @@
+package timeout
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"simple.http.server/internal/config"
+)
+
+// NewServer creates a server for handler using the timeouts configured in cfg, so slow
+// clients cannot hold connections open indefinitely. Handlers wrapped with Exempt are
+// not limited by the read and write timeouts, and those wrapped with Extend only while
+// they make progress.
+func NewServer(cfg *config.Config, handler http.Handler) *http.Server {
+	t := cfg.GetServerTimeouts()
+	return &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: seconds(t.ReadHeader),
+		ReadTimeout:       seconds(t.Read),
+		WriteTimeout:      seconds(t.Write),
+		IdleTimeout:       seconds(t.Idle),
+	}
+}
+
+// Exempt lifts the read and write deadlines for next, which streams events or transfers
+// large files and may legitimately run longer than the server's timeouts. A read timeout
+// would otherwise cancel the request's context once it expires, even after the body is read.
+func Exempt(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rc := http.NewResponseController(w)
+		// Errors only mean the connection has no deadlines to lift
+		rc.SetReadDeadline(time.Time{})
+		rc.SetWriteDeadline(time.Time{})
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Extend keeps the read and write deadlines for next while moving them forward as it
+// makes progress: each read of the request body and each write of the response restarts
+// the server's timeouts. Large downloads and proxied bodies can take as long as they need,
+// while a client that stops reading or sending is still cut off.
+func Extend(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv, _ := r.Context().Value(http.ServerContextKey).(*http.Server)
+		if srv == nil || (srv.ReadTimeout == 0 && srv.WriteTimeout == 0) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ew := &extendingWriter{ResponseWriter: w, rc: http.NewResponseController(w), read: srv.ReadTimeout, write: srv.WriteTimeout}
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = &extendingBody{ReadCloser: r.Body, w: ew}
+		}
+		next.ServeHTTP(ew, r)
+	})
+}
+
+// extendingWriter restarts the connection's deadlines before each write
+type extendingWriter struct {
+	http.ResponseWriter
+	rc          *http.ResponseController
+	read, write time.Duration // 0 leaves that deadline alone
+}
+
+// extend moves the deadlines the server set forward from now. The read deadline moves
+// too, as it would otherwise cancel the request's context once it expires.
+func (w *extendingWriter) extend() {
+	now := time.Now()
+	if w.read > 0 {
+		w.rc.SetReadDeadline(now.Add(w.read))
+	}
+	if w.write > 0 {
+		w.rc.SetWriteDeadline(now.Add(w.write))
+	}
+}
+
+func (w *extendingWriter) Write(b []byte) (int, error) {
+	w.extend()
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush passes flushes through, as Server-Sent Event handlers check for http.Flusher directly
+func (w *extendingWriter) Flush() {
+	w.extend()
+	w.rc.Flush()
+}
+
+// Hijack lifts the deadlines on a hijacked connection, such as a proxied WebSocket,
+// which the server no longer manages
+func (w *extendingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := w.rc.Hijack()
+	if err == nil {
+		conn.SetDeadline(time.Time{})
+	}
+	return conn, rw, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *extendingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// extendingBody restarts the connection's deadlines before each read of a request body
+type extendingBody struct {
+	io.ReadCloser
+	w *extendingWriter
+}
+
+func (b *extendingBody) Read(p []byte) (int, error) {
+	b.w.extend()
+	return b.ReadCloser.Read(p)
+}
+
+// seconds converts a timeout in seconds to a duration; 0 leaves the timeout disabled
+func seconds(s int) time.Duration {
+	return time.Duration(s) * time.Second
+}
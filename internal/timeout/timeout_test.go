@@ -0,0 +1,178 @@
+package timeout
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"simple.http.server/internal/config"
+)
+
+// startServer serves handler with one second read header and write timeouts
+func startServer(t *testing.T, handler http.Handler) *httptest.Server {
+	t.Helper()
+	return startServerWith(t, `{"read_header_timeout": 1, "write_timeout": 1}`, handler)
+}
+
+// startServerWith serves handler with the timeouts in settings
+func startServerWith(t *testing.T, settings string, handler http.Handler) *httptest.Server {
+	t.Helper()
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings([]byte(settings)); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewUnstartedServer(nil)
+	srv.Config = NewServer(cfg, handler)
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSlowHeadersAreCutOff(t *testing.T) {
+	srv := startServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	// Start a request and never finish its headers
+	io.WriteString(conn, "GET / HTTP/1.1\r\nHost: example\r\n")
+
+	start := time.Now()
+	conn.SetReadDeadline(start.Add(5 * time.Second))
+	io.Copy(io.Discard, conn)
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("connection stayed open %v, want it closed after the 1s header timeout", elapsed)
+	}
+}
+
+// streamEvents sends an event every 200ms for 1.6s, longer than the write timeout
+func streamEvents(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	for i := 0; i < 8; i++ {
+		fmt.Fprintf(w, "data: %d\n\n", i)
+		w.(http.Flusher).Flush()
+		time.Sleep(200 * time.Millisecond)
+	}
+	io.WriteString(w, "data: done\n\n")
+}
+
+// readEvents returns the data lines received from target until the stream ends
+func readEvents(t *testing.T, target string) []string {
+	t.Helper()
+	resp, err := http.Get(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var data []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if d, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			data = append(data, d)
+		}
+	}
+	return data
+}
+
+func TestExemptStreamOutlivesWriteTimeout(t *testing.T) {
+	srv := startServer(t, Exempt(http.HandlerFunc(streamEvents)))
+	if data := readEvents(t, srv.URL); len(data) == 0 || data[len(data)-1] != "done" {
+		t.Errorf("exempt stream received %v, want it to run to the end", data)
+	}
+
+	// Without the exemption the same stream is cut off
+	limited := startServer(t, http.HandlerFunc(streamEvents))
+	if data := readEvents(t, limited.URL); len(data) > 0 && data[len(data)-1] == "done" {
+		t.Error("stream outlived the write timeout without being exempt")
+	}
+}
+
+func TestExtendedStreamOutlivesWriteTimeout(t *testing.T) {
+	srv := startServer(t, Extend(http.HandlerFunc(streamEvents)))
+	if data := readEvents(t, srv.URL); len(data) == 0 || data[len(data)-1] != "done" {
+		t.Errorf("stream received %v, want each write to restart the timeout", data)
+	}
+}
+
+func TestExtendStillCutsOffStalledClients(t *testing.T) {
+	// Write far more than the connection buffers to a client that reads nothing
+	failed := make(chan error, 1)
+	srv := startServer(t, Extend(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunk := make([]byte, 32<<10)
+		for i := 0; i < 2048; i++ {
+			if _, err := w.Write(chunk); err != nil {
+				failed <- err
+				return
+			}
+		}
+		failed <- nil
+	})))
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	io.WriteString(conn, "GET / HTTP/1.1\r\nHost: example\r\n\r\n")
+
+	select {
+	case err := <-failed:
+		if err == nil {
+			t.Error("the whole response was written to a client that read nothing")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("writes to a stalled client were not cut off by the write timeout")
+	}
+}
+
+// slowUpload sends a body in 8 parts, 200ms apart, for longer than the read timeout, and
+// returns the response body
+func slowUpload(t *testing.T, target string) string {
+	t.Helper()
+	body, pw := io.Pipe()
+	go func() {
+		for i := 0; i < 8; i++ {
+			io.WriteString(pw, "part ")
+			time.Sleep(200 * time.Millisecond)
+		}
+		pw.Close()
+	}()
+	resp, err := http.Post(target, "text/plain", body)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return string(data)
+}
+
+func TestExtendedUploadOutlivesReadTimeout(t *testing.T) {
+	countBytes := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, err := io.Copy(io.Discard, r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestTimeout)
+			return
+		}
+		fmt.Fprint(w, n)
+	})
+	settings := `{"read_header_timeout": 1, "read_timeout": 1}`
+
+	srv := startServerWith(t, settings, Extend(countBytes))
+	if got := slowUpload(t, srv.URL); got != "40" {
+		t.Errorf("received %q, want all 40 bytes read", got)
+	}
+
+	// Without the extension the same upload is cut off
+	limited := startServerWith(t, settings, countBytes)
+	if got := slowUpload(t, limited.URL); got == "40" {
+		t.Error("upload outlived the read timeout without being extended")
+	}
+}
@@ -0,0 +1,38 @@
+package tlsutil
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"simple.http.server/internal/netutil"
+)
+
+func TestGenerateSelfSignedHosts(t *testing.T) {
+	// The server passes the detected LAN address, as main does
+	hosts := []string{"localhost", "127.0.0.1", "::1", "192.168.1.50", "files.lan"}
+	if ip := netutil.LocalIP(); ip != "" {
+		hosts = append(hosts, ip)
+	}
+	cert, err := GenerateSelfSigned("192.168.1.50", "files.lan", netutil.LocalIP())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert.Leaf)
+	for _, host := range hosts {
+		if _, err := cert.Leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: roots}); err != nil {
+			t.Errorf("certificate is not valid for %s: %v", host, err)
+		}
+	}
+	for _, host := range []string{"example.com", "192.168.1.51"} {
+		if err := cert.Leaf.VerifyHostname(host); err == nil {
+			t.Errorf("certificate is valid for %s", host)
+		}
+	}
+
+	if now := time.Now(); now.Before(cert.Leaf.NotBefore) || now.After(cert.Leaf.NotAfter) {
+		t.Errorf("certificate is not valid now: %s to %s", cert.Leaf.NotBefore, cert.Leaf.NotAfter)
+	}
+}
@@ -0,0 +1,383 @@
+package upload
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"simple.http.server/internal/pathutil"
+)
+
+const (
+	maxChunkSize = 64 << 20 // 64 MB
+	maxChunks    = 10000
+
+	// maxPendingUploads caps how many chunked uploads can be in progress at once
+	maxPendingUploads = 100
+	// staleUploadAge is how long an unfinished chunked upload is kept after its last chunk
+	staleUploadAge = 24 * time.Hour
+)
+
+// errTooManyUploads is returned when a chunked upload cannot start because too many are in progress
+var errTooManyUploads = errors.New("too many uploads in progress")
+
+// uploadIDPattern restricts upload IDs to names that are safe as directory names
+var uploadIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// chunkMeta is stored next to the parts of a chunked upload
+type chunkMeta struct {
+	Filename string `json:"filename"`
+	Path     string `json:"path"`
+	Total    int    `json:"total"`
+}
+
+// chunkRoot returns the temporary directory holding the parts of unfinished uploads
+func chunkRoot() string {
+	return filepath.Join(os.TempDir(), "simple-http-server-uploads")
+}
+
+// chunkDir returns the temporary directory holding the parts of an upload
+func chunkDir(id string) string {
+	return filepath.Join(chunkRoot(), id)
+}
+
+// prepareChunkDir creates dir for the parts of an upload unless it already exists.
+// Stale uploads are removed first, and a new upload is refused when too many are
+// already in progress. The caller must hold h.mu.
+func prepareChunkDir(dir string) error {
+	if _, err := os.Stat(dir); err == nil {
+		return nil
+	}
+	if removeStaleUploads(time.Now()) >= maxPendingUploads {
+		return errTooManyUploads
+	}
+	return os.MkdirAll(dir, 0755)
+}
+
+// removeStaleUploads deletes unfinished uploads whose last chunk arrived more than
+// staleUploadAge before now, and returns how many uploads are still in progress
+func removeStaleUploads(now time.Time) int {
+	entries, err := os.ReadDir(chunkRoot())
+	if err != nil {
+		return 0
+	}
+	pending := 0
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		// meta.json is rewritten with every chunk; without it the upload never stored one
+		dir := filepath.Join(chunkRoot(), entry.Name())
+		info, err := os.Stat(filepath.Join(dir, "meta.json"))
+		if err != nil {
+			info, err = entry.Info()
+		}
+		if err == nil && now.Sub(info.ModTime()) > staleUploadAge {
+			log.Printf("Removing unfinished upload %s", entry.Name())
+			os.RemoveAll(dir)
+			continue
+		}
+		pending++
+	}
+	return pending
+}
+
+// handleChunk stores one chunk of a chunked upload and assembles the file once
+// every chunk has arrived. Chunks may be sent in any order and resent after a failure.
+//
+// The chunk is the raw request body; these headers describe it:
+//
+//	X-Upload-Id     client-chosen ID shared by all chunks of a file
+//	X-Chunk-Index   zero-based index of this chunk
+//	X-Total-Chunks  number of chunks in the file
+//	X-File-Name     URL-encoded file name
+//	X-Upload-Path   URL-encoded target directory, relative to the server root (default "/")
+//	X-Overwrite     "true" to replace an existing file instead of renaming the upload
+func (h *Handler) handleChunk(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get("X-Upload-Id")
+	if !uploadIDPattern.MatchString(id) {
+		http.Error(w, "Invalid or missing X-Upload-Id", http.StatusBadRequest)
+		return
+	}
+
+	index, err := strconv.Atoi(r.Header.Get("X-Chunk-Index"))
+	if err != nil || index < 0 {
+		http.Error(w, "Invalid or missing X-Chunk-Index", http.StatusBadRequest)
+		return
+	}
+	total, err := strconv.Atoi(r.Header.Get("X-Total-Chunks"))
+	if err != nil || total < 1 || total > maxChunks || index >= total {
+		http.Error(w, "Invalid or missing X-Total-Chunks", http.StatusBadRequest)
+		return
+	}
+
+	rawName, err := url.PathUnescape(r.Header.Get("X-File-Name"))
+	if err != nil {
+		http.Error(w, "Invalid X-File-Name", http.StatusBadRequest)
+		return
+	}
+	filename := filepath.Base(filepath.Clean(rawName))
+	if rawName == "" || filename == "." || filename == ".." || filename == string(filepath.Separator) {
+		http.Error(w, "Invalid or missing X-File-Name", http.StatusBadRequest)
+		return
+	}
+
+	// Clients split a file into equal chunks, so any chunk but the last shows whether
+	// the whole file can fit within the upload limit before it is stored
+	maxBytes := h.config.GetMaxUploadBytes()
+	if r.ContentLength > maxBytes || (index < total-1 && r.ContentLength > 0 && int64(total-1)*r.ContentLength > maxBytes) {
+		rejectUpload(w, &http.MaxBytesError{Limit: maxBytes}, maxBytes)
+		return
+	}
+
+	// Reject disallowed extensions before storing anything; content is checked on assembly
+	allow, deny := h.config.GetUploadExtensions()
+	if err := checkExtension(filename, nil, allow, deny); err != nil {
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	uploadPath, err := url.PathUnescape(r.Header.Get("X-Upload-Path"))
+	if err != nil {
+		http.Error(w, "Invalid X-Upload-Path", http.StatusBadRequest)
+		return
+	}
+	if uploadPath == "" {
+		uploadPath = "/"
+	}
+
+	// Security: verify the target directory is within the served directory
+	_, absUpload, err := pathutil.Resolve(h.config.GetFileServerDir(), uploadPath)
+	if err == pathutil.ErrOutsideRoot {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Write the part to a temporary name first so a dropped connection never leaves a short part
+	dir := chunkDir(id)
+	h.mu.Lock()
+	err = prepareChunkDir(dir)
+	h.mu.Unlock()
+	if err == errTooManyUploads {
+		http.Error(w, "Too many uploads in progress, try again later", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to store chunk", http.StatusInternalServerError)
+		return
+	}
+	partPath := filepath.Join(dir, fmt.Sprintf("%d.part", index))
+	tmp, err := os.CreateTemp(dir, "incoming-*")
+	if err != nil {
+		http.Error(w, "Failed to store chunk", http.StatusInternalServerError)
+		return
+	}
+	_, err = io.Copy(tmp, http.MaxBytesReader(w, r.Body, min(maxChunkSize, maxBytes)))
+	tmp.Close()
+	if err != nil {
+		os.Remove(tmp.Name())
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) && tooLarge.Limit == maxBytes {
+			rejectUpload(w, err, maxBytes)
+			return
+		}
+		http.Error(w, "Failed to read chunk", http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := os.Rename(tmp.Name(), partPath); err != nil {
+		os.Remove(tmp.Name())
+		http.Error(w, "Failed to store chunk", http.StatusInternalServerError)
+		return
+	}
+
+	// The chunks stored so far already exceed the limit: the file can never be assembled
+	if storedSize(dir) > maxBytes {
+		os.RemoveAll(dir)
+		rejectUpload(w, &http.MaxBytesError{Limit: maxBytes}, maxBytes)
+		return
+	}
+
+	meta := chunkMeta{Filename: filename, Path: uploadPath, Total: total}
+	if data, err := json.Marshal(meta); err == nil {
+		os.WriteFile(filepath.Join(dir, "meta.json"), data, 0644)
+	}
+
+	received := receivedChunks(dir)
+	if len(received) < total {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":       id,
+			"received": received,
+			"total":    total,
+			"complete": false,
+		})
+		return
+	}
+
+	// Every chunk is present: assemble the file
+	if err := checkExtension(filename, readHead(filepath.Join(dir, "0.part")), allow, deny); err != nil {
+		os.RemoveAll(dir)
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+	if err := os.MkdirAll(absUpload, 0755); err != nil {
+		http.Error(w, "Failed to create upload directory", http.StatusInternalServerError)
+		return
+	}
+	overwrite := r.Header.Get("X-Overwrite") == "true"
+	destPath, name, status, written, err := assembleChunks(dir, absUpload, filename, total, overwrite)
+	if err != nil {
+		log.Printf("Failed to assemble upload %s: %v", id, err)
+		http.Error(w, "Failed to assemble file", http.StatusInternalServerError)
+		return
+	}
+	os.RemoveAll(dir)
+
+	log.Printf("Uploaded: %s (%d bytes in %d chunks) to %s", name, written, total, filepath.Dir(destPath))
+	h.metrics.UploadCompleted()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":       id,
+		"files":    []FileStatus{{Original: filename, Saved: name, Size: written, Status: status}},
+		"count":    1,
+		"complete": true,
+	})
+}
+
+// handleStatus reports which chunks of an upload have been received, so a client can resume
+func (h *Handler) handleStatus(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if !uploadIDPattern.MatchString(id) {
+		http.Error(w, "Invalid or missing id", http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	dir := chunkDir(id)
+	var meta chunkMeta
+	if data, err := os.ReadFile(filepath.Join(dir, "meta.json")); err == nil {
+		json.Unmarshal(data, &meta)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":       id,
+		"filename": meta.Filename,
+		"received": receivedChunks(dir),
+		"total":    meta.Total,
+	})
+}
+
+// receivedChunks returns the sorted indexes of the parts stored in dir
+func receivedChunks(dir string) []int {
+	received := []int{}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return received
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".part") {
+			continue
+		}
+		if index, err := strconv.Atoi(strings.TrimSuffix(name, ".part")); err == nil {
+			received = append(received, index)
+		}
+	}
+	sort.Ints(received)
+	return received
+}
+
+// storedSize returns the combined size of the parts stored in dir
+func storedSize(dir string) int64 {
+	var size int64
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".part") {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			size += info.Size()
+		}
+	}
+	return size
+}
+
+// assembleChunks concatenates the parts in dir, in order, into a file in destDir
+func assembleChunks(dir, destDir, filename string, total int, overwrite bool) (destPath, name, status string, written int64, err error) {
+	for i := 0; i < total; i++ {
+		if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf("%d.part", i))); err != nil {
+			return "", "", "", 0, err
+		}
+	}
+
+	tmpPath, written, err := stage(destDir, func(dst io.Writer) (int64, error) {
+		var written int64
+		for i := 0; i < total; i++ {
+			n, err := appendFile(dst, filepath.Join(dir, fmt.Sprintf("%d.part", i)))
+			written += n
+			if err != nil {
+				return written, err
+			}
+		}
+		return written, nil
+	})
+	if err != nil {
+		return "", "", "", 0, err
+	}
+	destPath, name, status, err = moveIntoPlace(tmpPath, destDir, filename, overwrite)
+	if err != nil {
+		os.Remove(tmpPath)
+		return "", "", "", 0, err
+	}
+	return destPath, name, status, written, nil
+}
+
+// readHead returns up to the first 512 bytes of the file at path
+func readHead(path string) []byte {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(f, head)
+	return head[:n]
+}
+
+// appendFile copies the file at path to dst
+func appendFile(dst io.Writer, path string) (int64, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+	return io.Copy(dst, src)
+}
@@ -0,0 +1,247 @@
+package upload
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// chunkRequest builds one chunk of a chunked upload of name
+func chunkRequest(id, name string, index, total int, data string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/upload/chunk", strings.NewReader(data))
+	req.Header.Set("X-Upload-Id", id)
+	req.Header.Set("X-Chunk-Index", strconv.Itoa(index))
+	req.Header.Set("X-Total-Chunks", strconv.Itoa(total))
+	req.Header.Set("X-File-Name", name)
+	return req
+}
+
+func TestChunkedUploadDeclaredSizeOverLimit(t *testing.T) {
+	h, _ := newTestHandler(t, 100)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, chunkRequest("big", "big.txt", 0, 5, strings.Repeat("x", 40)))
+	assertTooLarge(t, rec, 100)
+	if _, err := os.Stat(chunkDir("big")); !os.IsNotExist(err) {
+		t.Errorf("chunk was stored: %v", err)
+	}
+}
+
+func TestChunkedUploadChunkOverLimit(t *testing.T) {
+	h, _ := newTestHandler(t, 100)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, chunkRequest("one", "one.txt", 0, 1, strings.Repeat("x", 150)))
+	assertTooLarge(t, rec, 100)
+}
+
+func TestChunkedUploadAssembledSizeOverLimit(t *testing.T) {
+	h, root := newTestHandler(t, 100)
+	chunks := []string{strings.Repeat("a", 40), strings.Repeat("b", 40), strings.Repeat("c", 60)}
+	for i, data := range chunks[:2] {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, chunkRequest("grow", "grow.txt", i, len(chunks), data))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("chunk %d: status = %d: %s", i, rec.Code, rec.Body)
+		}
+	}
+
+	// The last chunk is within the limit itself but takes the file past it
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, chunkRequest("grow", "grow.txt", 2, len(chunks), chunks[2]))
+	assertTooLarge(t, rec, 100)
+	if _, err := os.Stat(chunkDir("grow")); !os.IsNotExist(err) {
+		t.Errorf("chunks were kept: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "grow.txt")); !os.IsNotExist(err) {
+		t.Errorf("oversized file was assembled: %v", err)
+	}
+}
+
+func TestChunkedUploadUnderLimit(t *testing.T) {
+	h, root := newTestHandler(t, 100)
+	chunks := []string{"hello ", "chunked ", "world"}
+	for i, data := range chunks {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, chunkRequest("small", "small.txt", i, len(chunks), data))
+		want := http.StatusOK
+		if i == len(chunks)-1 {
+			want = http.StatusCreated
+		}
+		if rec.Code != want {
+			t.Fatalf("chunk %d: status = %d, want %d: %s", i, rec.Code, want, rec.Body)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(root, "small.txt"))
+	if err != nil || string(data) != "hello chunked world" {
+		t.Fatalf("assembled file = %q, %v", data, err)
+	}
+}
+
+func TestChunkedUploadOutOfOrder(t *testing.T) {
+	h, root := newTestHandler(t, 1024)
+	chunks := []string{"first-", "second-", "third"}
+
+	// Send the last chunk first, then the first, then the middle one
+	for n, index := range []int{2, 0, 1} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, chunkRequest("order", "ordered.txt", index, len(chunks), chunks[index]))
+		if n < 2 {
+			if rec.Code != http.StatusOK {
+				t.Fatalf("chunk %d: status = %d: %s", index, rec.Code, rec.Body)
+			}
+			continue
+		}
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("final chunk %d: status = %d, want 201: %s", index, rec.Code, rec.Body)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "ordered.txt"))
+	if err != nil || string(data) != "first-second-third" {
+		t.Fatalf("assembled file = %q, %v", data, err)
+	}
+	if _, err := os.Stat(chunkDir("order")); !os.IsNotExist(err) {
+		t.Errorf("chunks were not cleaned up: %v", err)
+	}
+}
+
+func TestChunkedUploadStatusAllowsResume(t *testing.T) {
+	h, root := newTestHandler(t, 1024)
+	chunks := []string{"aaa", "bbb", "ccc"}
+	for _, index := range []int{0, 2} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, chunkRequest("resume", "resumed.txt", index, len(chunks), chunks[index]))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("chunk %d: status = %d: %s", index, rec.Code, rec.Body)
+		}
+	}
+	// A chunk sent again after a failure replaces the earlier copy
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, chunkRequest("resume", "resumed.txt", 0, len(chunks), chunks[0]))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("resent chunk: status = %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/upload/status?id=resume", nil))
+	var status struct {
+		Filename string `json:"filename"`
+		Received []int  `json:"received"`
+		Total    int    `json:"total"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Filename != "resumed.txt" || status.Total != 3 || !reflect.DeepEqual(status.Received, []int{0, 2}) {
+		t.Fatalf("status = %+v, want chunks 0 and 2 of 3 for resumed.txt", status)
+	}
+
+	// Sending the missing chunk completes the upload
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, chunkRequest("resume", "resumed.txt", 1, len(chunks), chunks[1]))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("missing chunk: status = %d, want 201: %s", rec.Code, rec.Body)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "resumed.txt"))
+	if err != nil || string(data) != "aaabbbccc" {
+		t.Fatalf("assembled file = %q, %v", data, err)
+	}
+}
+
+// sendChunk sends the first of two chunks of upload id and returns the response status
+func sendChunk(h *Handler, id string) int {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, chunkRequest(id, id+".txt", 0, 2, "data"))
+	return rec.Code
+}
+
+// age makes the upload id look as if its last chunk arrived d ago
+func age(t *testing.T, id string, d time.Duration) {
+	t.Helper()
+	then := time.Now().Add(-d)
+	for _, path := range []string{filepath.Join(chunkDir(id), "meta.json"), chunkDir(id)} {
+		if err := os.Chtimes(path, then, then); err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStaleChunkedUploadsExpire(t *testing.T) {
+	h, _ := newTestHandler(t, 1024)
+	for _, id := range []string{"old", "recent"} {
+		if code := sendChunk(h, id); code != http.StatusOK {
+			t.Fatalf("%s: status = %d", id, code)
+		}
+	}
+	age(t, "old", staleUploadAge+time.Hour)
+	age(t, "recent", staleUploadAge-time.Hour)
+
+	// Starting another upload clears out the abandoned one
+	if code := sendChunk(h, "new"); code != http.StatusOK {
+		t.Fatalf("new: status = %d", code)
+	}
+	if _, err := os.Stat(chunkDir("old")); !os.IsNotExist(err) {
+		t.Errorf("stale upload was kept: %v", err)
+	}
+	for _, id := range []string{"recent", "new"} {
+		if _, err := os.Stat(filepath.Join(chunkDir(id), "0.part")); err != nil {
+			t.Errorf("%s: upload in progress was removed: %v", id, err)
+		}
+	}
+}
+
+func TestPendingChunkedUploadsAreCapped(t *testing.T) {
+	h, _ := newTestHandler(t, 1024)
+	for i := 0; i < maxPendingUploads; i++ {
+		if err := os.MkdirAll(chunkDir(fmt.Sprintf("pending%d", i)), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if code := sendChunk(h, "extra"); code != http.StatusServiceUnavailable {
+		t.Errorf("upload past the cap: status = %d, want 503", code)
+	}
+	if _, err := os.Stat(chunkDir("extra")); !os.IsNotExist(err) {
+		t.Errorf("refused upload left a directory behind: %v", err)
+	}
+	// Uploads already in progress can go on
+	if code := sendChunk(h, "pending0"); code != http.StatusOK {
+		t.Errorf("upload in progress: status = %d, want 200", code)
+	}
+
+	// Once one has gone stale there is room again
+	age(t, "pending1", staleUploadAge+time.Hour)
+	if code := sendChunk(h, "extra"); code != http.StatusOK {
+		t.Errorf("upload after a stale one expired: status = %d, want 200", code)
+	}
+}
+
+func TestChunkedUploadRejectsInvalidRequests(t *testing.T) {
+	h, _ := newTestHandler(t, 1024)
+	tests := []struct {
+		name   string
+		modify func(*http.Request)
+		want   int
+	}{
+		{"missing id", func(r *http.Request) { r.Header.Del("X-Upload-Id") }, http.StatusBadRequest},
+		{"id with a path", func(r *http.Request) { r.Header.Set("X-Upload-Id", "../up") }, http.StatusBadRequest},
+		{"index past total", func(r *http.Request) { r.Header.Set("X-Chunk-Index", "3") }, http.StatusBadRequest},
+		{"target outside root", func(r *http.Request) { r.Header.Set("X-Upload-Path", "..%2F..%2Fetc") }, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := chunkRequest("bad", "bad.txt", 0, 3, "data")
+		tt.modify(req)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package upload
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// sniffedTypes maps extensions to the content type http.DetectContentType reports for them,
+// so a file whose contents do not match its extension can be rejected
+var sniffedTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".bmp":  "image/bmp",
+	".pdf":  "application/pdf",
+	".zip":  "application/zip",
+	".gz":   "application/x-gzip",
+	".mp3":  "audio/mpeg",
+	".wav":  "audio/wave",
+	".ogg":  "application/ogg",
+	".webm": "video/webm",
+}
+
+// executableMagic starts Windows executables (the "MZ" DOS header), whatever their name
+var executableMagic = []byte("MZ")
+
+// checkExtension reports why a file may not be uploaded, or nil if it may.
+// allow and deny hold lowercase extensions with a leading dot; an empty allow list permits
+// every extension that is not denied. head is the start of the file's content.
+func checkExtension(filename string, head []byte, allow, deny []string) error {
+	ext := strings.ToLower(filepath.Ext(filename))
+
+	if contains(deny, ext) {
+		return fmt.Errorf("file type %s is not allowed", ext)
+	}
+	if len(allow) > 0 && !contains(allow, ext) {
+		if ext == "" {
+			return fmt.Errorf("files without an extension are not allowed")
+		}
+		return fmt.Errorf("file type %s is not allowed", ext)
+	}
+
+	if head == nil {
+		return nil
+	}
+
+	// A denied executable renamed to an innocent extension is still an executable
+	if contains(deny, ".exe") && bytes.HasPrefix(head, executableMagic) {
+		return fmt.Errorf("content is a Windows executable")
+	}
+
+	if expected, ok := sniffedTypes[ext]; ok {
+		if detected := http.DetectContentType(head); !strings.HasPrefix(detected, expected) {
+			return fmt.Errorf("content (%s) does not match the %s extension", detected, ext)
+		}
+	}
+	return nil
+}
+
+// contains reports whether list holds ext
+func contains(list []string, ext string) bool {
+	for _, e := range list {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
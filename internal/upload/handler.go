@@ -1,25 +1,35 @@
 package upload
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 
 	"simple.http.server/internal/config"
-)
-
-const (
-	maxUploadSize = 500 << 20 // 500 MB
+	"simple.http.server/internal/format"
+	"simple.http.server/internal/metrics"
+	"simple.http.server/internal/pathutil"
 )
 
 // Handler manages file uploads
 type Handler struct {
-	config *config.Config
+	config   *config.Config
+	mu       sync.Mutex       // serializes chunk bookkeeping and assembly
+	progress ProgressReporter // optional, see SetProgressReporter
+	metrics  *metrics.Metrics // optional, see SetMetrics
 }
 
 // NewHandler creates a new upload handler
@@ -27,137 +37,402 @@ func NewHandler(cfg *config.Config) *Handler {
 	return &Handler{config: cfg}
 }
 
-// ServeHTTP handles file upload requests
+// SetMetrics sets where completed uploads are counted
+func (h *Handler) SetMetrics(m *metrics.Metrics) {
+	h.metrics = m
+}
+
+// ServeHTTP routes upload requests
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// CORS headers
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Upload-Id, X-Chunk-Index, X-Total-Chunks, X-File-Name, X-Upload-Path, X-Overwrite")
 
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
-	if r.Method != http.MethodPost {
+	switch {
+	case r.URL.Path == "/api/upload" && r.Method == http.MethodPost:
+		h.handleUpload(w, r)
+	case r.URL.Path == "/api/upload/chunk" && r.Method == http.MethodPost:
+		h.handleChunk(w, r)
+	case r.URL.Path == "/api/upload/url" && r.Method == http.MethodPost:
+		h.handleURL(w, r)
+	case r.URL.Path == "/api/upload/status" && r.Method == http.MethodGet:
+		h.handleStatus(w, r)
+	case r.URL.Path == "/api/upload" || r.URL.Path == "/api/upload/chunk" || r.URL.Path == "/api/upload/url" || r.URL.Path == "/api/upload/status":
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	// Parse multipart form with size limit
-	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
-	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
-		http.Error(w, "File too large", http.StatusBadRequest)
-		return
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
 	}
+}
 
-	// Get upload path (relative to server root)
-	uploadPath := r.FormValue("path")
-	if uploadPath == "" {
-		uploadPath = "/"
-	}
+// maxFieldSize is the largest non-file form value read from an upload
+const maxFieldSize = 1 << 20 // 1 MB
 
-	// Get base directory and construct full upload path
-	baseDir := h.config.GetFileServerDir()
-	fullPath := filepath.Join(baseDir, filepath.Clean(uploadPath))
+// stagedFile is an uploaded file written to a temporary file in the upload directory,
+// waiting to be moved to its final name once the whole request has been read
+type stagedFile struct {
+	index    int    // position among the request's files, used to pair it with relative_paths and its result
+	tmpPath  string // "" once the file has been moved into place
+	filename string // sanitized base name
+	written  int64
+}
 
-	// Security: verify path is within allowed directory
-	absBase, err := filepath.Abs(baseDir)
+// handleUpload saves the files of a multipart upload. Each file is streamed to disk as
+// it arrives, so memory use does not grow with its size. The path, overwrite and
+// upload_id fields must come before the files; relative_paths may come anywhere.
+func (h *Handler) handleUpload(w http.ResponseWriter, r *http.Request) {
+	// Limit the whole request body to the configured size
+	maxBytes := h.config.GetMaxUploadBytes()
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+	reader, err := r.MultipartReader()
 	if err != nil {
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		http.Error(w, "Invalid upload form", http.StatusBadRequest)
 		return
 	}
 
-	absUpload, err := filepath.Abs(fullPath)
-	if err != nil {
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
+	form := url.Values{}
+	var (
+		absUpload   string
+		overwrite   bool
+		allow, deny []string
+		uploadID    string
+		staged      []*stagedFile
+		results     []FileStatus // one per file, in the order they were sent
+		fileCount   int
+	)
 
-	if !strings.HasPrefix(absUpload, absBase) {
-		http.Error(w, "Forbidden", http.StatusForbidden)
-		return
-	}
+	// Remove whatever is still staged when the request fails part way
+	defer func() {
+		for _, f := range staged {
+			if f.tmpPath != "" {
+				os.Remove(f.tmpPath)
+			}
+		}
+	}()
 
-	// Ensure upload directory exists
-	if err := os.MkdirAll(absUpload, 0755); err != nil {
-		http.Error(w, "Failed to create upload directory", http.StatusInternalServerError)
-		return
-	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			rejectUpload(w, err, maxBytes)
+			return
+		}
 
-	// Process uploaded files
-	files := r.MultipartForm.File["files"]
-	if len(files) == 0 {
-		http.Error(w, "No files uploaded", http.StatusBadRequest)
-		return
-	}
+		if part.FormName() != "files" || part.FileName() == "" {
+			value, err := io.ReadAll(io.LimitReader(part, maxFieldSize))
+			if err != nil {
+				rejectUpload(w, err, maxBytes)
+				return
+			}
+			form.Add(part.FormName(), string(value))
+			continue
+		}
 
-	uploadedFiles := []string{}
-	var uploadErrors []string
+		// The first file fixes where the upload goes
+		if absUpload == "" {
+			var status int
+			var message string
+			absUpload, status, message = h.uploadDir(form.Get("path"))
+			if status != http.StatusOK {
+				http.Error(w, message, status)
+				return
+			}
+			overwrite = form.Get("overwrite") == "true"
+			allow, deny = h.config.GetUploadExtensions()
 
-	for _, fileHeader := range files {
-		// Open uploaded file
-		file, err := fileHeader.Open()
-		if err != nil {
-			uploadErrors = append(uploadErrors, fmt.Sprintf("%s: failed to open", fileHeader.Filename))
-			continue
+			// Progress is reported for uploads whose client supplied an id to follow
+			uploadID = form.Get("upload_id")
+			if !uploadIDPattern.MatchString(uploadID) {
+				uploadID = ""
+			}
 		}
-		defer file.Close()
+
+		index := fileCount
+		fileCount++
 
 		// Security: sanitize filename
-		filename := filepath.Base(filepath.Clean(fileHeader.Filename))
+		original := part.FileName()
+		results = append(results, FileStatus{Original: original})
+		filename := filepath.Base(filepath.Clean(original))
 		if filename == "." || filename == ".." {
-			uploadErrors = append(uploadErrors, fmt.Sprintf("%s: invalid filename", fileHeader.Filename))
+			results[index].fail("invalid filename")
+			continue
+		}
+
+		// Check the extension and that the content matches it
+		head := make([]byte, 512)
+		n, err := io.ReadFull(part, head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			rejectUpload(w, err, maxBytes)
+			return
+		}
+		head = head[:n]
+		if err := checkExtension(filename, head, allow, deny); err != nil {
+			results[index].fail(err.Error())
 			continue
 		}
 
-		// Create destination file
-		destPath := filepath.Join(absUpload, filename)
-		
-		// Check if file already exists and handle it
-		if _, err := os.Stat(destPath); err == nil {
-			// File exists, append timestamp to make it unique
-			ext := filepath.Ext(filename)
-			base := strings.TrimSuffix(filename, ext)
-			filename = fmt.Sprintf("%s_%d%s", base, fileHeader.Size, ext)
-			destPath = filepath.Join(absUpload, filename)
+		// Name progress after the relative path when the client sent it first, so files
+		// with the same name in different folders are told apart
+		progressName := filename
+		if relPaths := form["relative_paths"]; len(relPaths) > index {
+			progressName = relPaths[index]
 		}
 
-		dst, err := os.Create(destPath)
+		file := &stagedFile{index: index, filename: filename}
+		staged = append(staged, file)
+		file.tmpPath, file.written, err = stage(absUpload, func(dst io.Writer) (int64, error) {
+			src := io.MultiReader(bytes.NewReader(head), part)
+			if h.progress == nil || uploadID == "" {
+				return io.Copy(dst, src)
+			}
+			pw := &progressWriter{w: dst, reporter: h.progress, id: uploadID, name: progressName, total: partSize(part, r)}
+			defer pw.done()
+			return io.Copy(pw, src)
+		})
 		if err != nil {
-			uploadErrors = append(uploadErrors, fmt.Sprintf("%s: failed to create file", filename))
-			continue
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				rejectUpload(w, err, maxBytes)
+				return
+			}
+			results[index].fail("failed to save")
 		}
+	}
 
-		// Copy file content
-		written, err := io.Copy(dst, file)
-		dst.Close()
+	if fileCount == 0 {
+		http.Error(w, "No files uploaded", http.StatusBadRequest)
+		return
+	}
+
+	// Folder uploads send each file's path below the chosen folder, in the same order as the files
+	relPaths := form["relative_paths"]
+	if len(relPaths) != fileCount {
+		relPaths = nil
+	}
+	for i := range relPaths {
+		results[i].Original = relPaths[i]
+	}
+
+	saved := 0
+	for _, file := range staged {
+		if file.tmpPath == "" {
+			continue // failed to stage
+		}
+		result := &results[file.index]
 
+		// Recreate the file's folder below the upload directory
+		destDir := absUpload
+		subDir, err := relativeDir(result.Original)
 		if err != nil {
-			os.Remove(destPath) // Clean up partial file
-			uploadErrors = append(uploadErrors, fmt.Sprintf("%s: failed to save", filename))
+			result.fail(err.Error())
 			continue
 		}
+		if subDir != "" {
+			destDir = filepath.Join(absUpload, subDir)
+			if !pathutil.IsWithin(absUpload, destDir) {
+				result.fail("invalid path")
+				continue
+			}
+			if err := os.MkdirAll(destDir, 0755); err != nil {
+				result.fail("failed to create directory")
+				continue
+			}
+		}
 
-		log.Printf("Uploaded: %s (%d bytes) to %s", filename, written, absUpload)
-		uploadedFiles = append(uploadedFiles, filename)
+		// Move the staged file into place
+		_, filename, status, err := moveIntoPlace(file.tmpPath, destDir, file.filename, overwrite)
+		if err != nil {
+			result.fail("failed to save")
+			continue
+		}
+		file.tmpPath = ""
+		if subDir != "" {
+			filename = filepath.ToSlash(filepath.Join(subDir, filename))
+		}
+
+		log.Printf("Uploaded: %s (%d bytes) to %s", filename, file.written, absUpload)
+		h.metrics.UploadCompleted()
+		result.Saved = filename
+		result.Size = file.written
+		result.Status = status
+		saved++
 	}
 
 	// Prepare response
 	response := map[string]interface{}{
-		"uploaded": uploadedFiles,
-		"count":    len(uploadedFiles),
-	}
-
-	if len(uploadErrors) > 0 {
-		response["errors"] = uploadErrors
+		"files": results,
+		"count": saved,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if len(uploadedFiles) > 0 {
+	if saved > 0 {
 		w.WriteHeader(http.StatusCreated)
 	} else {
 		w.WriteHeader(http.StatusBadRequest)
 	}
 	json.NewEncoder(w).Encode(response)
 }
+
+// uploadDir resolves the folder an upload is saved to and creates it if needed,
+// returning the status and message of the error response when that fails
+func (h *Handler) uploadDir(uploadPath string) (absUpload string, status int, message string) {
+	if uploadPath == "" {
+		uploadPath = "/"
+	}
+
+	// Get base directory and construct full upload path
+	baseDir := h.config.GetFileServerDir()
+	fullPath := filepath.Join(baseDir, filepath.Clean(uploadPath))
+
+	// Security: verify path is within allowed directory
+	absBase, err := filepath.Abs(baseDir)
+	if err != nil {
+		return "", http.StatusInternalServerError, "Internal server error"
+	}
+
+	absUpload, err = filepath.Abs(fullPath)
+	if err != nil {
+		return "", http.StatusInternalServerError, "Internal server error"
+	}
+
+	if !pathutil.IsWithin(absBase, absUpload) {
+		return "", http.StatusForbidden, "Forbidden"
+	}
+
+	// Ensure upload directory exists
+	if err := os.MkdirAll(absUpload, 0755); err != nil {
+		return "", http.StatusInternalServerError, "Failed to create upload directory"
+	}
+	return absUpload, http.StatusOK, ""
+}
+
+// partSize returns the size of a file being uploaded as far as it is known: the part's
+// own Content-Length, which browsers do not send, else the whole request's, or 0
+func partSize(part *multipart.Part, r *http.Request) int64 {
+	if size, err := strconv.ParseInt(part.Header.Get("Content-Length"), 10, 64); err == nil && size >= 0 {
+		return size
+	}
+	if r.ContentLength > 0 {
+		return r.ContentLength
+	}
+	return 0
+}
+
+// rejectUpload replies to an upload whose body could not be read, explaining the
+// size limit when the body was too large
+func rejectUpload(w http.ResponseWriter, err error, maxBytes int64) {
+	var tooLarge *http.MaxBytesError
+	if !errors.As(err, &tooLarge) {
+		http.Error(w, "Invalid upload form", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":            fmt.Sprintf("Upload exceeds the limit of %s (%d bytes)", format.FileSize(maxBytes), maxBytes),
+		"max_upload_bytes": maxBytes,
+	})
+}
+
+// relativeDir returns the directory part of a relative upload path such as "photos/2024/a.jpg",
+// or "" for a bare file name. Absolute paths and ".." elements are rejected.
+func relativeDir(relPath string) (string, error) {
+	relPath = strings.ReplaceAll(relPath, "\\", "/")
+	if strings.HasPrefix(relPath, "/") || filepath.IsAbs(relPath) {
+		return "", fmt.Errorf("invalid path")
+	}
+	for _, elem := range strings.Split(relPath, "/") {
+		if elem == ".." {
+			return "", fmt.Errorf("invalid path")
+		}
+	}
+
+	dir := path.Dir(path.Clean(relPath))
+	if dir == "." {
+		return "", nil
+	}
+	return filepath.FromSlash(dir), nil
+}
+
+// stage writes an upload to a new temporary file in dir and returns its path. The
+// caller renames it into place; on error nothing is left behind.
+func stage(dir string, write func(io.Writer) (int64, error)) (tmpPath string, written int64, err error) {
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return "", 0, err
+	}
+
+	written, err = write(tmp)
+	if err == nil {
+		// CreateTemp makes the file private; give it the permissions os.Create would
+		err = tmp.Chmod(0644)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name()) // Clean up partial file
+		return "", 0, err
+	}
+	return tmp.Name(), written, nil
+}
+
+// Upload outcomes reported per file
+const (
+	statusCreated     = "created"
+	statusRenamed     = "renamed"
+	statusOverwritten = "overwritten"
+	statusFailed      = "failed"
+)
+
+// FileStatus reports what happened to one uploaded file
+type FileStatus struct {
+	Original string `json:"original"`        // name or relative path sent by the client
+	Saved    string `json:"saved,omitempty"` // path saved to, relative to the upload folder
+	Size     int64  `json:"size"`
+	Status   string `json:"status"`          // created, renamed, overwritten or failed
+	Error    string `json:"error,omitempty"` // why a failed file was not saved
+}
+
+// fail records that the file was not saved and why
+func (s *FileStatus) fail(reason string) {
+	s.Status = statusFailed
+	s.Error = reason
+}
+
+// moveIntoPlace moves the staged file at tmpPath to filename in dir. An existing file is
+// replaced when overwrite is set; otherwise a " (1)", " (2)", ... suffix picks a free name.
+// Each name is claimed with a hard link, which fails if the name is taken, so concurrent
+// uploads of the same name cannot replace each other.
+func moveIntoPlace(tmpPath, dir, filename string, overwrite bool) (destPath, name, status string, err error) {
+	ext := filepath.Ext(filename)
+	base := strings.TrimSuffix(filename, ext)
+	name, status = filename, statusCreated
+	for i := 1; ; i++ {
+		destPath = filepath.Join(dir, name)
+		err = os.Link(tmpPath, destPath)
+		if err == nil {
+			os.Remove(tmpPath)
+			return destPath, name, status, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return "", "", "", err
+		}
+		if overwrite {
+			if err := os.Rename(tmpPath, destPath); err != nil {
+				return "", "", "", err
+			}
+			return destPath, name, statusOverwritten, nil
+		}
+		name, status = fmt.Sprintf("%s (%d)%s", base, i, ext), statusRenamed
+	}
+}
@@ -0,0 +1,397 @@
+package upload
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"simple.http.server/internal/config"
+)
+
+// newTestHandler returns a handler serving a temporary directory with the given upload
+// limit. Chunks are kept in a temporary directory of their own.
+func newTestHandler(t *testing.T, maxBytes int64) (*Handler, string) {
+	t.Helper()
+	return newTestHandlerWith(t, map[string]interface{}{"max_upload_bytes": maxBytes})
+}
+
+// newTestHandlerWith is newTestHandler with the given settings
+func newTestHandlerWith(t *testing.T, settings map[string]interface{}) (*Handler, string) {
+	t.Helper()
+	t.Setenv("TMPDIR", t.TempDir())
+	root := t.TempDir()
+	settings["file_server_dir"] = root
+	data, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings(data); err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(cfg), root
+}
+
+// multipartRequest builds an upload of the given files, named by their keys
+func multipartRequest(t *testing.T, fields map[string]string, files map[string]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, value := range fields {
+		mw.WriteField(name, value)
+	}
+	for name, content := range files {
+		fw, err := mw.CreateFormFile("files", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(content))
+	}
+	mw.Close()
+	req := httptest.NewRequest(http.MethodPost, "/api/upload", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestUploadUnderLimit(t *testing.T) {
+	h, root := newTestHandler(t, 1024)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, multipartRequest(t, nil, map[string]string{"a.txt": "hello"}))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", rec.Code, rec.Body)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "a.txt"))
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("saved file = %q, %v", data, err)
+	}
+}
+
+func TestUploadOverLimit(t *testing.T) {
+	h, root := newTestHandler(t, 1024)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, multipartRequest(t, nil, map[string]string{"big.txt": strings.Repeat("x", 4096)}))
+	assertTooLarge(t, rec, 1024)
+	if _, err := os.Stat(filepath.Join(root, "big.txt")); !os.IsNotExist(err) {
+		t.Errorf("oversized upload was saved: %v", err)
+	}
+}
+
+// assertTooLarge checks for the JSON 413 response naming the configured limit
+func assertTooLarge(t *testing.T, rec *httptest.ResponseRecorder, limit int64) {
+	t.Helper()
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want 413: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Error          string `json:"error"`
+		MaxUploadBytes int64  `json:"max_upload_bytes"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if resp.MaxUploadBytes != limit || !strings.Contains(resp.Error, fmt.Sprint(limit)) {
+		t.Errorf("response = %+v, want limit %d", resp, limit)
+	}
+}
+
+// uploadResults decodes the per-file results of an upload response
+func uploadResults(t *testing.T, rec *httptest.ResponseRecorder) []FileStatus {
+	t.Helper()
+	var resp struct {
+		Files []FileStatus `json:"files"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("invalid JSON %q: %v", rec.Body, err)
+	}
+	return resp.Files
+}
+
+// readFile returns the content of the file name under root, or "" if it cannot be read
+func readFile(root, name string) string {
+	data, _ := os.ReadFile(filepath.Join(root, filepath.FromSlash(name)))
+	return string(data)
+}
+
+func TestUploadOverwritePolicies(t *testing.T) {
+	h, root := newTestHandler(t, 1024)
+	os.WriteFile(filepath.Join(root, "report.txt"), []byte("original"), 0644)
+
+	tests := []struct {
+		fields map[string]string
+		saved  string
+		status string
+	}{
+		{nil, "report (1).txt", statusRenamed},
+		{map[string]string{"overwrite": "false"}, "report (2).txt", statusRenamed},
+		{map[string]string{"overwrite": "true"}, "report.txt", statusOverwritten},
+	}
+	for i, tt := range tests {
+		content := fmt.Sprintf("upload %d", i)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, multipartRequest(t, tt.fields, map[string]string{"report.txt": content}))
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("%v: status = %d: %s", tt.fields, rec.Code, rec.Body)
+		}
+		results := uploadResults(t, rec)
+		if len(results) != 1 || results[0].Saved != tt.saved || results[0].Status != tt.status {
+			t.Errorf("%v: results = %+v, want %s %s", tt.fields, results, tt.status, tt.saved)
+		}
+		if got := readFile(root, tt.saved); got != content {
+			t.Errorf("%v: %s holds %q, want %q", tt.fields, tt.saved, got, content)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, multipartRequest(t, nil, map[string]string{"new.txt": "new"}))
+	if results := uploadResults(t, rec); len(results) != 1 || results[0].Status != statusCreated {
+		t.Errorf("new file: results = %+v, want created", results)
+	}
+}
+
+func TestConcurrentUploadsOfOneName(t *testing.T) {
+	h, root := newTestHandler(t, 1024)
+	const uploads = 50
+
+	// Release all the uploads at once so their names are picked at the same time
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	results := make([][]FileStatus, uploads)
+	for i := range results {
+		req := multipartRequest(t, nil, map[string]string{"same.txt": fmt.Sprintf("upload %d", i)})
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			var resp struct {
+				Files []FileStatus `json:"files"`
+			}
+			json.NewDecoder(rec.Body).Decode(&resp)
+			results[i] = resp.Files
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	// Every upload keeps a name of its own, and only one of them got the name asked for
+	saved := map[string]bool{}
+	created := 0
+	for i, files := range results {
+		if len(files) != 1 {
+			t.Fatalf("upload %d: results = %+v", i, files)
+		}
+		if saved[files[0].Saved] {
+			t.Errorf("upload %d was saved as %s, which another upload reported too", i, files[0].Saved)
+		}
+		saved[files[0].Saved] = true
+		if files[0].Status == statusCreated {
+			created++
+		}
+		if got, want := readFile(root, files[0].Saved), fmt.Sprintf("upload %d", i); got != want {
+			t.Errorf("upload %d: %s holds %q, want %q", i, files[0].Saved, got, want)
+		}
+	}
+	if created != 1 {
+		t.Errorf("%d uploads were reported as created, want 1", created)
+	}
+}
+
+// resultsByName indexes upload results by the name the client sent
+func resultsByName(results []FileStatus) map[string]FileStatus {
+	byName := make(map[string]FileStatus)
+	for _, r := range results {
+		byName[r.Original] = r
+	}
+	return byName
+}
+
+func TestUploadReportsEachFile(t *testing.T) {
+	h, root := newTestHandlerWith(t, map[string]interface{}{"upload_deny_extensions": []string{".exe"}})
+	os.WriteFile(filepath.Join(root, "notes.txt"), []byte("original"), 0644)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, multipartRequest(t, nil, map[string]string{
+		"notes.txt": "second notes",
+		"fresh.txt": "fresh",
+		"tool.exe":  "binary",
+	}))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := resp["uploaded"]; ok {
+		t.Error("response still has the flat uploaded list")
+	}
+
+	var results []FileStatus
+	if err := json.Unmarshal(resp["files"], &results); err != nil {
+		t.Fatal(err)
+	}
+	byName := resultsByName(results)
+	want := map[string]FileStatus{
+		"notes.txt": {Original: "notes.txt", Saved: "notes (1).txt", Size: 12, Status: statusRenamed},
+		"fresh.txt": {Original: "fresh.txt", Saved: "fresh.txt", Size: 5, Status: statusCreated},
+	}
+	for name, w := range want {
+		if got := byName[name]; got != w {
+			t.Errorf("%s: result = %+v, want %+v", name, got, w)
+		}
+	}
+	if got := byName["tool.exe"]; got.Status != statusFailed || got.Saved != "" || got.Error == "" {
+		t.Errorf("tool.exe: result = %+v, want failed with a reason", got)
+	}
+	if len(results) != 3 {
+		t.Errorf("got %d results, want one per file", len(results))
+	}
+
+	if readFile(root, "notes.txt") != "original" || readFile(root, "notes (1).txt") != "second notes" {
+		t.Error("the conflicting upload replaced the existing file")
+	}
+}
+
+func TestUploadExtensionFilters(t *testing.T) {
+	h, root := newTestHandlerWith(t, map[string]interface{}{"upload_deny_extensions": []string{"EXE"}})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, multipartRequest(t, nil, map[string]string{
+		"notes.txt":  "allowed",
+		"virus.exe":  "MZ denied",
+		"VIRUS2.Exe": "MZ case variant",
+		"setup.txt":  "MZ renamed executable",
+		"photo.jpg":  "not really a jpeg",
+	}))
+	// Denied files do not stop the rest of the batch
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	results := resultsByName(uploadResults(t, rec))
+	if got := results["notes.txt"]; got.Status != statusCreated || readFile(root, "notes.txt") != "allowed" {
+		t.Errorf("notes.txt = %+v, want it saved", got)
+	}
+	for _, name := range []string{"virus.exe", "VIRUS2.Exe", "setup.txt", "photo.jpg"} {
+		if got := results[name]; got.Status != statusFailed || got.Error == "" {
+			t.Errorf("%s = %+v, want it rejected with a reason", name, got)
+		}
+		if readFile(root, name) != "" {
+			t.Errorf("%s was saved", name)
+		}
+	}
+
+	// With an allow list only the listed extensions are accepted
+	h, _ = newTestHandlerWith(t, map[string]interface{}{"upload_allow_extensions": []string{"txt", ".PNG"}})
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, multipartRequest(t, nil, map[string]string{"a.TXT": "a", "run.sh": "echo", "README": "read me"}))
+	results = resultsByName(uploadResults(t, rec))
+	if results["a.TXT"].Status != statusCreated || results["run.sh"].Status != statusFailed || results["README"].Status != statusFailed {
+		t.Errorf("results = %+v, want only a.TXT saved", results)
+	}
+}
+
+func TestFolderUploadKeepsStructure(t *testing.T) {
+	h, root := newTestHandler(t, 1024)
+	os.Mkdir(filepath.Join(root, "target"), 0755)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, multipartRequest(t,
+		map[string]string{"path": "/target", "relative_paths": "a/b/c.txt"},
+		map[string]string{"c.txt": "nested"}))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if results := uploadResults(t, rec); len(results) != 1 || results[0].Saved != "a/b/c.txt" {
+		t.Errorf("results = %+v, want a/b/c.txt saved", results)
+	}
+	if got := readFile(root, "target/a/b/c.txt"); got != "nested" {
+		t.Errorf("target/a/b/c.txt holds %q, want the upload", got)
+	}
+	if readFile(root, "target/c.txt") != "" {
+		t.Error("the upload was flattened into the target folder")
+	}
+}
+
+func TestFolderUploadRejectsEscapes(t *testing.T) {
+	h, root := newTestHandler(t, 1024)
+	os.Mkdir(filepath.Join(root, "target"), 0755)
+
+	for _, relPath := range []string{"../escaped.txt", "a/../../escaped.txt", "/etc/escaped.txt", `..\escaped.txt`} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, multipartRequest(t,
+			map[string]string{"path": "/target", "relative_paths": relPath},
+			map[string]string{"escaped.txt": "escaped"}))
+		if results := uploadResults(t, rec); len(results) != 1 || results[0].Status != statusFailed {
+			t.Errorf("%q: results = %+v, want it rejected", relPath, results)
+		}
+	}
+	if readFile(root, "escaped.txt") != "" || readFile(filepath.Dir(root), "escaped.txt") != "" {
+		t.Error("a file was written outside the target folder")
+	}
+}
+
+func TestLargeUploadIsStreamed(t *testing.T) {
+	const size = 64 << 20
+	h, root := newTestHandler(t, 1<<30)
+
+	// The body is generated as it is read, so only the handler could hold it all in memory
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	sent := sha256.New()
+	go func() {
+		fw, err := mw.CreateFormFile("files", "large.bin")
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		content := io.LimitReader(rand.New(rand.NewSource(1)), size)
+		if _, err := io.Copy(io.MultiWriter(fw, sent), content); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.CloseWithError(mw.Close())
+	}()
+	req := httptest.NewRequest(http.MethodPost, "/api/upload", pr)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	runtime.ReadMemStats(&after)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	// Everything allocated during the upload, generator included, stays far below its size
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > size/8 {
+		t.Errorf("uploading %d bytes allocated %d bytes, want it streamed", size, allocated)
+	}
+
+	f, err := os.Open(filepath.Join(root, "large.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	saved := sha256.New()
+	if n, err := io.Copy(saved, f); err != nil || n != size {
+		t.Fatalf("saved %d bytes, %v, want %d", n, err, size)
+	}
+	if !bytes.Equal(saved.Sum(nil), sent.Sum(nil)) {
+		t.Error("saved file differs from the upload")
+	}
+}
@@ -0,0 +1,45 @@
+package upload
+
+import (
+	"io"
+	"time"
+)
+
+// progressInterval is the minimum time between two progress events for one file
+const progressInterval = 250 * time.Millisecond
+
+// ProgressReporter receives the progress of files being written
+type ProgressReporter interface {
+	BroadcastProgress(id, name string, written, total int64)
+}
+
+// SetProgressReporter sets where upload progress is reported; uploads without an id are not reported
+func (h *Handler) SetProgressReporter(reporter ProgressReporter) {
+	h.progress = reporter
+}
+
+// progressWriter counts the bytes written through it and reports them at most once per progressInterval
+type progressWriter struct {
+	w        io.Writer
+	reporter ProgressReporter
+	id       string
+	name     string
+	total    int64
+	written  int64
+	last     time.Time
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	if now := time.Now(); now.Sub(p.last) >= progressInterval {
+		p.last = now
+		p.reporter.BroadcastProgress(p.id, p.name, p.written, p.total)
+	}
+	return n, err
+}
+
+// done reports the final byte count, which the throttle may have skipped
+func (p *progressWriter) done() {
+	p.reporter.BroadcastProgress(p.id, p.name, p.written, p.total)
+}
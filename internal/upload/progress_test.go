@@ -0,0 +1,71 @@
+package upload
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// progressEvent is one call to BroadcastProgress
+type progressEvent struct {
+	id, name       string
+	written, total int64
+}
+
+// recordingReporter keeps every progress event it receives
+type recordingReporter struct {
+	events []progressEvent
+}
+
+func (r *recordingReporter) BroadcastProgress(id, name string, written, total int64) {
+	r.events = append(r.events, progressEvent{id, name, written, total})
+}
+
+func TestUploadReportsProgress(t *testing.T) {
+	h, _ := newTestHandler(t, 10<<20)
+	reporter := &recordingReporter{}
+	h.SetProgressReporter(reporter)
+
+	const size = 1 << 20
+	req := multipartRequest(t, map[string]string{"upload_id": "up1"}, map[string]string{"big.bin": strings.Repeat("x", size)})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	if len(reporter.events) < 2 {
+		t.Fatalf("got %d progress events, want an intermediate and a final one", len(reporter.events))
+	}
+	first, last := reporter.events[0], reporter.events[len(reporter.events)-1]
+	if first.id != "up1" || first.name != "big.bin" {
+		t.Errorf("first event = %+v, want id up1 for big.bin", first)
+	}
+	if first.written <= 0 || first.written >= size {
+		t.Errorf("first event wrote %d bytes, want an intermediate count", first.written)
+	}
+	if last.written != size {
+		t.Errorf("last event wrote %d bytes, want %d", last.written, size)
+	}
+	for _, e := range reporter.events {
+		if e.total < size {
+			t.Fatalf("event total = %d, want the known size of at least %d", e.total, size)
+		}
+	}
+}
+
+func TestUploadWithoutIDReportsNoProgress(t *testing.T) {
+	h, _ := newTestHandler(t, 1024)
+	reporter := &recordingReporter{}
+	h.SetProgressReporter(reporter)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, multipartRequest(t, nil, map[string]string{"a.txt": "hello"}))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if len(reporter.events) != 0 {
+		t.Errorf("got %d progress events for an upload without id", len(reporter.events))
+	}
+}
@@ -0,0 +1,173 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// errPrivateTarget is returned when a URL upload would connect to a local or private address
+var errPrivateTarget = errors.New("private address")
+
+// sharedAddressSpace is the carrier-grade NAT range, which is not public either
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// remoteClient fetches URL uploads. The address is checked when each connection is made,
+// so redirects and DNS names that resolve to private addresses are refused too.
+// Environment proxies are not used since they would connect on the client's behalf.
+var remoteClient = &http.Client{
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: func(network, address string, c syscall.RawConn) error {
+				return checkDialAddress(address)
+			},
+		}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+	},
+}
+
+// checkDialAddress refuses connections to addresses that are not public. It is a variable
+// so tests can let the client reach a local server standing in for a public one.
+var checkDialAddress = func(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || isPrivateIP(ip) {
+		return errPrivateTarget
+	}
+	return nil
+}
+
+// isPrivateIP reports whether ip is loopback, private, link-local or otherwise not a public address
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || sharedAddressSpace.Contains(ip)
+}
+
+// handleURL saves a file the server downloads from a public http or https URL, applying
+// the same size limit and extension filters as uploads
+func (h *Handler) handleURL(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		URL       string `json:"url"`
+		Path      string `json:"path"`
+		Overwrite bool   `json:"overwrite"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxFieldSize)).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	remote, err := url.Parse(req.URL)
+	if err != nil || (remote.Scheme != "http" && remote.Scheme != "https") || remote.Host == "" {
+		http.Error(w, "url must be an http or https URL", http.StatusBadRequest)
+		return
+	}
+
+	absUpload, status, message := h.uploadDir(req.Path)
+	if status != http.StatusOK {
+		http.Error(w, message, status)
+		return
+	}
+
+	fetch, err := http.NewRequestWithContext(r.Context(), http.MethodGet, remote.String(), nil)
+	if err != nil {
+		http.Error(w, "Invalid url", http.StatusBadRequest)
+		return
+	}
+	resp, err := remoteClient.Do(fetch)
+	if err != nil {
+		if errors.Is(err, errPrivateTarget) {
+			http.Error(w, "Fetching from local or private addresses is not allowed", http.StatusForbidden)
+			return
+		}
+		log.Printf("Failed to fetch %s: %v", remote.Redacted(), err)
+		http.Error(w, "Failed to fetch url", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		http.Error(w, "Remote server returned "+resp.Status, http.StatusBadGateway)
+		return
+	}
+
+	maxBytes := h.config.GetMaxUploadBytes()
+	if resp.ContentLength > maxBytes {
+		rejectUpload(w, &http.MaxBytesError{Limit: maxBytes}, maxBytes)
+		return
+	}
+
+	// Security: sanitize filename
+	filename := filepath.Base(filepath.Clean(remoteFilename(resp)))
+	if filename == "." || filename == ".." || filename == string(filepath.Separator) {
+		filename = "download"
+	}
+
+	// Check the extension and that the content matches it
+	head := make([]byte, 512)
+	n, err := io.ReadFull(resp.Body, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		http.Error(w, "Failed to fetch url", http.StatusBadGateway)
+		return
+	}
+	head = head[:n]
+	allow, deny := h.config.GetUploadExtensions()
+	if err := checkExtension(filename, head, allow, deny); err != nil {
+		http.Error(w, fmt.Sprintf("%s: %v", filename, err), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	// Read one byte past the limit to tell a body of exactly the limit from a larger one
+	tmpPath, written, err := stage(absUpload, func(dst io.Writer) (int64, error) {
+		return io.Copy(dst, io.LimitReader(io.MultiReader(bytes.NewReader(head), resp.Body), maxBytes+1))
+	})
+	if err != nil {
+		http.Error(w, "Failed to save file", http.StatusInternalServerError)
+		return
+	}
+	if written > maxBytes {
+		os.Remove(tmpPath)
+		rejectUpload(w, &http.MaxBytesError{Limit: maxBytes}, maxBytes)
+		return
+	}
+
+	_, name, outcome, err := moveIntoPlace(tmpPath, absUpload, filename, req.Overwrite)
+	if err != nil {
+		os.Remove(tmpPath)
+		http.Error(w, "Failed to save file", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Uploaded from %s: %s (%d bytes) to %s", remote.Redacted(), name, written, absUpload)
+	h.metrics.UploadCompleted()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"files": []FileStatus{{Original: remote.Redacted(), Saved: name, Size: written, Status: outcome}},
+		"count": 1,
+	})
+}
+
+// remoteFilename picks a name for a downloaded file: the one given by the server's
+// Content-Disposition, else the last element of the final URL's path
+func remoteFilename(resp *http.Response) string {
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		return params["filename"]
+	}
+	return path.Base(resp.Request.URL.Path)
+}
@@ -0,0 +1,147 @@
+package upload
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// allowDialTo lets URL uploads connect to the given test servers, which listen on
+// loopback, while every other address is still checked as usual
+func allowDialTo(t *testing.T, servers ...*httptest.Server) {
+	t.Helper()
+	allowed := make(map[string]bool)
+	for _, s := range servers {
+		allowed[s.Listener.Addr().String()] = true
+	}
+	check := checkDialAddress
+	checkDialAddress = func(address string) error {
+		if allowed[address] {
+			return nil
+		}
+		return check(address)
+	}
+	t.Cleanup(func() { checkDialAddress = check })
+}
+
+// urlRequest asks the handler to fetch target into the served directory
+func urlRequest(target string) *http.Request {
+	return httptest.NewRequest(http.MethodPost, "/api/upload/url", strings.NewReader(`{"url":"`+target+`"}`))
+}
+
+func TestIsPrivateIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		private bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"fe80::1", true},
+		{"fd00::1", true},
+		{"224.0.0.1", true},
+		{"8.8.8.8", false},
+		{"2606:4700:4700::1111", false},
+	}
+	for _, tt := range tests {
+		if got := isPrivateIP(net.ParseIP(tt.ip)); got != tt.private {
+			t.Errorf("isPrivateIP(%s) = %v, want %v", tt.ip, got, tt.private)
+		}
+	}
+}
+
+func TestURLUploadRejectsPrivateAddresses(t *testing.T) {
+	h, _ := newTestHandler(t, 1024)
+	for _, target := range []string{
+		"http://127.0.0.1:1/file.txt",
+		"http://[::1]:1/file.txt",
+		"http://localhost:1/file.txt",
+		"http://10.0.0.1/file.txt",
+		"http://169.254.169.254/latest/meta-data",
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, urlRequest(target))
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s: status = %d, want 403: %s", target, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestURLUploadRejectsRedirectToPrivateAddress(t *testing.T) {
+	reached := false
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		w.Write([]byte("internal"))
+	}))
+	defer internal.Close()
+	public := httptest.NewServer(http.RedirectHandler(internal.URL+"/secret.txt", http.StatusFound))
+	defer public.Close()
+	allowDialTo(t, public)
+
+	h, root := newTestHandler(t, 1024)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, urlRequest(public.URL+"/file.txt"))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403: %s", rec.Code, rec.Body)
+	}
+	if reached {
+		t.Error("the redirect reached the private server")
+	}
+	if entries, _ := os.ReadDir(root); len(entries) != 0 {
+		t.Errorf("files were saved: %v", entries)
+	}
+}
+
+func TestURLUploadOverLimit(t *testing.T) {
+	body := strings.Repeat("x", 2048)
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chunked.txt" {
+			// Flushing first sends the body without a Content-Length
+			w.(http.Flusher).Flush()
+		}
+		w.Write([]byte(body))
+	}))
+	defer remote.Close()
+	allowDialTo(t, remote)
+
+	h, root := newTestHandler(t, 1024)
+	for _, name := range []string{"sized.txt", "chunked.txt"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, urlRequest(remote.URL+"/"+name))
+		assertTooLarge(t, rec, 1024)
+		if _, err := os.Stat(filepath.Join(root, name)); !os.IsNotExist(err) {
+			t.Errorf("%s: oversized download was saved: %v", name, err)
+		}
+	}
+	if entries, _ := os.ReadDir(root); len(entries) != 0 {
+		t.Errorf("files left in the upload directory: %v", entries)
+	}
+}
+
+func TestURLUploadSavesFile(t *testing.T) {
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	defer remote.Close()
+	allowDialTo(t, remote)
+
+	h, root := newTestHandler(t, 1024)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, urlRequest(remote.URL+"/notes.txt"))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", rec.Code, rec.Body)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "notes.txt"))
+	if err != nil || string(data) != "hello" {
+		t.Errorf("saved file = %q, %v", data, err)
+	}
+}
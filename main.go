@@ -1,7 +1,10 @@
 package main
 
 import (
+	"crypto/tls"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"net/http"
@@ -9,19 +12,59 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"simple.http.server/internal/admin"
 	"simple.http.server/internal/archive"
+	"simple.http.server/internal/checksum"
 	"simple.http.server/internal/clipboard"
+	"simple.http.server/internal/compress"
 	"simple.http.server/internal/config"
+	"simple.http.server/internal/fileops"
 	"simple.http.server/internal/fileserver"
+	"simple.http.server/internal/health"
+	"simple.http.server/internal/icon"
+	"simple.http.server/internal/metrics"
+	"simple.http.server/internal/netutil"
+	"simple.http.server/internal/preview"
 	"simple.http.server/internal/proxy"
+	"simple.http.server/internal/qr"
+	"simple.http.server/internal/ratelimit"
+	"simple.http.server/internal/requestlog"
 	"simple.http.server/internal/search"
+	"simple.http.server/internal/tail"
+	"simple.http.server/internal/thumbnail"
+	"simple.http.server/internal/timeout"
+	"simple.http.server/internal/tlsutil"
 	"simple.http.server/internal/upload"
 )
 
+// Build details, set at build time with -ldflags "-X main.version=... -X main.commit=... -X main.buildDate=..."
+var (
+	version   = "dev"
+	commit    = "dev"
+	buildDate = "dev"
+)
+
 func main() {
+	// Command-line flags; the default flag set exits on errors
+	opts, _ := parseFlags(flag.CommandLine, os.Args[1:])
+
+	if opts.version {
+		fmt.Printf("simple-http-server %s (commit %s, built %s)\n", version, commit, buildDate)
+		return
+	}
+
+	if (opts.cert == "") != (opts.key == "") {
+		log.Fatalf("Both -cert and -key must be given together")
+	}
+	useTLS := opts.tls || opts.cert != ""
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+
 	// Get current working directory
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -43,86 +86,437 @@ func main() {
 		}
 	}
 
-	// Initialize configuration
+	// Serve the directory given with -dir instead of the working directory
+	serveDir, err := opts.serveDir(cwd)
+	if err != nil {
+		log.Fatalf("Invalid -dir: %v", err)
+	}
+
+	// Serve the page given with -home at the root
+	homePage := ""
+	if opts.home != "" {
+		homePage, err = resolveHomePage(opts.home)
+		if err != nil {
+			log.Fatalf("Invalid -home: %v", err)
+		}
+	}
+
+	// Initialize configuration, restoring saved settings if available
 	cfg := config.GetConfig()
-	cfg.SetFileServerDir(cwd)
+	configPath, err := config.DefaultConfigPath()
+	if err != nil {
+		log.Printf("Failed to locate config file: %v", err)
+	} else if err := cfg.Load(configPath); err != nil {
+		log.Printf("Failed to load config from %s: %v", configPath, err)
+	}
+	cfg.SetFileServerDir(serveDir)
+	if opts.poll {
+		cfg.SetWatchPoll(true)
+	}
 
+	var accessLog io.Writer
+	if opts.accessLog != "" {
+		accessLog, err = openAccessLog(opts.accessLog)
+		if err != nil {
+			log.Fatalf("Failed to open access log: %v", err)
+		}
+	}
+	handler, proxyManager := newHandler(cfg, opts.mounts, homePage, accessLog)
+
+	// Listen on the requested port, or let the OS assign one when it is 0
+	listener, port, err := listen(opts.addr, opts.port)
+	if err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+	
+	// Update config with the actual port
+	cfg.SetFileServerPort(port)
+
+	// Wrap the listener with a generated certificate when no files are given
+	if useTLS && opts.cert == "" {
+		cert, err := tlsutil.GenerateSelfSigned(netutil.LocalIP())
+		if err != nil {
+			log.Fatalf("Failed to generate TLS certificate: %v", err)
+		}
+		listener = tls.NewListener(listener, &tls.Config{Certificates: []tls.Certificate{cert}})
+	}
+
+	// Start port-based proxies AFTER config is updated with the port
+	go startPortBasedProxies(cfg, proxyManager)
+
+	// Print startup information
+	log.Println("╔════════════════════════════════════════════════════════════╗")
+	log.Println("║          Simple HTTP Server - 2 in 1                       ║")
+	log.Println("╚════════════════════════════════════════════════════════════╝")
+	if homePage != "" {
+		log.Printf("🏠 Home Page:      %s://localhost:%d/ (%s)", scheme, port, homePage)
+		log.Printf("📁 File Server:    %s://localhost:%d%s/", scheme, port, filesPrefix)
+	} else {
+		log.Printf("📁 File Server:    %s://localhost:%d/", scheme, port)
+	}
+	log.Printf("📂 Serving from:   %s", serveDir)
+	for _, m := range opts.mounts {
+		log.Printf("🗂️  Mount:          %s://localhost:%d%s -> %s", scheme, port, m.URLPath(), m.Dir)
+	}
+	log.Printf("⚙️  Admin Panel:    %s://localhost:%d/admin/", scheme, port)
+	if useTLS && opts.cert == "" {
+		log.Printf("🔒 TLS:            Self-signed certificate (browsers will show a warning)")
+	} else if useTLS {
+		log.Printf("🔒 TLS:            %s", opts.cert)
+	}
+	log.Printf("🔄 Live Updates:   Enabled (SSE)")
+	if configPath != "" {
+		log.Printf("💾 Config File:    %s", configPath)
+	}
+	if opts.accessLog != "" {
+		log.Printf("📝 Access Log:     %s", opts.accessLog)
+	}
+	if opts.qr {
+		printNetworkQR(scheme, port)
+	}
+	log.Println("────────────────────────────────────────────────────────────")
+	log.Printf("Server starting on %s", listener.Addr())
+	log.Println("Press Ctrl+C to stop")
+	log.Println("")
+
+	// Open admin panel in browser
+	adminURL := fmt.Sprintf("%s://localhost:%d/admin/", scheme, port)
+	go openBrowser(adminURL)
+
+	// Start server with the listener we already created
+	server := timeout.NewServer(cfg, handler)
+	if opts.cert != "" {
+		err = server.ServeTLS(listener, opts.cert, opts.key)
+	} else {
+		err = server.Serve(listener)
+	}
+	if err != nil {
+		log.Fatalf("Server failed: %v", err)
+	}
+}
+
+// options holds the command-line flags
+type options struct {
+	port      int
+	addr      string
+	dir       string
+	home      string
+	tls       bool
+	cert      string
+	key       string
+	poll      bool
+	accessLog string
+	version   bool
+	qr        bool
+	mounts    mountFlags
+}
+
+// parseFlags defines the command-line flags on fs and parses args into options
+func parseFlags(fs *flag.FlagSet, args []string) (*options, error) {
+	opts := &options{}
+	fs.IntVar(&opts.port, "port", 0, "port to listen on (0 picks a free port)")
+	fs.StringVar(&opts.addr, "addr", "", "address to bind to (empty binds all interfaces)")
+	fs.StringVar(&opts.dir, "dir", "", "directory to serve (default: current directory)")
+	fs.StringVar(&opts.home, "home", "", "HTML file served at / (the directory listing moves to /files/)")
+	fs.BoolVar(&opts.tls, "tls", false, "serve over HTTPS (uses a self-signed certificate unless -cert and -key are given)")
+	fs.StringVar(&opts.cert, "cert", "", "TLS certificate file (PEM)")
+	fs.StringVar(&opts.key, "key", "", "TLS private key file (PEM)")
+	fs.BoolVar(&opts.poll, "poll", false, "detect file changes by polling instead of file system notifications")
+	fs.StringVar(&opts.accessLog, "access-log", "", "write proxy access logs as JSON lines to this file (\"-\" for stdout)")
+	fs.BoolVar(&opts.version, "version", false, "print version information and exit")
+	fs.BoolVar(&opts.qr, "qr", isTerminal(os.Stderr), "print a QR code of the network URL at startup (default on in a terminal)")
+	fs.Var(&opts.mounts, "mount", "serve another directory under /mnt/<name>/, given as name=path (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// serveDir returns the directory to serve: the one given with -dir, else cwd
+func (opts *options) serveDir(cwd string) (string, error) {
+	if opts.dir == "" {
+		return cwd, nil
+	}
+	return resolveServeDir(opts.dir)
+}
+
+// newHandler builds every component and registers its routes, returning the server's
+// handler and the proxy manager that port-based proxies share with it.
+// accessLog may be nil; homePage is empty unless -home was given.
+func newHandler(cfg *config.Config, mounts mountFlags, homePage string, accessLog io.Writer) (http.Handler, *proxy.ProxyManager) {
 	// Initialize components
+	serverMetrics := metrics.New()
 	fileServer := fileserver.NewFileServer(cfg)
+	fileServer.SetMounts(mounts)
+	serverMetrics.SetClientCounter(fileServer)
 	proxyManager := proxy.NewProxyManager(cfg)
+	proxyManager.SetMetrics(serverMetrics)
+	if accessLog != nil {
+		proxyManager.SetAccessLog(accessLog)
+	}
 	adminHandler := admin.NewHandler(cfg, proxyManager)
+	adminHandler.SetWatcher(fileServer)
+	adminHandler.SetBuildInfo(admin.BuildInfo{Version: version, Commit: commit, BuildDate: buildDate})
+	requestLog := requestlog.New(requestlog.DefaultCapacity)
+	adminHandler.SetRequestLog(requestLog)
 	uploadHandler := upload.NewHandler(cfg)
+	uploadHandler.SetProgressReporter(fileServer)
+	uploadHandler.SetMetrics(serverMetrics)
 	searchHandler := search.NewHandler(cfg)
-	clipboardHandler := clipboard.NewHandler()
+	clipboardHandler := clipboard.NewHandler(cfg)
+	qrHandler := qr.NewHandler()
 	archiveHandler := archive.NewHandler(cfg)
+	archiveHandler.SetMetrics(serverMetrics)
+	previewHandler := preview.NewHandler(cfg)
+	thumbnailHandler := thumbnail.NewHandler(cfg)
+	checksumHandler := checksum.NewHandler(cfg)
+	tailHandler := tail.NewHandler(cfg)
+	fileopsHandler := fileops.NewHandler(cfg)
+	fileopsHandler.SetChangeNotifier(fileServer)
+	healthHandler := health.NewHandler(cfg, version)
+	compressor := compress.New(cfg)
 
 	// Setup routes
 	mux := http.NewServeMux()
 
 	// Admin panel routes
-	mux.Handle("/admin/api/", adminHandler)
-	mux.Handle("/admin/", http.StripPrefix("/admin", admin.GetStaticHandler()))
+	mux.Handle("/admin/api/", compressor.Wrap(adminHandler))
+	mux.Handle("/admin/", compressor.Wrap(http.StripPrefix("/admin", admin.GetStaticHandler())))
 
-	// API routes for new features
-	mux.Handle("/api/upload", uploadHandler)
+	// API routes for new features. Streams and large transfers are exempt from the
+	// server's read and write timeouts.
+	mux.Handle("/api/upload", timeout.Exempt(uploadHandler))
+	mux.Handle("/api/upload/", timeout.Exempt(uploadHandler))
 	mux.Handle("/api/search", searchHandler)
+	mux.Handle("/api/search/stream", timeout.Exempt(searchHandler))
+	mux.Handle("/api/recent", searchHandler)
 	mux.Handle("/api/clipboard", clipboardHandler)
-	mux.Handle("/api/archive", archiveHandler)
+	mux.Handle("/api/clipboard/qr", clipboardHandler)
+	mux.Handle("/api/qr", qrHandler)
+	mux.Handle("/api/archive", timeout.Exempt(archiveHandler))
+	mux.Handle("/api/preview", compressor.Wrap(previewHandler))
+	// The file's path may follow the prefix; raw and assets have longer routes of their own
+	mux.Handle("/api/preview/", compressor.Wrap(previewHandler))
+	mux.Handle("/api/preview/raw", timeout.Exempt(previewHandler))
+	mux.Handle("/api/preview/assets/", http.StripPrefix("/api/preview/assets", preview.GetAssetHandler()))
+	mux.Handle("/api/thumbnail", thumbnailHandler)
+	mux.Handle("/api/checksum", timeout.Exempt(checksumHandler))
+	mux.Handle("/api/tail", timeout.Exempt(tailHandler))
+	mux.Handle("/api/delete", fileopsHandler)
+	mux.Handle("/api/delete/batch", fileopsHandler)
+	mux.Handle("/api/mkdir", fileopsHandler)
+	mux.Handle("/api/copy", fileopsHandler)
+	mux.Handle("/api/move", fileopsHandler)
+	mux.Handle("/api/stat", fileopsHandler)
+	mux.Handle("/api/du", fileopsHandler)
+
+	// Liveness and readiness checks for service managers and containers
+	mux.Handle("/healthz", healthHandler)
+	mux.Handle("/readyz", healthHandler)
+	mux.Handle("/metrics", serverMetrics)
 
 	// SSE endpoint for file changes
-	mux.HandleFunc("/events", fileServer.HandleSSE)
-
-	// Main router to handle proxy vs file server
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		// Check if this path matches any proxy rule
-		rules := cfg.GetProxyRules()
-		for _, rule := range rules {
-			if rule.PathPrefix != "" && strings.HasPrefix(r.URL.Path, rule.PathPrefix) {
-				proxyManager.ServeHTTP(w, r)
-				return
-			}
+	mux.Handle("/events", timeout.Exempt(http.HandlerFunc(fileServer.HandleSSE)))
+
+	// Extra directories given with -mount, each confined to its own directory
+	for _, m := range mounts {
+		mountServer := fileserver.NewMount(cfg, m)
+		mux.Handle(m.URLPath(), timeout.Extend(compressor.Wrap(http.StripPrefix(strings.TrimSuffix(m.URLPath(), "/"), mountServer))))
+	}
+
+	// With -home the home page takes the root and the served directory moves to /files/
+	var home http.Handler
+	if homePage != "" {
+		fileServer.SetPrefix(filesPrefix)
+		mux.Handle(filesPrefix+"/", timeout.Extend(compressor.Wrap(http.StripPrefix(filesPrefix, fileServer))))
+		home = compressor.Wrap(homeHandler(homePage))
+	}
+
+	// Main router to handle proxy vs file server; proxied responses are passed through as they are.
+	// Large files and proxied bodies keep the timeouts, restarted as each part is sent.
+	compressedFiles := compressor.Wrap(fileServer)
+	root := timeout.Extend(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Check if this host or path matches any proxy rule
+		if _, ok := proxyManager.Match(r); ok {
+			proxyManager.ServeHTTP(w, r)
+			return
 		}
 
-		// No proxy match, serve files
-		fileServer.ServeHTTP(w, r)
+		// No proxy match, serve the home page or files
+		if home != nil {
+			home.ServeHTTP(w, r)
+			return
+		}
+		compressedFiles.ServeHTTP(w, r)
+	}))
+
+	// Built-in favicon and app manifest, unless a proxy rule or a file of the same
+	// name at the top of the served directory claims the path
+	icons := icon.GetHandler()
+	iconRoute := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := proxyManager.Match(r); ok {
+			root.ServeHTTP(w, r)
+			return
+		}
+		if info, err := os.Stat(filepath.Join(cfg.GetFileServerDir(), r.URL.Path)); err == nil && !info.IsDir() {
+			root.ServeHTTP(w, r)
+			return
+		}
+		icons.ServeHTTP(w, r)
 	})
+	for _, p := range icon.Paths {
+		mux.Handle(p, iconRoute)
+	}
+	mux.Handle("/", root)
 
-	// Find an available port (use 0 to let OS assign one)
-	listener, err := net.Listen("tcp", ":0")
+	// Limit each client's request rate and keep recent requests, including rejected
+	// ones, for the admin panel and metrics
+	return serverMetrics.Wrap(requestLog.Wrap(ratelimit.New(cfg).Wrap(mux))), proxyManager
+}
+
+// listen opens the server's listener on addr and port, or on a free port chosen by the
+// OS when port is 0, and returns it with the port actually used
+func listen(addr string, port int) (net.Listener, int, error) {
+	listenAddr := net.JoinHostPort(addr, strconv.Itoa(port))
+	listener, err := net.Listen("tcp", listenAddr)
 	if err != nil {
-		log.Fatalf("Failed to find available port: %v", err)
+		if port != 0 {
+			return nil, 0, fmt.Errorf("listen on %s (is the port already in use?): %w", listenAddr, err)
+		}
+		return nil, 0, fmt.Errorf("find an available port: %w", err)
 	}
-	
-	// Get the actual port assigned
-	port := listener.Addr().(*net.TCPAddr).Port
-	
-	// Update config with the actual port
-	cfg.SetFileServerPort(port)
+	return listener, listener.Addr().(*net.TCPAddr).Port, nil
+}
 
-	// Start port-based proxies AFTER config is updated with the port
-	go startPortBasedProxies(cfg, proxyManager)
+// resolveServeDir returns the absolute form of dir after checking it is an existing directory
+func resolveServeDir(dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
 
-	// Print startup information
-	log.Println("╔════════════════════════════════════════════════════════════╗")
-	log.Println("║          Simple HTTP Server - 2 in 1                       ║")
-	log.Println("╚════════════════════════════════════════════════════════════╝")
-	log.Printf("📁 File Server:    http://localhost:%d/", port)
-	log.Printf("📂 Serving from:   %s", cwd)
-	log.Printf("⚙️  Admin Panel:    http://localhost:%d/admin/", port)
-	log.Printf("🔄 Live Updates:   Enabled (SSE)")
-	log.Println("────────────────────────────────────────────────────────────")
-	log.Printf("Server starting on :%d", port)
-	log.Println("Press Ctrl+C to stop")
-	log.Println("")
+	info, err := os.Stat(absDir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", absDir)
+	}
+	return absDir, nil
+}
 
-	// Open admin panel in browser
-	adminURL := fmt.Sprintf("http://localhost:%d/admin/", port)
-	go openBrowser(adminURL)
+// filesPrefix is where the served directory is listed when -home takes the root
+const filesPrefix = "/files"
 
-	// Start server with the listener we already created
-	if err := http.Serve(listener, mux); err != nil {
-		log.Fatalf("Server failed: %v", err)
+// resolveHomePage returns the absolute path of the -home file, checking it is a regular file
+func resolveHomePage(file string) (string, error) {
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(absFile)
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%s is not a file", absFile)
+	}
+	return absFile, nil
+}
+
+// homeHandler serves the home page at the root and redirects every other path to the
+// same place under /files/, so links from before the home page was set keep working
+func homeHandler(homePage string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			target := filesPrefix + r.URL.EscapedPath()
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusTemporaryRedirect)
+			return
+		}
+		// The file is read on every request, so edits show up without a restart
+		http.ServeFile(w, r, homePage)
+	})
+}
+
+// mountFlags collects the directories given with repeated -mount name=path flags
+type mountFlags []fileserver.Mount
+
+func (m *mountFlags) String() string {
+	var parts []string
+	for _, mount := range *m {
+		parts = append(parts, mount.Name+"="+mount.Dir)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (m *mountFlags) Set(value string) error {
+	name, dir, ok := strings.Cut(value, "=")
+	if !ok || dir == "" {
+		return fmt.Errorf("expected name=path")
+	}
+	if !validMountName(name) {
+		return fmt.Errorf("invalid mount name %q (use letters, digits, '.', '-' and '_')", name)
+	}
+	for _, mount := range *m {
+		if mount.Name == name {
+			return fmt.Errorf("mount %q is given more than once", name)
+		}
+	}
+
+	absDir, err := resolveServeDir(dir)
+	if err != nil {
+		return err
+	}
+	*m = append(*m, fileserver.Mount{Name: name, Dir: absDir})
+	return nil
+}
+
+// validMountName reports whether name can be used as a single URL path segment
+func validMountName(name string) bool {
+	if name == "" || name == "." || name == ".." {
+		return false
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// isTerminal reports whether f is an interactive terminal rather than a file or pipe
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// printNetworkQR prints a QR code of the server's LAN URL so phones can open it by scanning
+func printNetworkQR(scheme string, port int) {
+	ip := netutil.LocalIP()
+	if ip == "" {
+		return
+	}
+	networkURL := fmt.Sprintf("%s://%s/", scheme, net.JoinHostPort(ip, strconv.Itoa(port)))
+
+	code, err := qr.Terminal(networkURL)
+	if err != nil {
+		log.Printf("Failed to render QR code: %v", err)
+		return
+	}
+	log.Printf("📱 Network URL:    %s", networkURL)
+	fmt.Fprint(os.Stderr, code)
+}
+
+// openAccessLog returns stdout for "-", or the named file opened for appending
+func openAccessLog(path string) (io.Writer, error) {
+	if path == "-" {
+		return os.Stdout, nil
 	}
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 }
 
 // openBrowser opens the specified URL in the default browser
@@ -147,16 +541,19 @@ func openBrowser(url string) {
 func startPortBasedProxies(cfg *config.Config, proxyManager *proxy.ProxyManager) {
 	rules := cfg.GetProxyRules()
 	for _, rule := range rules {
-		if rule.Port > 0 {
+		if rule.Enabled && rule.Port > 0 {
 			go func(r config.ProxyRule) {
 				addr := fmt.Sprintf(":%d", r.Port)
-				log.Printf("🔗 Port Proxy:     http://localhost:%d -> %s", r.Port, r.TargetURL)
+				log.Printf("🔗 Port Proxy:     http://localhost:%d -> %s", r.Port, strings.Join(r.Targets(), ", "))
 				
 				handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 					proxyManager.ServePortProxy(w, req, r)
 				})
 				
-				if err := http.ListenAndServe(addr, handler); err != nil {
+				// Proxied responses may stream, so the timeouts restart as each part is sent
+				server := timeout.NewServer(cfg, timeout.Extend(handler))
+				server.Addr = addr
+				if err := server.ListenAndServe(); err != nil {
 					log.Printf("Port-based proxy failed on port %d: %v", r.Port, err)
 				}
 			}(rule)
@@ -0,0 +1,611 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"simple.http.server/internal/config"
+)
+
+// newTestServer serves a temporary directory through the same routes as main
+func newTestServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	server, dir, _ := newTestServerWith(t, nil)
+	return server, dir
+}
+
+// newTestServerWith is newTestServer with the given settings added, also returning the config
+func newTestServerWith(t *testing.T, settings map[string]interface{}) (*httptest.Server, string, *config.Config) {
+	t.Helper()
+	dir := t.TempDir()
+	if settings == nil {
+		settings = map[string]interface{}{}
+	}
+	settings["file_server_dir"] = dir
+	data, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings(data); err != nil {
+		t.Fatal(err)
+	}
+	handler, _ := newHandler(cfg, nil, "", nil)
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server, dir, cfg
+}
+
+// doRequest sends a request to the test server and returns the response, closed at cleanup
+func doRequest(t *testing.T, method, url, contentType, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestClipboardRoute(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	resp := doRequest(t, http.MethodPost, server.URL+"/api/clipboard", "application/json", `{"content":"shared text","ttl":5}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST /api/clipboard: status = %d", resp.StatusCode)
+	}
+	var saved struct {
+		ID        string    `json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&saved); err != nil || saved.ID == "" {
+		t.Fatalf("POST /api/clipboard: invalid response: %v", err)
+	}
+	if !saved.ExpiresAt.After(saved.CreatedAt) {
+		t.Errorf("item expires at %s, not after it was created at %s", saved.ExpiresAt, saved.CreatedAt)
+	}
+
+	resp = doRequest(t, http.MethodGet, server.URL+"/api/clipboard?id="+saved.ID, "", "")
+	var item struct {
+		Content string `json:"content"`
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /api/clipboard?id=: status = %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&item); err != nil || item.Content != "shared text" {
+		t.Fatalf("GET /api/clipboard?id=: content = %q, %v", item.Content, err)
+	}
+
+	resp = doRequest(t, http.MethodDelete, server.URL+"/api/clipboard?id="+saved.ID, "", "")
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("DELETE /api/clipboard?id=: status = %d", resp.StatusCode)
+	}
+	resp = doRequest(t, http.MethodGet, server.URL+"/api/clipboard?id="+saved.ID, "", "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET after DELETE: status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestUploadThenSearchRoutes(t *testing.T) {
+	server, dir := newTestServer(t)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("files", "quarterly-report.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write([]byte("numbers"))
+	mw.Close()
+	resp := doRequest(t, http.MethodPost, server.URL+"/api/upload", mw.FormDataContentType(), body.String())
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST /api/upload: status = %d", resp.StatusCode)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "quarterly-report.txt")); err != nil {
+		t.Fatalf("uploaded file not saved: %v", err)
+	}
+
+	resp = doRequest(t, http.MethodGet, server.URL+"/api/search?q=quarterly", "", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /api/search: status = %d", resp.StatusCode)
+	}
+	var found struct {
+		Results []struct {
+			Name string `json:"name"`
+			Path string `json:"path"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&found); err != nil {
+		t.Fatal(err)
+	}
+	if len(found.Results) != 1 || found.Results[0].Name != "quarterly-report.txt" {
+		t.Errorf("search results = %+v, want the uploaded file", found.Results)
+	}
+}
+
+func TestPreviewRoutes(t *testing.T) {
+	server, dir := newTestServer(t)
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/api/preview?path=notes.txt", http.StatusOK},
+		{"/api/preview/notes.txt", http.StatusOK},
+		{"/api/preview/raw?path=notes.txt", http.StatusOK},
+		{"/api/preview/assets/highlight.css", http.StatusOK},
+		{"/api/preview/assets/highlight.min.js", http.StatusOK},
+		{"/api/preview?path=../outside.txt", http.StatusForbidden},
+		{"/api/preview/?path=../../etc/passwd", http.StatusForbidden},
+		{"/api/preview/raw?path=../outside.txt", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		resp := doRequest(t, http.MethodGet, server.URL+tt.path, "", "")
+		if resp.StatusCode != tt.want {
+			t.Errorf("GET %s: status = %d, want %d", tt.path, resp.StatusCode, tt.want)
+		}
+	}
+}
+
+func TestListenHonoursPort(t *testing.T) {
+	// Find a free port, then ask for it explicitly
+	listener, free, err := listen("127.0.0.1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener.Close()
+
+	listener, port, err := listen("127.0.0.1", free)
+	if err != nil {
+		t.Fatalf("listen on port %d: %v", free, err)
+	}
+	defer listener.Close()
+	if port != free {
+		t.Errorf("listening on port %d, want %d", port, free)
+	}
+
+	// A port in use is an error rather than a silent switch to another port
+	if other, _, err := listen("127.0.0.1", free); err == nil {
+		other.Close()
+		t.Errorf("listen on the busy port %d succeeded", free)
+	} else if !strings.Contains(err.Error(), "already in use") {
+		t.Errorf("error = %q, want a hint that the port is in use", err)
+	}
+}
+
+// parseTestFlags parses args with a flag set that reports errors instead of exiting
+func parseTestFlags(t *testing.T, args ...string) (*options, error) {
+	t.Helper()
+	fs := flag.NewFlagSet("simple-http-server", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return parseFlags(fs, args)
+}
+
+func TestDirFlagSetsServedDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "flagged.txt"), []byte("from -dir"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A relative -dir is taken from the working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Dir(dir)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	opts, err := parseTestFlags(t, "-dir", filepath.Base(dir), "-port", "9001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.port != 9001 {
+		t.Errorf("port = %d, want 9001", opts.port)
+	}
+	serveDir, err := opts.serveDir("/cwd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serveDir != dir {
+		t.Errorf("served directory = %q, want %q", serveDir, dir)
+	}
+
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings([]byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	cfg.SetFileServerDir(serveDir)
+	if got := cfg.GetFileServerDir(); got != dir {
+		t.Fatalf("config directory = %q, want %q", got, dir)
+	}
+	handler, _ := newHandler(cfg, opts.mounts, "", nil)
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	resp := doRequest(t, http.MethodGet, server.URL+"/flagged.txt", "", "")
+	if body, _ := io.ReadAll(resp.Body); string(body) != "from -dir" {
+		t.Errorf("GET /flagged.txt = %q, want the file from the -dir directory", body)
+	}
+}
+
+func TestDirFlagDefaultsAndErrors(t *testing.T) {
+	opts, err := parseTestFlags(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir, err := opts.serveDir("/cwd"); err != nil || dir != "/cwd" {
+		t.Errorf("without -dir: served directory = %q, %v, want the working directory", dir, err)
+	}
+
+	file := filepath.Join(t.TempDir(), "file.txt")
+	os.WriteFile(file, []byte("x"), 0644)
+	for _, dir := range []string{file, filepath.Join(t.TempDir(), "missing")} {
+		opts, err := parseTestFlags(t, "-dir", dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := opts.serveDir("/cwd"); err == nil {
+			t.Errorf("-dir %s was accepted", dir)
+		}
+	}
+
+	if _, err := parseTestFlags(t, "-port", "not-a-port"); err == nil {
+		t.Error("an invalid -port was accepted")
+	}
+}
+
+func TestDisabledProxyRuleFallsThrough(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "proxied")
+	}))
+	t.Cleanup(backend.Close)
+	rule := config.ProxyRule{ID: "docs", PathPrefix: "/docs/", TargetURL: backend.URL, Enabled: false}
+	server, dir, cfg := newTestServerWith(t, map[string]interface{}{"proxy_rules": []config.ProxyRule{rule}})
+	if err := os.MkdirAll(filepath.Join(dir, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "docs", "a.txt"), []byte("local file"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	body := func() string {
+		resp := doRequest(t, http.MethodGet, server.URL+"/docs/a.txt", "", "")
+		data, _ := io.ReadAll(resp.Body)
+		return string(data)
+	}
+	if got := body(); got != "local file" {
+		t.Errorf("with the rule disabled, body = %q, want the local file", got)
+	}
+
+	rule.Enabled = true
+	cfg.UpdateProxyRule("docs", rule)
+	if got := body(); got != "proxied" {
+		t.Errorf("with the rule enabled, body = %q, want the proxied response", got)
+	}
+}
+
+func TestChangeServedDirectory(t *testing.T) {
+	server, oldDir := newTestServer(t)
+	newDir := t.TempDir()
+	os.WriteFile(filepath.Join(oldDir, "old.txt"), []byte("old"), 0644)
+	os.WriteFile(filepath.Join(newDir, "new.txt"), []byte("new"), 0644)
+
+	// Follow live updates from before the change
+	events := doRequest(t, http.MethodGet, server.URL+"/events", "", "")
+	lines := make(chan string, 100)
+	go func() {
+		scanner := bufio.NewScanner(events.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	for _, dir := range []string{filepath.Join(newDir, "missing"), filepath.Join(newDir, "new.txt")} {
+		body, _ := json.Marshal(map[string]string{"file_server_dir": dir})
+		if resp := doRequest(t, http.MethodPut, server.URL+"/admin/api/settings", "application/json", string(body)); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("PUT %s: status = %d, want 400", dir, resp.StatusCode)
+		}
+	}
+
+	body, _ := json.Marshal(map[string]string{"file_server_dir": newDir})
+	resp := doRequest(t, http.MethodPut, server.URL+"/admin/api/settings", "application/json", string(body))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("PUT /admin/api/settings: status = %d", resp.StatusCode)
+	}
+
+	if resp := doRequest(t, http.MethodGet, server.URL+"/new.txt", "", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /new.txt: status = %d, want the file from the new directory", resp.StatusCode)
+	}
+	if resp := doRequest(t, http.MethodGet, server.URL+"/old.txt", "", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /old.txt: status = %d, want 404 after the change", resp.StatusCode)
+	}
+
+	// The watcher now follows the new directory
+	time.Sleep(200 * time.Millisecond)
+	os.WriteFile(filepath.Join(newDir, "watched.txt"), []byte("x"), 0644)
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case line := <-lines:
+			if strings.HasPrefix(line, "data: ") && strings.Contains(line, `"path":"/watched.txt"`) {
+				return
+			}
+		case <-timeout:
+			t.Fatal("no change reported for a file created in the new directory")
+		}
+	}
+}
+
+func TestListingGzippedButEventsNot(t *testing.T) {
+	server, dir := newTestServer(t)
+	for i := 0; i < 100; i++ {
+		os.WriteFile(filepath.Join(dir, fmt.Sprintf("file-%03d.txt", i)), []byte("x"), 0644)
+	}
+
+	// Setting Accept-Encoding keeps the client from decompressing the response itself
+	get := func(path string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Accept-Encoding", "gzip")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	if resp := get("/"); resp.Header.Get("Content-Encoding") != "gzip" {
+		t.Errorf("listing: Content-Encoding = %q, want gzip", resp.Header.Get("Content-Encoding"))
+	}
+	if resp := get("/events"); resp.Header.Get("Content-Encoding") != "" {
+		t.Errorf("events: Content-Encoding = %q, want none", resp.Header.Get("Content-Encoding"))
+	}
+}
+
+func TestHealthRoutesTakePrecedence(t *testing.T) {
+	server, dir := newTestServer(t)
+	// A served file with the same name must not shadow the check
+	if err := os.WriteFile(filepath.Join(dir, "healthz"), []byte("a file"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/healthz", "/readyz"} {
+		resp := doRequest(t, http.MethodGet, server.URL+path, "", "")
+		var body map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || resp.StatusCode != http.StatusOK || body["status"] != "ok" {
+			t.Errorf("GET %s: status = %d, body = %v, %v, want an ok health report", path, resp.StatusCode, body, err)
+		}
+	}
+}
+
+func TestVersionDefaultsToDev(t *testing.T) {
+	opts, err := parseTestFlags(t, "-version")
+	if err != nil || !opts.version {
+		t.Fatalf("-version: opts.version = %v, %v, want true", opts != nil && opts.version, err)
+	}
+
+	server, _ := newTestServer(t)
+	resp := doRequest(t, http.MethodGet, server.URL+"/admin/api/version", "", "")
+	var body map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("status = %d: %v", resp.StatusCode, err)
+	}
+	for _, field := range []string{"version", "commit", "build_date"} {
+		if body[field] != "dev" {
+			t.Errorf("%s = %q, want dev when not set at build time", field, body[field])
+		}
+	}
+}
+
+func TestMountsServedAndConfined(t *testing.T) {
+	base := t.TempDir()
+	for name, content := range map[string]string{
+		"docs/guide.txt":  "docs guide",
+		"media/song.txt":  "media song",
+		"outside.txt":     "outside",
+		"served/main.txt": "main",
+	} {
+		path := filepath.Join(base, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts, err := parseTestFlags(t, "-mount", "docs="+filepath.Join(base, "docs"), "-mount", "media="+filepath.Join(base, "media"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings([]byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	cfg.SetFileServerDir(filepath.Join(base, "served"))
+	handler, _ := newHandler(cfg, opts.mounts, "", nil)
+
+	get := func(target string) (int, string) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec.Code, rec.Body.String()
+	}
+	for target, want := range map[string]string{
+		"/mnt/docs/guide.txt": "docs guide",
+		"/mnt/media/song.txt": "media song",
+		"/main.txt":           "main",
+	} {
+		if code, body := get(target); code != http.StatusOK || body != want {
+			t.Errorf("GET %s: status = %d, body = %q, want %q", target, code, body, want)
+		}
+	}
+
+	// The root page lists the mounts
+	if _, body := get("/"); !strings.Contains(body, "/mnt/docs/") || !strings.Contains(body, "/mnt/media/") {
+		t.Error("root page does not list the mounts")
+	}
+
+	for _, target := range []string{
+		"/mnt/docs/..%2fmedia/song.txt",
+		"/mnt/docs/%2e%2e/media/song.txt",
+		"/mnt/docs/..%2f..%2foutside.txt",
+		"/mnt/media/..%5c..%5coutside.txt",
+	} {
+		if code, body := get(target); code == http.StatusOK || body == "media song" || body == "outside" {
+			t.Errorf("GET %s: status = %d, body = %q, want it blocked", target, code, body)
+		}
+	}
+
+	// Bad mount flags are refused
+	for _, args := range [][]string{
+		{"-mount", "docs"},
+		{"-mount", "../x=" + base},
+		{"-mount", "a=" + base, "-mount", "a=" + base},
+		{"-mount", "a=" + filepath.Join(base, "missing")},
+	} {
+		if _, err := parseTestFlags(t, args...); err == nil {
+			t.Errorf("%v was accepted", args)
+		}
+	}
+}
+
+// metricValue returns the value of the metric line starting with name on the server's /metrics page
+func metricValue(t *testing.T, serverURL, name string) string {
+	t.Helper()
+	resp := doRequest(t, http.MethodGet, serverURL+"/metrics", "", "")
+	body, _ := io.ReadAll(resp.Body)
+	for _, line := range strings.Split(string(body), "\n") {
+		if value, ok := strings.CutPrefix(line, name+" "); ok {
+			return value
+		}
+	}
+	t.Fatalf("%s missing from /metrics:\n%s", name, body)
+	return ""
+}
+
+func TestMetricsCountSSEClients(t *testing.T) {
+	server, _ := newTestServer(t)
+	if got := metricValue(t, server.URL, "simple_http_sse_clients"); got != "0" {
+		t.Fatalf("sse clients = %s before any connected, want 0", got)
+	}
+
+	events := doRequest(t, http.MethodGet, server.URL+"/events", "", "")
+	waitForMetric := func(want string) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for metricValue(t, server.URL, "simple_http_sse_clients") != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("sse clients never reached %s", want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitForMetric("1")
+	events.Body.Close()
+	waitForMetric("0")
+
+	if got := metricValue(t, server.URL, `simple_http_requests_total{class="2xx"}`); got == "0" {
+		t.Error("requests served are not counted")
+	}
+}
+
+func TestHomePage(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "docs"), 0755)
+	if err := os.WriteFile(filepath.Join(dir, "docs", "a.txt"), []byte("file a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	homeFile := filepath.Join(t.TempDir(), "home.html")
+	if err := os.WriteFile(homeFile, []byte("<h1>Welcome home</h1>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	homePage, err := resolveHomePage(homeFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{}
+	if err := cfg.ImportSettings([]byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	cfg.SetFileServerDir(dir)
+	handler, _ := newHandler(cfg, nil, homePage, nil)
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	if rec := get("/"); rec.Code != http.StatusOK || rec.Body.String() != "<h1>Welcome home</h1>" {
+		t.Errorf("GET /: status = %d, body = %q, want the home page", rec.Code, rec.Body)
+	}
+	// The listing moves to /files/, with its links following it
+	if body := get("/files/").Body.String(); !strings.Contains(body, `href="/files/docs/"`) {
+		t.Error("GET /files/ is not the listing of the served directory")
+	}
+	if body := get("/files/docs/").Body.String(); !strings.Contains(body, `href="/files/docs/a.txt"`) {
+		t.Error("GET /files/docs/ does not link to its files under /files/")
+	}
+	if rec := get("/files/docs/a.txt"); rec.Body.String() != "file a" {
+		t.Errorf("GET /files/docs/a.txt = %q, want the file", rec.Body)
+	}
+	// Old links are sent to the new place
+	if rec := get("/docs/a.txt?download=1"); rec.Code != http.StatusTemporaryRedirect || rec.Header().Get("Location") != "/files/docs/a.txt?download=1" {
+		t.Errorf("GET /docs/a.txt: status = %d, Location = %q, want a redirect under /files/", rec.Code, rec.Header().Get("Location"))
+	}
+
+	// Without -home the listing stays at the root
+	handler, _ = newHandler(cfg, nil, "", nil)
+	if body := get("/").Body.String(); !strings.Contains(body, `href="/docs/"`) {
+		t.Error("without a home page, GET / is not the listing")
+	}
+
+	for _, bad := range []string{dir, filepath.Join(dir, "missing.html")} {
+		if _, err := resolveHomePage(bad); err == nil {
+			t.Errorf("home page %s was accepted", bad)
+		}
+	}
+}
+
+func TestFaviconRoute(t *testing.T) {
+	server, dir := newTestServer(t)
+
+	resp := doRequest(t, http.MethodGet, server.URL+"/favicon.ico", "", "")
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "image/") {
+		t.Errorf("GET /favicon.ico: status = %d, Content-Type = %q, want an image", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	resp = doRequest(t, http.MethodGet, server.URL+"/", "", "")
+	if body, _ := io.ReadAll(resp.Body); !strings.Contains(string(body), `<link rel="manifest" href="/manifest.webmanifest">`) {
+		t.Error("listing does not reference the manifest")
+	}
+
+	// A favicon in the served directory takes precedence
+	if err := os.WriteFile(filepath.Join(dir, "favicon.ico"), []byte("own icon"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	resp = doRequest(t, http.MethodGet, server.URL+"/favicon.ico", "", "")
+	if body, _ := io.ReadAll(resp.Body); string(body) != "own icon" {
+		t.Errorf("GET /favicon.ico = %q, want the served directory's icon", body)
+	}
+}